@@ -0,0 +1,16 @@
+package main
+
+import "fmt"
+
+var (
+	// errUsage is returned if a command is used incorrectly.
+	errUsage = fmt.Errorf("invalid usage")
+	// errInvalidFlags is returned if the flags of a command can't be parsed.
+	errInvalidFlags = fmt.Errorf("invalid flags")
+	// errUnknownFormat is returned if an output format is unknown.
+	errUnknownFormat = fmt.Errorf("unknown format")
+	// errInvalidProof is returned if a proof is invalid.
+	errInvalidProof = fmt.Errorf("invalid proof")
+	// errDecodeWitness is returned if a witness can't be decoded.
+	errDecodeWitness = fmt.Errorf("unable to decode witness")
+)
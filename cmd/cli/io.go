@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// stdio is the path that refers to stdin.
+const stdio = "-"
+
+// format is the form an object is written in.
+type format string
+
+const (
+	// formatText is the armored text form.
+	formatText format = "text"
+	// formatJSON is the JSON form.
+	formatJSON format = "json"
+	// formatBinary is the binary form.
+	formatBinary format = "binary"
+)
+
+// String returns the name of the format.
+func (f *format) String() string {
+	return string(*f)
+}
+
+// Set sets the format via its name.
+// Returns an error if the format is unknown.
+func (f *format) Set(s string) error {
+	switch format(s) {
+	case formatText, formatJSON, formatBinary:
+		*f = format(s)
+		return nil
+	default:
+		return errUnknownFormat
+	}
+}
+
+// decodable is an object that can be decoded from all its forms.
+type decodable interface {
+	encoding.BinaryUnmarshaler
+	encoding.TextUnmarshaler
+	json.Unmarshaler
+}
+
+// encodable is an object that can be encoded into all its forms.
+type encodable interface {
+	encoding.BinaryMarshaler
+	encoding.TextMarshaler
+	json.Marshaler
+}
+
+// listFlag is a flag that can be set multiple times.
+type listFlag []string
+
+// String returns the comma-separated values of the flag.
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set appends the value to the flag's values.
+func (l *listFlag) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// outputFlags registers the flags that select where and in which form objects
+// are written.
+func outputFlags(fs *flag.FlagSet) (*string, *format) {
+	out := fs.String("out", "", "output `file` (defaults to stdout)")
+	f := formatText
+	fs.Var(&f, "format", "output `format` (text, json or binary)")
+
+	return out, &f
+}
+
+// readFile reads the file at the path (or stdin if the path is "-").
+// Returns an error if the path is empty or if the file can't be read.
+func readFile(e *env, path string) ([]byte, error) {
+	if path == "" {
+		return nil, fmt.Errorf("%w: missing input file", errUsage)
+	}
+
+	if path == stdio {
+		return io.ReadAll(e.stdin)
+	}
+
+	return os.ReadFile(path)
+}
+
+// writeFile writes the data to the file at the path (or stdout if the path is
+// empty).
+// Returns an error if the file can't be written.
+func writeFile(e *env, path string, data []byte) error {
+	if path == "" {
+		_, err := e.stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// readObject reads the file at the path (or stdin if the path is "-") and
+// decodes the object from its binary, JSON or armored text form.
+// Returns an error if the file can't be read or decoded.
+func readObject(e *env, path string, v decodable) error {
+	data, err := readFile(e, path)
+	if err != nil {
+		return err
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("-----BEGIN ")):
+		err = v.UnmarshalText(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		err = v.UnmarshalJSON(trimmed)
+	default:
+		err = v.UnmarshalBinary(data)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	return nil
+}
+
+// writeObject encodes the object in the format and writes it to the file at
+// the path (or stdout if the path is empty).
+// Returns an error if the object can't be encoded or written.
+func writeObject(e *env, path string, f format, v encodable) error {
+	var data []byte
+	var err error
+	switch f {
+	case formatJSON:
+		data, err = v.MarshalJSON()
+		data = append(data, '\n')
+	case formatBinary:
+		data, err = v.MarshalBinary()
+	default:
+		data, err = v.MarshalText()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return writeFile(e, path, data)
+}
+
+// readParams reads the protocol parameters from the file at the path and
+// validates them.
+// Returns an error if the protocol parameters can't be read or are invalid.
+func readParams(e *env, path string) (*params.Params, error) {
+	var p params.Params
+	if err := readObject(e, path, &p); err != nil {
+		return nil, err
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	return &p, nil
+}
+
+// readPuzzles reads the puzzles from the files at the paths.
+// Returns an error if a puzzle can't be read.
+func readPuzzles(e *env, paths []string) ([]*puzzle.Puzzle, error) {
+	puzzles := make([]*puzzle.Puzzle, len(paths))
+	for i, path := range paths {
+		var z puzzle.Puzzle
+		if err := readObject(e, path, &z); err != nil {
+			return nil, err
+		}
+		puzzles[i] = &z
+	}
+
+	return puzzles, nil
+}
+
+// checkStdin checks that at most one of the paths refers to stdin.
+// Returns an error if stdin is used more than once.
+func checkStdin(paths ...string) error {
+	n := 0
+	for _, path := range paths {
+		if path == stdio {
+			n++
+		}
+	}
+
+	if n > 1 {
+		return fmt.Errorf("%w: stdin can only be read once", errUsage)
+	}
+
+	return nil
+}
+
+// displayPath returns the path in a form that's suitable for error messages.
+func displayPath(path string) string {
+	if path == stdio {
+		return "stdin"
+	}
+
+	return path
+}
+
+// parseBigInt parses a decimal (or "0x" prefixed hex) integer.
+// Returns an error if the value is missing or isn't an integer.
+func parseBigInt(name, s string) (*big.Int, error) {
+	if s == "" {
+		return nil, fmt.Errorf("%w: missing -%s", errUsage, name)
+	}
+
+	x, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid -%s %q", errUsage, name, s)
+	}
+
+	return x, nil
+}
@@ -1,7 +1,170 @@
+/*
+Command lhtlp generates protocol parameters, creates, solves and combines
+puzzles, creates and verifies Range proofs and serves the HTTP/JSON API of
+the server package and the JSON-RPC 2.0 API of the rpc package.
+
+Usage:
+
+	lhtlp <command> [<subcommand>...] [flags] [args]
+
+Objects are read from files (or from stdin if the path is "-") in their binary,
+JSON or armored text form which is detected automatically and written to files
+(or to stdout if no path is given) in the form that's selected via the -format
+flag (which defaults to the armored text form). Run "lhtlp help" to list all
+commands and "lhtlp <command> -h" to list the flags of a command.
+*/
 package main
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// command is an instance of a (sub)command of the CLI.
+type command struct {
+	// name is the space-separated path of the command (e.g. "puzzle create").
+	name string
+	// args describes the positional arguments of the command.
+	args string
+	// summary is a one-line description of the command.
+	summary string
+	// run runs the command with the flag set (which the command populates
+	// before parsing the arguments) and the arguments that follow the
+	// command's name.
+	run func(env *env, fs *flag.FlagSet, args []string) error
+}
+
+// env is the environment a command runs in.
+type env struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// commands contains all commands of the CLI.
+var commands = []*command{
+	paramsGenerateCommand,
+	puzzleCreateCommand,
+	puzzleSolveCommand,
+	puzzleAddCommand,
+	puzzleMulCommand,
+	proofRangeCreateCommand,
+	proofRangeVerifyCommand,
+	serveCommand,
+	rpcCommand,
+}
 
 func main() {
-	fmt.Printf("Hello %s\n", "World")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command that's selected via the arguments and returns the exit
+// code which is 0 on success, 1 if the command failed and 2 if it was used
+// incorrectly.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd, rest := lookupCommand(args)
+	if cmd == nil {
+		if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+			printUsage(stdout)
+			return 0
+		}
+
+		fmt.Fprintf(stderr, "lhtlp: unknown command %q\n\n", strings.Join(args, " "))
+		printUsage(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("lhtlp "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		usage := strings.TrimSpace("lhtlp " + cmd.name + " [flags] " + cmd.args)
+		fmt.Fprintf(stderr, "Usage: %s\n\n%s\n\nFlags:\n", usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	e := &env{
+		ctx:    ctx,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}
+
+	err := cmd.run(e, fs, rest)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errInvalidFlags):
+		// The flag package already printed the error and the usage.
+		return 2
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "lhtlp %s: %v\n", cmd.name, err)
+		fs.Usage()
+		return 2
+	default:
+		fmt.Fprintf(stderr, "lhtlp %s: %v\n", cmd.name, err)
+		return 1
+	}
+}
+
+// lookupCommand returns the command with the longest name that matches the
+// leading arguments alongside the remaining arguments.
+func lookupCommand(args []string) (*command, []string) {
+	var found *command
+	depth := 0
+	for _, cmd := range commands {
+		path := strings.Fields(cmd.name)
+		if len(path) <= depth || len(path) > len(args) {
+			continue
+		}
+
+		if strings.Join(args[:len(path)], " ") == cmd.name {
+			found = cmd
+			depth = len(path)
+		}
+	}
+
+	return found, args[depth:]
+}
+
+// printUsage prints the list of all commands to w.
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: lhtlp <command> [flags] [args]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-20s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nRun \"lhtlp <command> -h\" to list the flags of a command.\n")
+}
+
+// parseFlags parses the arguments via the flag set and checks that the number
+// of positional arguments is within [minArgs, maxArgs] (maxArgs < 0 means
+// unlimited).
+// Returns an error if the arguments can't be parsed or if the number of
+// positional arguments is invalid.
+func parseFlags(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %v", errInvalidFlags, err)
+	}
+
+	n := fs.NArg()
+	if n < minArgs || (maxArgs >= 0 && n > maxArgs) {
+		return fmt.Errorf("%w: unexpected number of arguments", errUsage)
+	}
+
+	return nil
 }
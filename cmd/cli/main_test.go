@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+)
+
+// cli runs the CLI with the arguments and stdin and returns the exit code,
+// stdout and stderr.
+func cli(t *testing.T, stdin []byte, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, bytes.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+// mustCLI runs the CLI like cli and fails the test if the command fails.
+func mustCLI(t *testing.T, stdin []byte, args ...string) string {
+	t.Helper()
+
+	code, stdout, stderr := cli(t, stdin, args...)
+	if code != 0 {
+		t.Fatalf("want exit code %v, got %v (%s)", 0, code, stderr)
+	}
+
+	return stdout
+}
+
+func TestCLI(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	paramsPath := filepath.Join(dir, "params.pem")
+
+	mustCLI(t, nil, "params", "generate", "-bits", "256", "-t", "1000", "-out", paramsPath)
+
+	t.Run("Generate Params With Safe Primes", func(t *testing.T) {
+		t.Parallel()
+
+		result := mustCLI(t, nil, "params", "generate", "-bits", "128", "-t", "1000", "-safe-primes", "-format", "json")
+
+		if !strings.Contains(result, `"modulus":"safe-primes"`) {
+			t.Errorf("want safe-prime params, got %v", result)
+		}
+	})
+
+	t.Run("Error when params generation is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var stdout, stderr bytes.Buffer
+		code := run(ctx, []string{"params", "generate", "-bits", "2048", "-t", "1000"}, nil, &stdout, &stderr)
+
+		if code != 1 || !strings.Contains(stderr.String(), context.Canceled.Error()) {
+			t.Errorf("want exit code %v and error %v, got %v (%s)", 1, context.Canceled, code, stderr.String())
+		}
+	})
+
+	t.Run("Create / Add / Mul / Solve", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		path1 := filepath.Join(dir, "1.json")
+		path2 := filepath.Join(dir, "2.bin")
+
+		mustCLI(t, nil, "puzzle", "create", "-params", paramsPath, "-value", "5", "-format", "json", "-out", path1)
+		mustCLI(t, nil, "puzzle", "create", "-params", paramsPath, "-value", "7", "-format", "binary", "-out", path2)
+
+		sum := mustCLI(t, nil, "puzzle", "add", "-params", paramsPath, "-value", "3", path1, path2)
+		product := mustCLI(t, []byte(sum), "puzzle", "mul", "-params", paramsPath, "-value", "2")
+		result := mustCLI(t, []byte(product), "puzzle", "solve", "-params", paramsPath)
+
+		if result != "30\n" {
+			t.Errorf("want %v, got %v", "30", result)
+		}
+	})
+
+	t.Run("Range Proof", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		puzzle1 := filepath.Join(dir, "1.pem")
+		puzzle2 := filepath.Join(dir, "2.pem")
+		witness1 := filepath.Join(dir, "1.json")
+		witness2 := filepath.Join(dir, "2.json")
+		proofPath := filepath.Join(dir, "proof.pem")
+
+		mustCLI(t, nil, "puzzle", "create", "-params", paramsPath, "-value", "5", "-witness", witness1, "-out", puzzle1)
+		mustCLI(t, nil, "puzzle", "create", "-params", paramsPath, "-value", "7", "-witness", witness2, "-out", puzzle2)
+
+		mustCLI(t, nil, "proof", "range", "create", "-params", paramsPath, "-q", "100",
+			"-puzzle", puzzle1, "-witness", witness1, "-puzzle", puzzle2, "-witness", witness2, "-out", proofPath)
+
+		proof, _ := os.ReadFile(proofPath)
+		result := mustCLI(t, proof, "proof", "range", "verify", "-params", paramsPath, "-q", "100", "-puzzle", puzzle1, "-puzzle", puzzle2)
+
+		if result != "valid\n" {
+			t.Errorf("want %v, got %v", "valid", result)
+		}
+
+		// Swapping the puzzles invalidates the proof.
+		code, _, stderr := cli(t, nil, "proof", "range", "verify", "-params", paramsPath, "-q", "100", "-puzzle", puzzle2, "-puzzle", puzzle1, proofPath)
+
+		if code != 1 || !strings.Contains(stderr, errInvalidProof.Error()) {
+			t.Errorf("want exit code %v and error %v, got %v (%s)", 1, errInvalidProof, code, stderr)
+		}
+
+		// A proof for fewer bits is malformed.
+		shortProof := filepath.Join(dir, "short.pem")
+		mustCLI(t, nil, "proof", "range", "create", "-params", paramsPath, "-q", "100", "-bits", "8",
+			"-puzzle", puzzle1, "-witness", witness1, "-out", shortProof)
+
+		code, _, stderr = cli(t, nil, "proof", "range", "verify", "-params", paramsPath, "-q", "100", "-puzzle", puzzle1, shortProof)
+
+		if code != 1 || !strings.Contains(stderr, proofs.ErrInvalidProof.Error()) {
+			t.Errorf("want exit code %v and error %v, got %v (%s)", 1, proofs.ErrInvalidProof, code, stderr)
+		}
+	})
+
+	t.Run("Serve", func(t *testing.T) {
+		t.Parallel()
+
+		// The server shuts down once the context is canceled.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var stdout, stderr bytes.Buffer
+		code := run(ctx, []string{"serve", "-params", paramsPath, "-addr", "127.0.0.1:0"}, nil, &stdout, &stderr)
+
+		if code != 0 || !strings.Contains(stderr.String(), "listening on") {
+			t.Errorf("want exit code %v, got %v (%s)", 0, code, stderr.String())
+		}
+	})
+
+	t.Run("RPC", func(t *testing.T) {
+		t.Parallel()
+
+		request := `{"jsonrpc":"2.0","id":1,"method":"request.cancel","params":{"id":2}}` + "\n"
+		result := mustCLI(t, []byte(request), "rpc")
+
+		want := `{"jsonrpc":"2.0","result":{"canceled":false},"id":1}` + "\n"
+		if result != want {
+			t.Errorf("want %v, got %v", want, result)
+		}
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		t.Parallel()
+
+		for _, args := range [][]string{nil, {"help"}, {"puzzle", "create", "-h"}} {
+			if code, _, _ := cli(t, nil, args...); code != 0 {
+				t.Errorf("want exit code %v, got %v (%v)", 0, code, args)
+			}
+		}
+	})
+
+	t.Run("Error when usage is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name string
+			args []string
+		}{
+			{"Unknown Command", []string{"puzzle", "foo"}},
+			{"Negative Difficulty", []string{"params", "generate", "-bits", "64", "-t", "-1"}},
+			{"Zero Difficulty", []string{"params", "generate", "-bits", "64", "-t", "0"}},
+			{"Exponent y < 2", []string{"params", "generate", "-bits", "64", "-t", "1000", "-y", "1"}},
+			{"Unknown Flag", []string{"puzzle", "create", "-foo"}},
+			{"Unknown Format", []string{"puzzle", "create", "-format", "yaml"}},
+			{"Missing Value", []string{"puzzle", "create", "-params", paramsPath}},
+			{"Invalid Value", []string{"puzzle", "create", "-params", paramsPath, "-value", "five"}},
+			{"Missing Params", []string{"puzzle", "create", "-value", "5"}},
+			{"Missing Puzzles", []string{"puzzle", "add", "-params", paramsPath}},
+			{"Stdin Twice", []string{"puzzle", "add", "-params", "-", "-", "-"}},
+			{"Serve Without Params", []string{"serve"}},
+			{"Zero Proof Bits", []string{"proof", "range", "verify", "-params", paramsPath, "-q", "100", "-bits", "0", "-puzzle", paramsPath}},
+			{"Missing Witness", []string{"proof", "range", "create", "-params", paramsPath, "-q", "100", "-puzzle", paramsPath}},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				if code, _, _ := cli(t, nil, tc.args...); code != 2 {
+					t.Errorf("want exit code %v, got %v", 2, code)
+				}
+			})
+		}
+	})
+
+	t.Run("Error when input is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		code, _, stderr := cli(t, []byte("garbage"), "puzzle", "solve", "-params", paramsPath)
+
+		if code != 1 || !strings.Contains(stderr, "stdin") {
+			t.Errorf("want exit code %v and an error that names stdin, got %v (%s)", 1, code, stderr)
+		}
+	})
+}
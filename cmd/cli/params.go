@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+// paramsGenerateCommand generates protocol parameters.
+var paramsGenerateCommand = &command{
+	name:    "params generate",
+	summary: "Generate protocol parameters",
+	run:     runParamsGenerate,
+}
+
+// runParamsGenerate runs the "params generate" command.
+func runParamsGenerate(e *env, fs *flag.FlagSet, args []string) error {
+	bits := fs.Int("bits", 2048, "bit length of the modulus n")
+	y := fs.Int("y", 2, "exponent y of the message space n^(y - 1)")
+	t := fs.String("t", "", "difficulty t (number of sequential squarings)")
+	safePrimes := fs.Bool("safe-primes", false, "use safe primes p = 2p' + 1 and q = 2q' + 1 (slower)")
+	out, f := outputFlags(fs)
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	difficulty, err := parseBigInt("t", *t)
+	if err != nil {
+		return err
+	}
+
+	if difficulty.Sign() <= 0 {
+		return fmt.Errorf("%w: -t must be positive", errUsage)
+	}
+
+	if *y < 2 {
+		return fmt.Errorf("%w: -y must be at least 2", errUsage)
+	}
+
+	opts := &params.GenerateOptions{SafePrimes: *safePrimes}
+
+	p, err := params.GenerateParamsContext(e.ctx, *bits, *y, difficulty, opts)
+	if err != nil {
+		return err
+	}
+
+	return writeObject(e, *out, *f, p)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// witnessVersion is the version of the witness JSON representation.
+const witnessVersion = 1
+
+// witnessJSON is the JSON representation of a witness (a puzzle's plaintext
+// value and nonce) which uses the same canonical hex encoding as the library's
+// JSON forms.
+type witnessJSON struct {
+	Version int    `json:"version"`
+	X       string `json:"x"`
+	R       string `json:"r"`
+}
+
+// proofRangeCreateCommand creates a Range proof.
+var proofRangeCreateCommand = &command{
+	name:    "proof range create",
+	summary: "Create a Range proof for puzzles",
+	run:     runProofRangeCreate,
+}
+
+// proofRangeVerifyCommand verifies a Range proof.
+var proofRangeVerifyCommand = &command{
+	name:    "proof range verify",
+	args:    "[<proof>]",
+	summary: "Verify a Range proof for puzzles",
+	run:     runProofRangeVerify,
+}
+
+// runProofRangeCreate runs the "proof range create" command.
+func runProofRangeCreate(e *env, fs *flag.FlagSet, args []string) error {
+	paramsPath := fs.String("params", "", "protocol parameters `file` (\"-\" for stdin)")
+	bits := fs.Int("bits", 128, "security parameter of the proof")
+	q := fs.String("q", "", "upper `bound` q of the plaintext values")
+	var puzzlePaths, witnessPaths listFlag
+	fs.Var(&puzzlePaths, "puzzle", "puzzle `file` (repeatable)")
+	fs.Var(&witnessPaths, "witness", "witness `file` of the puzzle at the same position (repeatable)")
+	out, f := outputFlags(fs)
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	if *bits < 1 {
+		return fmt.Errorf("%w: -bits must be positive", errUsage)
+	}
+
+	if len(puzzlePaths) == 0 || len(puzzlePaths) != len(witnessPaths) {
+		return fmt.Errorf("%w: every -puzzle needs a -witness", errUsage)
+	}
+
+	if err := checkStdin(append(puzzlePaths, *paramsPath)...); err != nil {
+		return err
+	}
+
+	bound, err := parseBigInt("q", *q)
+	if err != nil {
+		return err
+	}
+
+	p, err := readParams(e, *paramsPath)
+	if err != nil {
+		return err
+	}
+
+	puzzles, err := readPuzzles(e, puzzlePaths)
+	if err != nil {
+		return err
+	}
+
+	wit := make([]*proofs.PuzzleValues, len(witnessPaths))
+	for i, path := range witnessPaths {
+		if wit[i], err = readWitness(path); err != nil {
+			return err
+		}
+	}
+
+	proof, err := proofs.GenerateRangeProof(*bits, p, puzzles, bound, wit)
+	if err != nil {
+		return err
+	}
+
+	return writeObject(e, *out, *f, proof)
+}
+
+// runProofRangeVerify runs the "proof range verify" command.
+func runProofRangeVerify(e *env, fs *flag.FlagSet, args []string) error {
+	paramsPath := fs.String("params", "", "protocol parameters `file` (\"-\" for stdin)")
+	bits := fs.Int("bits", 128, "security parameter of the proof")
+	q := fs.String("q", "", "upper `bound` q of the plaintext values")
+	var puzzlePaths listFlag
+	fs.Var(&puzzlePaths, "puzzle", "puzzle `file` (repeatable)")
+
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	if *bits < 1 {
+		return fmt.Errorf("%w: -bits must be positive", errUsage)
+	}
+
+	if len(puzzlePaths) == 0 {
+		return fmt.Errorf("%w: missing -puzzle", errUsage)
+	}
+
+	proofPath := fs.Arg(0)
+	if proofPath == "" {
+		proofPath = stdio
+	}
+
+	if err := checkStdin(append(puzzlePaths, *paramsPath, proofPath)...); err != nil {
+		return err
+	}
+
+	bound, err := parseBigInt("q", *q)
+	if err != nil {
+		return err
+	}
+
+	p, err := readParams(e, *paramsPath)
+	if err != nil {
+		return err
+	}
+
+	puzzles, err := readPuzzles(e, puzzlePaths)
+	if err != nil {
+		return err
+	}
+
+	var proof proofs.RangeProof
+	if err := readObject(e, proofPath, &proof); err != nil {
+		return err
+	}
+
+	valid, err := proofs.VerifyRangePoof(&proof, *bits, p, puzzles, bound)
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		return errInvalidProof
+	}
+
+	fmt.Fprintln(e.stdout, "valid")
+
+	return nil
+}
+
+// writeWitness writes the witness to the file at the path. The file is only
+// readable by its owner as the witness reveals the plaintext value.
+// Returns an error if the file can't be written.
+func writeWitness(path string, wit *proofs.PuzzleValues) error {
+	data, err := json.Marshal(&witnessJSON{
+		Version: witnessVersion,
+		X:       utils.BigIntToHex(wit.X),
+		R:       utils.BigIntToHex(wit.R),
+	})
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// readWitness reads the witness from the file at the path.
+// Returns an error if the file can't be read or decoded.
+func readWitness(path string) (*proofs.PuzzleValues, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var v witnessJSON
+	if err := utils.DecodeJSON(data, &v); err != nil || v.Version != witnessVersion {
+		return nil, fmt.Errorf("%s: %w", path, errDecodeWitness)
+	}
+
+	x, err := utils.HexToBigInt(v.X)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, errDecodeWitness)
+	}
+
+	r, err := utils.HexToBigInt(v.R)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, errDecodeWitness)
+	}
+
+	return proofs.NewPuzzleValues(x, r), nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/primefactor-io/lhtlp/pkg/homomorphic"
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// puzzleCreateCommand creates a puzzle.
+var puzzleCreateCommand = &command{
+	name:    "puzzle create",
+	summary: "Create a puzzle that hides a plaintext value",
+	run:     runPuzzleCreate,
+}
+
+// puzzleSolveCommand solves a puzzle.
+var puzzleSolveCommand = &command{
+	name:    "puzzle solve",
+	args:    "[<puzzle>]",
+	summary: "Solve a puzzle and print its plaintext value",
+	run:     runPuzzleSolve,
+}
+
+// puzzleAddCommand adds puzzles.
+var puzzleAddCommand = &command{
+	name:    "puzzle add",
+	args:    "<puzzle>...",
+	summary: "Add the plaintext values of puzzles (and an optional constant)",
+	run:     runPuzzleAdd,
+}
+
+// puzzleMulCommand multiplies a puzzle.
+var puzzleMulCommand = &command{
+	name:    "puzzle mul",
+	args:    "[<puzzle>]",
+	summary: "Multiply the plaintext value of a puzzle by a constant",
+	run:     runPuzzleMul,
+}
+
+// runPuzzleCreate runs the "puzzle create" command.
+func runPuzzleCreate(e *env, fs *flag.FlagSet, args []string) error {
+	paramsPath := fs.String("params", "", "protocol parameters `file` (\"-\" for stdin)")
+	value := fs.String("value", "", "plaintext `value` to hide")
+	witnessPath := fs.String("witness", "", "`file` to write the witness (plaintext value and nonce) for proofs to")
+	out, f := outputFlags(fs)
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	plaintext, err := parseBigInt("value", *value)
+	if err != nil {
+		return err
+	}
+
+	p, err := readParams(e, *paramsPath)
+	if err != nil {
+		return err
+	}
+
+	z, nonce, err := puzzle.GeneratePuzzleAndReturnNonce(p, plaintext)
+	if err != nil {
+		return err
+	}
+
+	if *witnessPath != "" {
+		if err := writeWitness(*witnessPath, proofs.NewPuzzleValues(plaintext, nonce)); err != nil {
+			return err
+		}
+	}
+
+	return writeObject(e, *out, *f, z)
+}
+
+// runPuzzleSolve runs the "puzzle solve" command.
+func runPuzzleSolve(e *env, fs *flag.FlagSet, args []string) error {
+	paramsPath := fs.String("params", "", "protocol parameters `file` (\"-\" for stdin)")
+	progress := fs.Bool("progress", false, "report the progress on stderr")
+	out := fs.String("out", "", "output `file` (defaults to stdout)")
+
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	puzzlePath := fs.Arg(0)
+	if puzzlePath == "" {
+		puzzlePath = stdio
+	}
+
+	if err := checkStdin(*paramsPath, puzzlePath); err != nil {
+		return err
+	}
+
+	p, err := readParams(e, *paramsPath)
+	if err != nil {
+		return err
+	}
+
+	puzzles, err := readPuzzles(e, []string{puzzlePath})
+	if err != nil {
+		return err
+	}
+
+	var opts *puzzle.SolveOptions
+	if *progress {
+		opts = &puzzle.SolveOptions{
+			OnProgress: func(p puzzle.Progress) {
+				fmt.Fprintf(e.stderr, "%v / %v squarings (elapsed %v, remaining %v)\n", p.Squarings, p.Total, p.Elapsed, p.Remaining)
+			},
+		}
+	}
+
+	plaintext, err := puzzle.SolvePuzzleContext(e.ctx, p, puzzles[0], opts)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(e, *out, []byte(plaintext.String()+"\n"))
+}
+
+// runPuzzleAdd runs the "puzzle add" command.
+func runPuzzleAdd(e *env, fs *flag.FlagSet, args []string) error {
+	paramsPath := fs.String("params", "", "protocol parameters `file` (\"-\" for stdin)")
+	value := fs.String("value", "", "plaintext `value` to add to the sum")
+	out, f := outputFlags(fs)
+
+	if err := parseFlags(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	if err := checkStdin(append(fs.Args(), *paramsPath)...); err != nil {
+		return err
+	}
+
+	p, err := readParams(e, *paramsPath)
+	if err != nil {
+		return err
+	}
+
+	puzzles, err := readPuzzles(e, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	z, err := homomorphic.AddPlaintextValues(p, puzzles...)
+	if err != nil {
+		return err
+	}
+
+	if *value != "" {
+		c, err := parseBigInt("value", *value)
+		if err != nil {
+			return err
+		}
+
+		z, err = homomorphic.AddPlaintextValue(p, z, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeObject(e, *out, *f, z)
+}
+
+// runPuzzleMul runs the "puzzle mul" command.
+func runPuzzleMul(e *env, fs *flag.FlagSet, args []string) error {
+	paramsPath := fs.String("params", "", "protocol parameters `file` (\"-\" for stdin)")
+	value := fs.String("value", "", "plaintext `value` to multiply by")
+	out, f := outputFlags(fs)
+
+	if err := parseFlags(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	puzzlePath := fs.Arg(0)
+	if puzzlePath == "" {
+		puzzlePath = stdio
+	}
+
+	if err := checkStdin(*paramsPath, puzzlePath); err != nil {
+		return err
+	}
+
+	c, err := parseBigInt("value", *value)
+	if err != nil {
+		return err
+	}
+
+	p, err := readParams(e, *paramsPath)
+	if err != nil {
+		return err
+	}
+
+	puzzles, err := readPuzzles(e, []string{puzzlePath})
+	if err != nil {
+		return err
+	}
+
+	z, err := homomorphic.MultiplyPlaintextValue(p, puzzles[0], c)
+	if err != nil {
+		return err
+	}
+
+	return writeObject(e, *out, *f, z)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+
+	"github.com/primefactor-io/lhtlp/pkg/rpc"
+)
+
+// rpcCommand runs the JSON-RPC 2.0 server.
+var rpcCommand = &command{
+	name:    "rpc",
+	summary: "Serve JSON-RPC 2.0 over stdin/stdout or TCP",
+	run:     runRPC,
+}
+
+// runRPC runs the "rpc" command.
+func runRPC(e *env, fs *flag.FlagSet, args []string) error {
+	listen := fs.String("listen", "", "TCP `address` to listen on (serves stdin/stdout if not set)")
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	srv := rpc.NewServer(nil)
+
+	if *listen == "" {
+		err := srv.ServeConn(e.ctx, e.stdin, e.stdout)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stderr, "listening on %s\n", ln.Addr())
+
+	if err := srv.Serve(e.ctx, ln); !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return nil
+}
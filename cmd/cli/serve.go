@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/server"
+)
+
+// shutdownTimeout is the time in-flight requests get to complete once the
+// server is shut down.
+const shutdownTimeout = 10 * time.Second
+
+// serveCommand runs the HTTP/JSON service.
+var serveCommand = &command{
+	name:    "serve",
+	summary: "Serve the HTTP/JSON API for protocol parameters",
+	run:     runServe,
+}
+
+// runServe runs the "serve" command.
+func runServe(e *env, fs *flag.FlagSet, args []string) error {
+	paramsPath := fs.String("params", "", "protocol parameters `file` (\"-\" for stdin)")
+	addr := fs.String("addr", "localhost:8080", "`address` to listen on")
+	workers := fs.Int("workers", 0, "number of puzzles that are solved concurrently (defaults to the number of CPUs)")
+	maxJobs := fs.Int("max-jobs", server.DefaultMaxJobs, "maximum number of queued or running jobs")
+	jobRetention := fs.Duration("job-retention", server.DefaultJobRetention, "`duration` for which finished jobs are kept")
+
+	if err := parseFlags(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	p, err := readParams(e, *paramsPath)
+	if err != nil {
+		return err
+	}
+
+	srv, err := server.NewServer(p, &server.Options{
+		Workers:      *workers,
+		MaxJobs:      *maxJobs,
+		JobRetention: *jobRetention,
+	})
+	if err != nil {
+		return err
+	}
+	defer srv.Close()
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+
+	hs := &http.Server{
+		Handler:           srv,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- hs.Serve(ln)
+	}()
+
+	fmt.Fprintf(e.stderr, "listening on %s\n", ln.Addr())
+
+	select {
+	case err := <-errs:
+		return err
+	case <-e.ctx.Done():
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := hs.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
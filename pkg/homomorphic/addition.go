@@ -3,12 +3,50 @@ package homomorphic
 import (
 	"math/big"
 
+	"github.com/primefactor-io/lhtlp/pkg/internal/scheme"
 	"github.com/primefactor-io/lhtlp/pkg/params"
 	"github.com/primefactor-io/lhtlp/pkg/puzzle"
 )
 
 // AddPlaintextValues adds the plaintext values that were hidden in the puzzles.
-func AddPlaintextValues(params *params.Params, puzzles ...*puzzle.Puzzle) *puzzle.Puzzle {
+// Returns an error if no puzzles are passed in or if the protocol parameters or
+// a puzzle are invalid.
+func AddPlaintextValues(params *params.Params, puzzles ...*puzzle.Puzzle) (*puzzle.Puzzle, error) {
+	if len(puzzles) == 0 {
+		return nil, ErrNoPuzzles
+	}
+
+	if err := validate(params, puzzles...); err != nil {
+		return nil, err
+	}
+
+	return addPlaintextValues(params, puzzles...), nil
+}
+
+// AddPlaintextValue adds the plaintext value to the value that is hidden in the
+// puzzle.
+// Returns an error if the protocol parameters or the puzzle are invalid or if
+// the plaintext value is missing.
+func AddPlaintextValue(params *params.Params, z *puzzle.Puzzle, p *big.Int) (*puzzle.Puzzle, error) {
+	if err := validate(params, z); err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, ErrMissingValue
+	}
+
+	u, v := scheme.AddPlaintextValue(params, z.U, z.V, p)
+
+	puzzle := puzzle.NewPuzzle(u, v)
+
+	return puzzle, nil
+}
+
+// addPlaintextValues adds the plaintext values that were hidden in the puzzles.
+// Note: The caller needs to ensure that the protocol parameters and the
+// puzzles are valid.
+func addPlaintextValues(params *params.Params, puzzles ...*puzzle.Puzzle) *puzzle.Puzzle {
 	var u *big.Int
 	var v *big.Int
 
@@ -28,32 +66,22 @@ func AddPlaintextValues(params *params.Params, puzzles ...*puzzle.Puzzle) *puzzl
 		v = new(big.Int).Mod(in2, params.NExpY) // v_a * v_b mod n^y
 	}
 
-	puzzle := puzzle.NewPuzzle(u, v)
-
-	return puzzle
+	return puzzle.NewPuzzle(u, v)
 }
 
-// AddPlaintextValue adds the plaintext value to the value that is hidden in the
-// puzzle.
-func AddPlaintextValue(params *params.Params, z *puzzle.Puzzle, p *big.Int) *puzzle.Puzzle {
-	// Compute u'.
-	uPrime := new(big.Int).Exp(params.G, p, params.N) // g^p mod n
-
-	// Compute v'.
-	in1 := new(big.Int).Mul(p, params.NExpYMinusOne)     // p * n^(y - 1)
-	in2 := new(big.Int).Exp(params.H, in1, params.NExpY) // h^(p * n^(y - 1)) mod n^y
-	in3 := new(big.Int).Add(big.NewInt(1), params.N)     // 1 + n
-	in4 := new(big.Int).Exp(in3, p, params.NExpY)        // (1 + n)^p mod n^y
-	in5 := new(big.Int).Mul(in2, in4)                    // h^(p * n^(y - 1)) * (1 + n)^p
-	vPrime := new(big.Int).Mod(in5, params.NExpY)        // h^(p * n^(y - 1)) * (1 + n)^p mod n^y
-
-	in6 := new(big.Int).Mul(z.U, uPrime) // u * u'
-	u := new(big.Int).Mod(in6, params.N) // u * u' mod n
-
-	in7 := new(big.Int).Mul(z.V, vPrime)     // v * v'
-	v := new(big.Int).Mod(in7, params.NExpY) // v * v' mod n^y
+// validate checks if the protocol parameters are valid and if the puzzles are
+// well-formed with respect to them.
+// Returns an error if the protocol parameters or a puzzle are invalid.
+func validate(params *params.Params, puzzles ...*puzzle.Puzzle) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
 
-	puzzle := puzzle.NewPuzzle(u, v)
+	for _, puzzle := range puzzles {
+		if err := puzzle.Validate(params); err != nil {
+			return err
+		}
+	}
 
-	return puzzle
+	return nil
 }
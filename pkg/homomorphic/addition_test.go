@@ -1,6 +1,7 @@
 package homomorphic_test
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
@@ -23,7 +24,7 @@ func TestAddPlaintextValues(t *testing.T) {
 		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
 		puzzle2, _ := puzzle.GeneratePuzzle(params, message2)
 
-		puzzle3 := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2)
+		puzzle3, _ := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2)
 
 		result := puzzle.SolvePuzzle(params, puzzle3)
 
@@ -45,7 +46,7 @@ func TestAddPlaintextValues(t *testing.T) {
 		puzzle2, _ := puzzle.GeneratePuzzle(params, message2)
 		puzzle3, _ := puzzle.GeneratePuzzle(params, message3)
 
-		puzzle4 := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2, puzzle3)
+		puzzle4, _ := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2, puzzle3)
 
 		result := puzzle.SolvePuzzle(params, puzzle4)
 
@@ -81,7 +82,7 @@ func TestAddPlaintextValues(t *testing.T) {
 		puzzle9, _ := puzzle.GeneratePuzzle(params, message9)
 		puzzle10, _ := puzzle.GeneratePuzzle(params, message10)
 
-		puzzle11 := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2, puzzle3,
+		puzzle11, _ := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2, puzzle3,
 			puzzle4, puzzle5, puzzle6, puzzle7, puzzle8, puzzle9, puzzle10)
 
 		result := puzzle.SolvePuzzle(params, puzzle11)
@@ -106,7 +107,7 @@ func TestAddPlaintextValues(t *testing.T) {
 		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
 		puzzle2, _ := puzzle.GeneratePuzzle(params, message2)
 
-		puzzle3 := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2)
+		puzzle3, _ := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2)
 
 		result := puzzle.SolvePuzzle(params, puzzle3)
 
@@ -129,7 +130,7 @@ func TestAddPlaintextValue(t *testing.T) {
 		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
 		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
 
-		puzzle2 := homomorphic.AddPlaintextValue(params, puzzle1, message2)
+		puzzle2, _ := homomorphic.AddPlaintextValue(params, puzzle1, message2)
 
 		result := puzzle.SolvePuzzle(params, puzzle2)
 
@@ -137,4 +138,71 @@ func TestAddPlaintextValue(t *testing.T) {
 			t.Errorf("want %v, got %v", expected, result)
 		}
 	})
+	t.Run("Error when params are invalid", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(24)
+
+		p, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(p, message)
+
+		params1 := params.NewParams(p.Y, p.T, p.N, big.NewInt(0), p.H, p.NExpY, p.NExpYMinusOne)
+
+		_, err1 := homomorphic.AddPlaintextValues(params1, puzzle1, puzzle1)
+		_, err2 := homomorphic.AddPlaintextValue(params1, puzzle1, message)
+
+		if !errors.Is(err1, params.ErrInvalidG) {
+			t.Errorf("want error %v, got %v", params.ErrInvalidG, err1)
+		}
+		if !errors.Is(err2, params.ErrInvalidG) {
+			t.Errorf("want error %v, got %v", params.ErrInvalidG, err2)
+		}
+	})
+	t.Run("Error when puzzle is malformed", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(24)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+		puzzle2 := puzzle.NewPuzzle(big.NewInt(0), puzzle1.V)
+
+		_, err1 := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2)
+		_, err2 := homomorphic.AddPlaintextValue(params, puzzle2, message)
+
+		if !errors.Is(err1, puzzle.ErrInvalidU) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidU, err1)
+		}
+		if !errors.Is(err2, puzzle.ErrInvalidU) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidU, err2)
+		}
+	})
+
+	t.Run("Error when no puzzles are passed in", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		_, err := homomorphic.AddPlaintextValues(params)
+
+		if !errors.Is(err, homomorphic.ErrNoPuzzles) {
+			t.Errorf("want error %v, got %v", homomorphic.ErrNoPuzzles, err)
+		}
+	})
+	t.Run("Error when puzzle or value is missing", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(24))
+
+		_, err1 := homomorphic.AddPlaintextValue(params, nil, big.NewInt(42))
+		_, err2 := homomorphic.AddPlaintextValue(params, puzzle1, nil)
+
+		if !errors.Is(err1, puzzle.ErrMissingPuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrMissingPuzzle, err1)
+		}
+		if !errors.Is(err2, homomorphic.ErrMissingValue) {
+			t.Errorf("want error %v, got %v", homomorphic.ErrMissingValue, err2)
+		}
+	})
 }
@@ -0,0 +1,22 @@
+package homomorphic
+
+import "fmt"
+
+var (
+	// ErrNoPuzzles is returned if no puzzles are passed in.
+	ErrNoPuzzles = fmt.Errorf("no puzzles")
+	// ErrMissingValue is returned if a plaintext value is missing.
+	ErrMissingValue = fmt.Errorf("missing plaintext value")
+	// ErrPlaintextOutOfRange is returned if a plaintext value isn't an element of {0, ..., n^(y - 1) - 1}.
+	ErrPlaintextOutOfRange = fmt.Errorf("plaintext value out of range")
+	// ErrNumPuzzlesAndCoefficients is returned if the number of puzzles and coefficients differ.
+	ErrNumPuzzlesAndCoefficients = fmt.Errorf("number of puzzles and coefficients differ")
+	// ErrInvalidCoefficient is returned if a coefficient is missing.
+	ErrInvalidCoefficient = fmt.Errorf("invalid coefficient")
+	// ErrInexactCoefficient is returned if a coefficient isn't a finite decimal number.
+	ErrInexactCoefficient = fmt.Errorf("coefficient isn't a finite decimal number")
+	// ErrInvalidFixedPoint is returned if a fixed-point puzzle is missing its puzzle or has an invalid bound.
+	ErrInvalidFixedPoint = fmt.Errorf("invalid fixed-point puzzle")
+	// ErrOverflow is returned if the result of a computation might overflow the message space.
+	ErrOverflow = fmt.Errorf("message space overflow")
+)
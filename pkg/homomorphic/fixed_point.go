@@ -0,0 +1,172 @@
+package homomorphic
+
+import (
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/plaintext"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// FixedPointPuzzle is a puzzle that hides a fixed-point value (see
+// plaintext.FixedPoint) alongside the public bookkeeping that is needed to
+// combine it with other fixed-point values.
+type FixedPointPuzzle struct {
+	// Puzzle is the puzzle that hides the scaled value x * 10^scale.
+	Puzzle *puzzle.Puzzle
+	// Scale is the number of fractional digits.
+	Scale int
+	// Bound is a public upper bound on the absolute value of the scaled value
+	// which is used to detect overflows.
+	Bound *big.Int
+}
+
+// NewFixedPointPuzzle creates a new instance of a fixed-point puzzle.
+func NewFixedPointPuzzle(p *puzzle.Puzzle, scale int, bound *big.Int) *FixedPointPuzzle {
+	return &FixedPointPuzzle{
+		Puzzle: p,
+		Scale:  scale,
+		Bound:  bound,
+	}
+}
+
+// AddFixedPoint adds the fixed-point values that were hidden in the puzzles.
+// Puzzles with a smaller scale are rescaled to the largest scale before the
+// values are added.
+// Returns an error if no puzzles are passed in, if the protocol parameters or
+// a puzzle are invalid or if the sum might overflow the message space.
+func AddFixedPoint(params *params.Params, puzzles ...*FixedPointPuzzle) (*FixedPointPuzzle, error) {
+	if len(puzzles) == 0 {
+		return nil, ErrNoPuzzles
+	}
+
+	scale := 0
+	for _, z := range puzzles {
+		if err := validateFixedPoint(params, z); err != nil {
+			return nil, err
+		}
+
+		scale = max(scale, z.Scale)
+	}
+
+	if err := plaintext.NewFixedPoint(scale).Validate(params); err != nil {
+		return nil, err
+	}
+
+	// Rescale every value via the coefficient 10^(scale - scale_i).
+	bound := big.NewInt(0)
+	zs := make([]*puzzle.Puzzle, len(puzzles))
+	coeffs := make([]*big.Int, len(puzzles))
+	for i, z := range puzzles {
+		zs[i] = z.Puzzle
+		coeffs[i] = plaintext.NewFixedPoint(scale - z.Scale).Factor()
+
+		in1 := new(big.Int).Mul(z.Bound, coeffs[i]) // bound_i * 10^(scale - scale_i)
+		bound.Add(bound, in1)
+	}
+
+	if bound.Cmp(plaintext.MaxSigned(params)) > 0 {
+		return nil, ErrOverflow
+	}
+
+	sum, err := LinearCombination(params, zs, coeffs)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFixedPointPuzzle(sum, scale, bound), nil
+}
+
+// MultiplyFixedPoint multiplies the decimal constant with the fixed-point value
+// that is hidden in the puzzle. The scale of the result is the sum of the
+// puzzle's scale and the number of fractional digits of the constant.
+// Returns an error if the protocol parameters or the puzzle are invalid, if the
+// constant isn't a finite decimal number or if the product might overflow the
+// message space.
+func MultiplyFixedPoint(params *params.Params, z *FixedPointPuzzle, c *big.Rat) (*FixedPointPuzzle, error) {
+	if err := validateFixedPoint(params, z); err != nil {
+		return nil, err
+	}
+
+	cInt, cScale, err := toDecimal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := z.Scale + cScale
+	if err := plaintext.NewFixedPoint(scale).Validate(params); err != nil {
+		return nil, err
+	}
+
+	in1 := new(big.Int).Abs(cInt)  // |c * 10^scale_c|
+	bound := in1.Mul(in1, z.Bound) // bound * |c * 10^scale_c|
+
+	if bound.Cmp(plaintext.MaxSigned(params)) > 0 {
+		return nil, ErrOverflow
+	}
+
+	product, err := LinearCombination(params, []*puzzle.Puzzle{z.Puzzle}, []*big.Int{cInt})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFixedPointPuzzle(product, scale, bound), nil
+}
+
+// validateFixedPoint checks if the fixed-point puzzle is well-formed with
+// respect to the protocol parameters.
+// Returns an error if the protocol parameters, the puzzle, the scale or the
+// bound are invalid.
+func validateFixedPoint(params *params.Params, z *FixedPointPuzzle) error {
+	if z == nil || z.Puzzle == nil {
+		return ErrInvalidFixedPoint
+	}
+
+	if err := plaintext.NewFixedPoint(z.Scale).Validate(params); err != nil {
+		return err
+	}
+
+	if z.Bound == nil || z.Bound.Sign() < 0 || z.Bound.Cmp(plaintext.MaxSigned(params)) > 0 {
+		return ErrInvalidFixedPoint
+	}
+
+	return z.Puzzle.Validate(params)
+}
+
+// toDecimal returns the integer c * 10^scale and the smallest scale for which
+// that product is an integer.
+// Returns an error if the constant isn't a finite decimal number (which is the
+// case if its denominator has prime factors other than 2 and 5).
+func toDecimal(c *big.Rat) (*big.Int, int, error) {
+	if c == nil {
+		return nil, 0, ErrInexactCoefficient
+	}
+
+	d := new(big.Int).Set(c.Denom())
+	twos := 0
+	fives := 0
+	for d.Bit(0) == 0 {
+		d.Rsh(d, 1)
+		twos++
+	}
+	five := big.NewInt(5)
+	r := new(big.Int)
+	for {
+		q, m := new(big.Int).QuoRem(d, five, r)
+		if m.Sign() != 0 {
+			break
+		}
+		d = q
+		fives++
+	}
+
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return nil, 0, ErrInexactCoefficient
+	}
+
+	scale := max(twos, fives)
+	factor := plaintext.NewFixedPoint(scale).Factor()       // 10^scale
+	in1 := new(big.Rat).Mul(c, new(big.Rat).SetInt(factor)) // c * 10^scale
+
+	return in1.Num(), scale, nil
+}
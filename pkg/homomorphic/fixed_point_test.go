@@ -0,0 +1,120 @@
+package homomorphic_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/homomorphic"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/plaintext"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestFixedPoint(t *testing.T) {
+	t.Parallel()
+
+	params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+	// generate returns a fixed-point puzzle that hides the decimal value whose
+	// scaled absolute value is at most 10^6.
+	generate := func(value string, scale int) *homomorphic.FixedPointPuzzle {
+		m, _ := plaintext.NewFixedPoint(scale).EncodeString(params, value)
+		p, _ := puzzle.GeneratePuzzle(params, m)
+		return homomorphic.NewFixedPointPuzzle(p, scale, big.NewInt(1_000_000))
+	}
+
+	// solve solves the fixed-point puzzle and returns the decimal string.
+	solve := func(z *homomorphic.FixedPointPuzzle) string {
+		m := puzzle.SolvePuzzle(params, z.Puzzle)
+		s, _ := plaintext.NewFixedPoint(z.Scale).DecodeString(params, m)
+		return s
+	}
+
+	t.Run("Generate Puzzles / Add Values / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		z1 := generate("12.34", 2)
+		z2 := generate("-0.5", 1)
+		z3 := generate("100.001", 3)
+		expected := "111.841"
+
+		z4, err := homomorphic.AddFixedPoint(params, z1, z2, z3)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if z4.Scale != 3 {
+			t.Errorf("want scale %v, got %v", 3, z4.Scale)
+		}
+
+		if result := solve(z4); result != expected {
+			t.Errorf("want %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Generate Puzzle / Multiply Value / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		z1 := generate("12.34", 2)
+		c := big.NewRat(-15, 10) // -1.5
+		expected := "-18.510"
+
+		z2, err := homomorphic.MultiplyFixedPoint(params, z1, c)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if z2.Scale != 3 {
+			t.Errorf("want scale %v, got %v", 3, z2.Scale)
+		}
+
+		if result := solve(z2); result != expected {
+			t.Errorf("want %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Error when coefficient isn't a finite decimal number", func(t *testing.T) {
+		t.Parallel()
+
+		z1 := generate("12.34", 2)
+
+		_, err := homomorphic.MultiplyFixedPoint(params, z1, big.NewRat(1, 3))
+
+		if !errors.Is(err, homomorphic.ErrInexactCoefficient) {
+			t.Errorf("want error %v, got %v", homomorphic.ErrInexactCoefficient, err)
+		}
+	})
+
+	t.Run("Error when result might overflow", func(t *testing.T) {
+		t.Parallel()
+
+		z1 := generate("12.34", 2)
+		z1.Bound = plaintext.MaxSigned(params)
+
+		_, err1 := homomorphic.AddFixedPoint(params, z1, z1)
+		_, err2 := homomorphic.MultiplyFixedPoint(params, z1, big.NewRat(2, 1))
+
+		for _, err := range []error{err1, err2} {
+			if !errors.Is(err, homomorphic.ErrOverflow) {
+				t.Errorf("want error %v, got %v", homomorphic.ErrOverflow, err)
+			}
+		}
+	})
+
+	t.Run("Error when fixed-point puzzle is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		z1 := generate("12.34", 2)
+		z2 := homomorphic.NewFixedPointPuzzle(z1.Puzzle, 2, nil)
+		z3 := homomorphic.NewFixedPointPuzzle(nil, 2, big.NewInt(1))
+
+		for _, z := range []*homomorphic.FixedPointPuzzle{z2, z3, nil} {
+			_, err := homomorphic.AddFixedPoint(params, z1, z)
+
+			if !errors.Is(err, homomorphic.ErrInvalidFixedPoint) {
+				t.Errorf("want error %v, got %v", homomorphic.ErrInvalidFixedPoint, err)
+			}
+		}
+	})
+}
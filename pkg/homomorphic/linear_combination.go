@@ -0,0 +1,82 @@
+package homomorphic
+
+import (
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// LinearCombination computes a puzzle that hides the linear combination
+// a_1 * x_1 + ... + a_k * x_k mod n^(y - 1) of the plaintext values x_i that are
+// hidden in the puzzles and the coefficients a_i (which can be negative).
+// The u and v values are computed via multi-exponentiation which shares the
+// squarings between all puzzles.
+// Returns an error if no puzzles are passed in, if the number of puzzles and
+// coefficients differ, if a coefficient is missing or if the protocol
+// parameters or a puzzle are invalid.
+func LinearCombination(params *params.Params, puzzles []*puzzle.Puzzle, coeffs []*big.Int) (*puzzle.Puzzle, error) {
+	if len(puzzles) == 0 {
+		return nil, ErrNoPuzzles
+	}
+
+	if len(puzzles) != len(coeffs) {
+		return nil, ErrNumPuzzlesAndCoefficients
+	}
+
+	for _, a := range coeffs {
+		if a == nil {
+			return nil, ErrInvalidCoefficient
+		}
+	}
+
+	if err := validate(params, puzzles...); err != nil {
+		return nil, err
+	}
+
+	us := make([]*big.Int, len(puzzles))
+	vs := make([]*big.Int, len(puzzles))
+	exps := make([]*big.Int, len(puzzles))
+
+	for i, puzzle := range puzzles {
+		us[i] = puzzle.U
+		vs[i] = puzzle.V
+		exps[i] = coeffs[i]
+
+		// Use the inverses for negative coefficients as u^(-a) = (u^(-1))^a.
+		if coeffs[i].Sign() < 0 {
+			us[i] = new(big.Int).ModInverse(puzzle.U, params.N)     // u^(-1) mod n
+			vs[i] = new(big.Int).ModInverse(puzzle.V, params.NExpY) // v^(-1) mod n^y
+			exps[i] = new(big.Int).Neg(coeffs[i])                   // -a
+		}
+	}
+
+	u := utils.MultiExponentiate(us, exps, params.N)     // u_1^a_1 * ... * u_k^a_k mod n
+	v := utils.MultiExponentiate(vs, exps, params.NExpY) // v_1^a_1 * ... * v_k^a_k mod n^y
+
+	puzzle := puzzle.NewPuzzle(u, v)
+
+	return puzzle, nil
+}
+
+// InnerProduct computes a puzzle that hides the inner product
+// <x, a> = x_1 * a_1 + ... + x_k * a_k mod n^(y - 1) of the plaintext values x_i
+// that are hidden in the puzzles and the public vector a whose values are
+// expected to be plaintext values (elements of {0, ..., n^(y - 1) - 1}).
+// Returns an error if no puzzles are passed in, if the puzzles and the vector
+// have different lengths, if a value of the vector is out of range or if the
+// protocol parameters or a puzzle are invalid.
+func InnerProduct(params *params.Params, puzzles []*puzzle.Puzzle, vector []*big.Int) (*puzzle.Puzzle, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, a := range vector {
+		if a == nil || a.Sign() < 0 || a.Cmp(params.NExpYMinusOne) >= 0 {
+			return nil, ErrPlaintextOutOfRange
+		}
+	}
+
+	return LinearCombination(params, puzzles, vector)
+}
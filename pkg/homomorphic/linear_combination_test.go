@@ -0,0 +1,211 @@
+package homomorphic_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/homomorphic"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestLinearCombination(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate 3 Puzzles / Linear Combination / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		message1 := big.NewInt(24)
+		message2 := big.NewInt(42)
+		message3 := big.NewInt(11)
+		// 2 * 24 + 3 * 42 + 0 * 11 = 174
+		expected := big.NewInt(174)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
+		puzzle2, _ := puzzle.GeneratePuzzle(params, message2)
+		puzzle3, _ := puzzle.GeneratePuzzle(params, message3)
+
+		puzzles := []*puzzle.Puzzle{puzzle1, puzzle2, puzzle3}
+		coeffs := []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(0)}
+
+		puzzle4, _ := homomorphic.LinearCombination(params, puzzles, coeffs)
+
+		result := puzzle.SolvePuzzle(params, puzzle4)
+
+		if result.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Generate 2 Puzzles / Linear Combination / Solve Puzzle - Negative Coefficient", func(t *testing.T) {
+		t.Parallel()
+
+		message1 := big.NewInt(24)
+		message2 := big.NewInt(42)
+		// 1 * 24 - 2 * 42 = -60
+		expected := big.NewInt(-60)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
+		puzzle2, _ := puzzle.GeneratePuzzle(params, message2)
+
+		puzzles := []*puzzle.Puzzle{puzzle1, puzzle2}
+		coeffs := []*big.Int{big.NewInt(1), big.NewInt(-2)}
+
+		puzzle3, _ := homomorphic.LinearCombination(params, puzzles, coeffs)
+
+		result := puzzle.SolvePuzzle(params, puzzle3)
+		signed, _ := homomorphic.ToSigned(params, result)
+
+		if signed.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, signed)
+		}
+	})
+
+	t.Run("Generate 50 Puzzles / Linear Combination / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 3, big.NewInt(1))
+
+		// sum_i i * (i + 1) for i = 0, ..., 49
+		expected := big.NewInt(0)
+		puzzles := make([]*puzzle.Puzzle, 50)
+		coeffs := make([]*big.Int, 50)
+		for i := range 50 {
+			puzzles[i], _ = puzzle.GeneratePuzzle(params, big.NewInt(int64(i)))
+			coeffs[i] = big.NewInt(int64(i + 1))
+			expected.Add(expected, big.NewInt(int64(i*(i+1))))
+		}
+
+		puzzle1, _ := homomorphic.LinearCombination(params, puzzles, coeffs)
+
+		result := puzzle.SolvePuzzle(params, puzzle1)
+
+		if result.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Linear Combination equals Multiplications and Additions", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(24))
+		puzzle2, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		puzzles := []*puzzle.Puzzle{puzzle1, puzzle2}
+		coeffs := []*big.Int{big.NewInt(5), big.NewInt(7)}
+
+		puzzle3, _ := homomorphic.LinearCombination(params, puzzles, coeffs)
+
+		puzzle4, _ := homomorphic.MultiplyPlaintextValue(params, puzzle1, coeffs[0])
+		puzzle5, _ := homomorphic.MultiplyPlaintextValue(params, puzzle2, coeffs[1])
+		puzzle6, _ := homomorphic.AddPlaintextValues(params, puzzle4, puzzle5)
+
+		if !puzzle3.Equal(puzzle6) {
+			t.Errorf("want %v, got %v", puzzle6, puzzle3)
+		}
+	})
+
+	t.Run("Error when input is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(24))
+		puzzle2 := puzzle.NewPuzzle(puzzle1.U, params.NExpY)
+
+		tests := []struct {
+			name    string
+			puzzles []*puzzle.Puzzle
+			coeffs  []*big.Int
+			err     error
+		}{
+			{"No Puzzles", nil, nil, homomorphic.ErrNoPuzzles},
+			{"Length Mismatch", []*puzzle.Puzzle{puzzle1}, []*big.Int{big.NewInt(1), big.NewInt(2)}, homomorphic.ErrNumPuzzlesAndCoefficients},
+			{"Missing Coefficient", []*puzzle.Puzzle{puzzle1}, []*big.Int{nil}, homomorphic.ErrInvalidCoefficient},
+			{"Malformed Puzzle", []*puzzle.Puzzle{puzzle2}, []*big.Int{big.NewInt(1)}, puzzle.ErrInvalidV},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := homomorphic.LinearCombination(params, tc.puzzles, tc.coeffs)
+
+				if !errors.Is(err, tc.err) {
+					t.Errorf("want error %v, got %v", tc.err, err)
+				}
+			})
+		}
+	})
+}
+
+func TestInnerProduct(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate 3 Puzzles / Inner Product / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		// Votes (one-hot) for option 2 weighted by 1, 2 and 3.
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(0))
+		puzzle2, _ := puzzle.GeneratePuzzle(params, big.NewInt(1))
+		puzzle3, _ := puzzle.GeneratePuzzle(params, big.NewInt(1))
+
+		puzzles := []*puzzle.Puzzle{puzzle1, puzzle2, puzzle3}
+		vector := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+		expected := big.NewInt(5)
+
+		puzzle4, _ := homomorphic.InnerProduct(params, puzzles, vector)
+
+		result := puzzle.SolvePuzzle(params, puzzle4)
+
+		if result.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Error when vector value is out of range", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(24))
+
+		for _, a := range []*big.Int{big.NewInt(-1), params.NExpYMinusOne} {
+			_, err := homomorphic.InnerProduct(params, []*puzzle.Puzzle{puzzle1}, []*big.Int{a})
+
+			if !errors.Is(err, homomorphic.ErrPlaintextOutOfRange) {
+				t.Errorf("want error %v, got %v", homomorphic.ErrPlaintextOutOfRange, err)
+			}
+		}
+	})
+}
+
+func BenchmarkLinearCombination(b *testing.B) {
+	params, _ := params.GenerateParams(2048, 2, big.NewInt(1))
+
+	puzzles := make([]*puzzle.Puzzle, 100)
+	coeffs := make([]*big.Int, 100)
+	for i := range puzzles {
+		puzzles[i], _ = puzzle.GeneratePuzzle(params, big.NewInt(int64(i)))
+		coeffs[i] = big.NewInt(int64(1_000 + i))
+	}
+
+	b.Run("Multiply and Add", func(b *testing.B) {
+		for b.Loop() {
+			products := make([]*puzzle.Puzzle, len(puzzles))
+			for i := range puzzles {
+				products[i], _ = homomorphic.MultiplyPlaintextValue(params, puzzles[i], coeffs[i])
+			}
+			_, _ = homomorphic.AddPlaintextValues(params, products...)
+		}
+	})
+
+	b.Run("Linear Combination", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = homomorphic.LinearCombination(params, puzzles, coeffs)
+		}
+	})
+}
@@ -9,11 +9,21 @@ import (
 
 // MultiplyPlaintextValue multiplies the plaintext value with the value that is
 // hidden in the puzzle.
-func MultiplyPlaintextValue(params *params.Params, z *puzzle.Puzzle, p *big.Int) *puzzle.Puzzle {
+// Returns an error if the protocol parameters or the puzzle are invalid or if
+// the plaintext value is missing.
+func MultiplyPlaintextValue(params *params.Params, z *puzzle.Puzzle, p *big.Int) (*puzzle.Puzzle, error) {
+	if err := validate(params, z); err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, ErrMissingValue
+	}
+
 	u := new(big.Int).Exp(z.U, p, params.N)     // u^p mod n
 	v := new(big.Int).Exp(z.V, p, params.NExpY) // v^p mod n^y
 
 	puzzle := puzzle.NewPuzzle(u, v)
 
-	return puzzle
+	return puzzle, nil
 }
@@ -1,6 +1,7 @@
 package homomorphic_test
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
@@ -22,7 +23,7 @@ func TestMultiplyPlaintextValue(t *testing.T) {
 		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
 		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
 
-		puzzle2 := homomorphic.MultiplyPlaintextValue(params, puzzle1, message2)
+		puzzle2, _ := homomorphic.MultiplyPlaintextValue(params, puzzle1, message2)
 
 		result := puzzle.SolvePuzzle(params, puzzle2)
 
@@ -30,4 +31,33 @@ func TestMultiplyPlaintextValue(t *testing.T) {
 			t.Errorf("want %v, got %v", expected, result)
 		}
 	})
+	t.Run("Error when puzzle is malformed", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(24))
+		puzzle2 := puzzle.NewPuzzle(puzzle1.U, params.NExpY)
+
+		_, err := homomorphic.MultiplyPlaintextValue(params, puzzle2, big.NewInt(42))
+
+		if !errors.Is(err, puzzle.ErrInvalidV) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidV, err)
+		}
+	})
+	t.Run("Error when puzzle or value is missing", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(24))
+
+		_, err1 := homomorphic.MultiplyPlaintextValue(params, nil, big.NewInt(42))
+		_, err2 := homomorphic.MultiplyPlaintextValue(params, puzzle1, nil)
+
+		if !errors.Is(err1, puzzle.ErrMissingPuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrMissingPuzzle, err1)
+		}
+		if !errors.Is(err2, homomorphic.ErrMissingValue) {
+			t.Errorf("want error %v, got %v", homomorphic.ErrMissingValue, err2)
+		}
+	})
 }
@@ -0,0 +1,86 @@
+package homomorphic
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/internal/scheme"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/plaintext"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// SubtractPlaintextValues subtracts the plaintext value that is hidden in the
+// subtrahend from the plaintext value that is hidden in the minuend modulo
+// n^(y - 1). A negative difference wraps around and can be recovered via
+// ToSigned after solving the puzzle.
+// Returns an error if the protocol parameters or a puzzle are invalid.
+func SubtractPlaintextValues(params *params.Params, minuend, subtrahend *puzzle.Puzzle) (*puzzle.Puzzle, error) {
+	if err := validate(params, minuend, subtrahend); err != nil {
+		return nil, err
+	}
+
+	return addPlaintextValues(params, minuend, negate(params, subtrahend)), nil
+}
+
+// SubtractPlaintextValue subtracts the plaintext value from the value that is
+// hidden in the puzzle modulo n^(y - 1). A negative difference wraps around and
+// can be recovered via ToSigned after solving the puzzle.
+// Returns an error if the protocol parameters or the puzzle are invalid or if
+// the plaintext value is missing.
+func SubtractPlaintextValue(params *params.Params, z *puzzle.Puzzle, p *big.Int) (*puzzle.Puzzle, error) {
+	if err := validate(params, z); err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, ErrMissingValue
+	}
+
+	in1 := new(big.Int).Neg(p)                   // -p
+	pPrime := in1.Mod(in1, params.NExpYMinusOne) // -p mod n^(y - 1)
+
+	u, v := scheme.AddPlaintextValue(params, z.U, z.V, pPrime)
+
+	puzzle := puzzle.NewPuzzle(u, v)
+
+	return puzzle, nil
+}
+
+// Negate negates the plaintext value that is hidden in the puzzle modulo
+// n^(y - 1).
+// Returns an error if the protocol parameters or the puzzle are invalid.
+func Negate(params *params.Params, z *puzzle.Puzzle) (*puzzle.Puzzle, error) {
+	if err := validate(params, z); err != nil {
+		return nil, err
+	}
+
+	return negate(params, z), nil
+}
+
+// negate negates the plaintext value that is hidden in the puzzle modulo
+// n^(y - 1).
+// Note: The caller needs to ensure that the protocol parameters and the puzzle
+// are valid.
+func negate(params *params.Params, z *puzzle.Puzzle) *puzzle.Puzzle {
+	// Both values are units which is why their inverses exist.
+	u := new(big.Int).ModInverse(z.U, params.N)     // u^(-1) mod n
+	v := new(big.Int).ModInverse(z.V, params.NExpY) // v^(-1) mod n^y
+
+	return puzzle.NewPuzzle(u, v)
+}
+
+// ToSigned interprets the solved plaintext value x as a signed value in the
+// range [-(n^(y - 1) - 1) / 2, (n^(y - 1) - 1) / 2] (see plaintext.DecodeSigned).
+// The signed value is correct as long as the result of the homomorphic
+// computation is within that range.
+// Returns an error if the protocol parameters are invalid or if the plaintext
+// value isn't an element of {0, ..., n^(y - 1) - 1}.
+func ToSigned(params *params.Params, x *big.Int) (*big.Int, error) {
+	signed, err := plaintext.DecodeSigned(params, x)
+	if errors.Is(err, plaintext.ErrOutOfRange) {
+		return nil, ErrPlaintextOutOfRange
+	}
+
+	return signed, err
+}
@@ -0,0 +1,223 @@
+package homomorphic_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/homomorphic"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestSubtractPlaintextValues(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate 2 Puzzles / Subtract Message Values / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		message1 := big.NewInt(42)
+		message2 := big.NewInt(24)
+		expected := big.NewInt(18)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
+		puzzle2, _ := puzzle.GeneratePuzzle(params, message2)
+
+		puzzle3, _ := homomorphic.SubtractPlaintextValues(params, puzzle1, puzzle2)
+
+		result := puzzle.SolvePuzzle(params, puzzle3)
+
+		if result.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Generate 2 Puzzles / Subtract Message Values / Solve Puzzle - Negative Result", func(t *testing.T) {
+		t.Parallel()
+
+		message1 := big.NewInt(24)
+		message2 := big.NewInt(42)
+		expected := big.NewInt(-18)
+
+		params, _ := params.GenerateParams(128, 3, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
+		puzzle2, _ := puzzle.GeneratePuzzle(params, message2)
+
+		puzzle3, _ := homomorphic.SubtractPlaintextValues(params, puzzle1, puzzle2)
+
+		result := puzzle.SolvePuzzle(params, puzzle3)
+
+		wrapped := new(big.Int).Add(params.NExpYMinusOne, expected) // n^(y - 1) - 18
+		if result.Cmp(wrapped) != 0 {
+			t.Errorf("want %v, got %v", wrapped, result)
+		}
+
+		signed, _ := homomorphic.ToSigned(params, result)
+
+		if signed.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, signed)
+		}
+	})
+
+	t.Run("Error when puzzle is malformed", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+		puzzle2 := puzzle.NewPuzzle(puzzle1.U, params.NExpY)
+
+		_, err := homomorphic.SubtractPlaintextValues(params, puzzle1, puzzle2)
+
+		if !errors.Is(err, puzzle.ErrInvalidV) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidV, err)
+		}
+	})
+}
+
+func TestSubtractPlaintextValue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate Puzzle / Subtract Plaintext Value / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		message1 := big.NewInt(42)
+		message2 := big.NewInt(24)
+		expected := big.NewInt(18)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
+
+		puzzle2, _ := homomorphic.SubtractPlaintextValue(params, puzzle1, message2)
+
+		result := puzzle.SolvePuzzle(params, puzzle2)
+
+		if result.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Generate Puzzle / Subtract Plaintext Value / Solve Puzzle - Negative Result", func(t *testing.T) {
+		t.Parallel()
+
+		message1 := big.NewInt(24)
+		message2 := big.NewInt(42)
+		expected := big.NewInt(-18)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
+
+		puzzle2, _ := homomorphic.SubtractPlaintextValue(params, puzzle1, message2)
+
+		result := puzzle.SolvePuzzle(params, puzzle2)
+		signed, _ := homomorphic.ToSigned(params, result)
+
+		if signed.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, signed)
+		}
+	})
+
+	t.Run("Error when puzzle or value is missing", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(24))
+
+		_, err1 := homomorphic.SubtractPlaintextValue(params, nil, big.NewInt(42))
+		_, err2 := homomorphic.SubtractPlaintextValue(params, puzzle1, nil)
+
+		if !errors.Is(err1, puzzle.ErrMissingPuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrMissingPuzzle, err1)
+		}
+		if !errors.Is(err2, homomorphic.ErrMissingValue) {
+			t.Errorf("want error %v, got %v", homomorphic.ErrMissingValue, err2)
+		}
+	})
+}
+
+func TestNegate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate Puzzle / Negate / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+		expected := big.NewInt(-42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		puzzle2, _ := homomorphic.Negate(params, puzzle1)
+
+		result := puzzle.SolvePuzzle(params, puzzle2)
+		signed, _ := homomorphic.ToSigned(params, result)
+
+		if signed.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, signed)
+		}
+	})
+
+	t.Run("Generate Puzzle / Negate Twice / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		puzzle2, _ := homomorphic.Negate(params, puzzle1)
+		puzzle3, _ := homomorphic.Negate(params, puzzle2)
+
+		if !puzzle3.Equal(puzzle1) {
+			t.Errorf("want %v, got %v", puzzle1, puzzle3)
+		}
+	})
+}
+
+func TestToSigned(t *testing.T) {
+	t.Parallel()
+
+	params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+	half := new(big.Int).Rsh(params.NExpYMinusOne, 1)                  // (n - 1) / 2
+	halfPlusOne := new(big.Int).Add(half, big.NewInt(1))               // (n + 1) / 2
+	nMinusOne := new(big.Int).Sub(params.NExpYMinusOne, big.NewInt(1)) // n - 1
+
+	tests := []struct {
+		name      string
+		plaintext *big.Int
+		expected  *big.Int
+	}{
+		{"Zero", big.NewInt(0), big.NewInt(0)},
+		{"Largest Positive Value", half, half},
+		{"Smallest Negative Value", halfPlusOne, new(big.Int).Neg(half)},
+		{"Minus One", nMinusOne, big.NewInt(-1)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			signed, err := homomorphic.ToSigned(params, tc.plaintext)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if signed.Cmp(tc.expected) != 0 {
+				t.Errorf("want %v, got %v", tc.expected, signed)
+			}
+		})
+	}
+
+	t.Run("Error when plaintext is out of range", func(t *testing.T) {
+		t.Parallel()
+
+		for _, plaintext := range []*big.Int{big.NewInt(-1), params.NExpYMinusOne} {
+			_, err := homomorphic.ToSigned(params, plaintext)
+
+			if !errors.Is(err, homomorphic.ErrPlaintextOutOfRange) {
+				t.Errorf("want error %v, got %v", homomorphic.ErrPlaintextOutOfRange, err)
+			}
+		}
+	})
+}
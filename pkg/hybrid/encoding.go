@@ -0,0 +1,231 @@
+package hybrid
+
+import (
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// armorType is the type of the PEM block that contains the envelope.
+const armorType = "LHTLP ENVELOPE"
+
+// AppendBinary appends the binary encoding of the envelope to b.
+// The encoding consists of the header followed by the binary encoded protocol
+// parameters and puzzle (each as length-prefixed bytes), the algorithm (as a
+// single byte), the nonce and the ciphertext (each as length-prefixed bytes).
+// Returns an error if an envelope value is missing.
+func (e *Envelope) AppendBinary(b []byte) ([]byte, error) {
+	b, err := e.appendHeader(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.AppendBytes(b, e.Ciphertext), nil
+}
+
+// MarshalBinary encodes the envelope into its binary form.
+// Returns an error if an envelope value is missing.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	return e.AppendBinary(nil)
+}
+
+// UnmarshalBinary decodes the envelope from its binary form.
+// Returns an error if the data isn't a canonical encoding of an envelope.
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	d := utils.NewDecoder(data)
+
+	if err := d.ReadHeader(utils.TypeEnvelope); err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	paramsData, err := d.ReadBytes()
+	if err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	var p params.Params
+	if err := p.UnmarshalBinary(paramsData); err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	puzzleData, err := d.ReadBytes()
+	if err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	var z puzzle.Puzzle
+	if err := z.UnmarshalBinary(puzzleData); err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	algorithm, err := d.ReadByte()
+	if err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	nonce, err := d.ReadBytes()
+	if err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	ciphertext, err := d.ReadBytes()
+	if err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	if err := d.Finish(); err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	*e = *NewEnvelope(&p, &z, Algorithm(algorithm), nonce, ciphertext)
+
+	return nil
+}
+
+// MarshalText encodes the envelope into its armored text form which is a PEM
+// block that contains the binary encoding.
+// Returns an error if an envelope value is missing.
+func (e *Envelope) MarshalText() ([]byte, error) {
+	data, err := e.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.Armor(armorType, data), nil
+}
+
+// UnmarshalText decodes the envelope from its armored text form.
+// Returns an error if the text isn't a valid armored encoding of an envelope.
+func (e *Envelope) UnmarshalText(text []byte) error {
+	data, err := utils.Dearmor(armorType, text)
+	if err != nil {
+		return ErrDecodeEnvelope
+	}
+
+	return e.UnmarshalBinary(data)
+}
+
+// additionalData returns the data that is authenticated alongside the payload
+// which is the binary encoding of the envelope without the ciphertext.
+// Returns an error if an envelope value is missing.
+func (e *Envelope) additionalData() ([]byte, error) {
+	return e.appendHeader(nil)
+}
+
+// appendHeader appends the binary encoding of the envelope without the
+// ciphertext to b.
+// Returns an error if an envelope value is missing.
+func (e *Envelope) appendHeader(b []byte) ([]byte, error) {
+	if e.Params == nil || e.Puzzle == nil {
+		return nil, ErrEncodeEnvelope
+	}
+
+	paramsData, err := e.Params.MarshalBinary()
+	if err != nil {
+		return nil, ErrEncodeEnvelope
+	}
+
+	puzzleData, err := e.Puzzle.MarshalBinary()
+	if err != nil {
+		return nil, ErrEncodeEnvelope
+	}
+
+	b = utils.AppendHeader(b, utils.TypeEnvelope)
+	b = utils.AppendBytes(b, paramsData)
+	b = utils.AppendBytes(b, puzzleData)
+	b = append(b, byte(e.Algorithm))
+	b = utils.AppendBytes(b, e.Nonce)
+
+	return b, nil
+}
+
+// AppendBinary appends the binary encoding of the stream header to b.
+// The encoding consists of the header followed by the binary encoded protocol
+// parameters and puzzle (each as length-prefixed bytes), the algorithm (as a
+// single byte), the chunk size (as an 8 byte big-endian integer) and the nonce
+// prefix (as length-prefixed bytes).
+// Returns an error if a stream header value is missing or invalid.
+func (h *StreamHeader) AppendBinary(b []byte) ([]byte, error) {
+	if h.Params == nil || h.Puzzle == nil || h.ChunkSize <= 0 {
+		return nil, ErrEncodeStreamHeader
+	}
+
+	paramsData, err := h.Params.MarshalBinary()
+	if err != nil {
+		return nil, ErrEncodeStreamHeader
+	}
+
+	puzzleData, err := h.Puzzle.MarshalBinary()
+	if err != nil {
+		return nil, ErrEncodeStreamHeader
+	}
+
+	b = utils.AppendHeader(b, utils.TypeStreamHeader)
+	b = utils.AppendBytes(b, paramsData)
+	b = utils.AppendBytes(b, puzzleData)
+	b = append(b, byte(h.Algorithm))
+	b = utils.AppendUint64(b, uint64(h.ChunkSize))
+	b = utils.AppendBytes(b, h.NoncePrefix)
+
+	return b, nil
+}
+
+// MarshalBinary encodes the stream header into its binary form.
+// Returns an error if a stream header value is missing or invalid.
+func (h *StreamHeader) MarshalBinary() ([]byte, error) {
+	return h.AppendBinary(nil)
+}
+
+// UnmarshalBinary decodes the stream header from its binary form.
+// Returns an error if the data isn't a canonical encoding of a stream header
+// or if the chunk size or the nonce prefix are invalid.
+func (h *StreamHeader) UnmarshalBinary(data []byte) error {
+	d := utils.NewDecoder(data)
+
+	if err := d.ReadHeader(utils.TypeStreamHeader); err != nil {
+		return ErrDecodeStreamHeader
+	}
+
+	paramsData, err := d.ReadBytes()
+	if err != nil {
+		return ErrDecodeStreamHeader
+	}
+
+	var p params.Params
+	if err := p.UnmarshalBinary(paramsData); err != nil {
+		return ErrDecodeStreamHeader
+	}
+
+	puzzleData, err := d.ReadBytes()
+	if err != nil {
+		return ErrDecodeStreamHeader
+	}
+
+	var z puzzle.Puzzle
+	if err := z.UnmarshalBinary(puzzleData); err != nil {
+		return ErrDecodeStreamHeader
+	}
+
+	algorithm, err := d.ReadByte()
+	if err != nil {
+		return ErrDecodeStreamHeader
+	}
+
+	chunkSize, err := d.ReadUint64()
+	if err != nil || chunkSize == 0 || chunkSize > MaxChunkSize {
+		return ErrDecodeStreamHeader
+	}
+
+	noncePrefix, err := d.ReadBytes()
+	if err != nil || len(noncePrefix) != noncePrefixSize {
+		return ErrDecodeStreamHeader
+	}
+
+	if err := d.Finish(); err != nil {
+		return ErrDecodeStreamHeader
+	}
+
+	*h = *NewStreamHeader(&p, &z, Algorithm(algorithm), int(chunkSize), noncePrefix)
+
+	return nil
+}
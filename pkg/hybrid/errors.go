@@ -0,0 +1,38 @@
+package hybrid
+
+import "fmt"
+
+var (
+	// ErrMessageSpaceTooSmall is returned if the message space of the protocol parameters is smaller than 2^256.
+	ErrMessageSpaceTooSmall = fmt.Errorf("message space too small")
+	// ErrSampleSecret is returned if the random secret can't be sampled.
+	ErrSampleSecret = fmt.Errorf("unable to sample random secret")
+	// ErrSampleNonce is returned if the random nonce can't be sampled.
+	ErrSampleNonce = fmt.Errorf("unable to sample random nonce")
+	// ErrDeriveKey is returned if the payload key can't be derived.
+	ErrDeriveKey = fmt.Errorf("unable to derive key")
+	// ErrInitializeAEAD is returned if the AEAD algorithm can't be initialized.
+	ErrInitializeAEAD = fmt.Errorf("unable to initialize AEAD")
+	// ErrUnsupportedAlgorithm is returned if the AEAD algorithm is not supported.
+	ErrUnsupportedAlgorithm = fmt.Errorf("unsupported algorithm")
+	// ErrInvalidEnvelope is returned if the envelope is missing a value or is malformed.
+	ErrInvalidEnvelope = fmt.Errorf("invalid envelope")
+	// ErrDecrypt is returned if the payload can't be decrypted.
+	ErrDecrypt = fmt.Errorf("unable to decrypt payload")
+	// ErrInvalidChunkSize is returned if the chunk size of a stream is not positive or too large.
+	ErrInvalidChunkSize = fmt.Errorf("invalid chunk size")
+	// ErrStreamClosed is returned if data is written to a closed stream.
+	ErrStreamClosed = fmt.Errorf("stream closed")
+	// ErrStreamLocked is returned if a stream is read before it was unlocked.
+	ErrStreamLocked = fmt.Errorf("stream locked")
+	// ErrStreamTooLarge is returned if a stream has more chunks than the chunk counter supports.
+	ErrStreamTooLarge = fmt.Errorf("stream too large")
+	// ErrEncodeEnvelope is returned if the envelope can't be encoded.
+	ErrEncodeEnvelope = fmt.Errorf("unable to encode envelope")
+	// ErrDecodeEnvelope is returned if the envelope can't be decoded.
+	ErrDecodeEnvelope = fmt.Errorf("unable to decode envelope")
+	// ErrEncodeStreamHeader is returned if the stream header can't be encoded.
+	ErrEncodeStreamHeader = fmt.Errorf("unable to encode stream header")
+	// ErrDecodeStreamHeader is returned if the stream header can't be decoded.
+	ErrDecodeStreamHeader = fmt.Errorf("unable to decode stream header")
+)
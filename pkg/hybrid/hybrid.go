@@ -0,0 +1,268 @@
+package hybrid
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// Algorithm is the AEAD algorithm that is used to encrypt the payload.
+type Algorithm byte
+
+// Supported AEAD algorithms.
+const (
+	// AlgorithmAES256GCM is AES-256 in Galois/Counter Mode.
+	AlgorithmAES256GCM Algorithm = 0x01
+)
+
+// keySize is the size (in bytes) of the derived payload key.
+const keySize = 32
+
+// minSecretBits is the minimum number of bits of the secret that is locked in
+// the puzzle and from which the payload key is derived.
+const minSecretBits = 256
+
+// HKDF infos that bind the derived keys to their purpose.
+const (
+	keyInfo       = "lhtlp hybrid payload key"
+	streamKeyInfo = "lhtlp hybrid stream key"
+)
+
+// Envelope is a self-describing time-locked ciphertext. The payload is
+// encrypted with a key that is derived from a random secret which is hidden in
+// the puzzle.
+type Envelope struct {
+	// Params are the protocol parameters that were used to generate the puzzle.
+	Params *params.Params
+	// Puzzle is the puzzle that hides the secret.
+	Puzzle *puzzle.Puzzle
+	// Algorithm is the AEAD algorithm that was used to encrypt the payload.
+	Algorithm Algorithm
+	// Nonce is the AEAD nonce.
+	Nonce []byte
+	// Ciphertext is the encrypted payload (including the authentication tag).
+	Ciphertext []byte
+}
+
+// NewEnvelope creates a new instance of an envelope.
+func NewEnvelope(params *params.Params, puzzle *puzzle.Puzzle, algorithm Algorithm, nonce, ciphertext []byte) *Envelope {
+	return &Envelope{
+		Params:     params,
+		Puzzle:     puzzle,
+		Algorithm:  algorithm,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+	}
+}
+
+// SealOptions configures the sealing of payloads.
+type SealOptions struct {
+	// Rand is the source of randomness (defaults to crypto/rand.Reader).
+	Rand io.Reader
+	// Algorithm is the AEAD algorithm (defaults to AlgorithmAES256GCM).
+	Algorithm Algorithm
+	// ChunkSize is the size (in bytes) of the plaintext chunks of streams
+	// (defaults to DefaultChunkSize). It's ignored by Seal.
+	ChunkSize int
+}
+
+// rand returns the configured source of randomness.
+func (o *SealOptions) rand() io.Reader {
+	if o == nil || o.Rand == nil {
+		return rand.Reader
+	}
+
+	return o.Rand
+}
+
+// chunkSize returns the configured chunk size.
+func (o *SealOptions) chunkSize() int {
+	if o == nil || o.ChunkSize == 0 {
+		return DefaultChunkSize
+	}
+
+	return o.ChunkSize
+}
+
+// algorithm returns the configured AEAD algorithm.
+func (o *SealOptions) algorithm() Algorithm {
+	if o == nil || o.Algorithm == 0 {
+		return AlgorithmAES256GCM
+	}
+
+	return o.Algorithm
+}
+
+// Seal encrypts the payload so that it can only be decrypted after solving a
+// single puzzle that was generated with the protocol parameters.
+// A random secret s in {0, ..., n^(y - 1) - 1} is hidden in the puzzle and the
+// payload is encrypted with a key that is derived from s via HKDF-SHA256. The
+// protocol parameters, the puzzle, the algorithm and the nonce are
+// authenticated as additional data.
+// Returns an error if the protocol parameters are invalid, if their message
+// space is smaller than 2^256 or if the encryption fails.
+func Seal(params *params.Params, payload []byte) (*Envelope, error) {
+	return SealWithOptions(params, payload, nil)
+}
+
+// SealWithOptions encrypts the payload like Seal while using the options
+// (which can be nil).
+// Returns an error if the protocol parameters are invalid, if their message
+// space is smaller than 2^256 or if the encryption fails.
+func SealWithOptions(params *params.Params, payload []byte, opts *SealOptions) (*Envelope, error) {
+	random := opts.rand()
+
+	s, z, err := lockSecret(params, random)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(opts.algorithm(), params, s, keyInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(random, nonce); err != nil {
+		return nil, ErrSampleNonce
+	}
+
+	envelope := NewEnvelope(params, z, opts.algorithm(), nonce, nil)
+
+	ad, err := envelope.additionalData()
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.Ciphertext = aead.Seal(nil, nonce, payload, ad)
+
+	return envelope, nil
+}
+
+// Open solves the envelope's puzzle and decrypts the payload. The context and
+// the options (which can be nil) are handled like in puzzle.SolvePuzzleContext.
+// Returns an error if the envelope is invalid, if the context is canceled or if
+// the decryption fails.
+func Open(ctx context.Context, envelope *Envelope, opts *puzzle.SolveOptions) ([]byte, error) {
+	if envelope.Params == nil || envelope.Puzzle == nil {
+		return nil, ErrInvalidEnvelope
+	}
+
+	s, err := puzzle.SolvePuzzleContext(ctx, envelope.Params, envelope.Puzzle, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return envelope.decrypt(s)
+}
+
+// OpenWithTrapdoor decrypts the payload without doing the sequential
+// computation by solving the envelope's puzzle via the secret protocol
+// parameters.
+// Returns an error if the envelope is invalid or if the decryption fails.
+func OpenWithTrapdoor(envelope *Envelope, secret *params.SecretParams) ([]byte, error) {
+	if envelope.Params == nil || envelope.Puzzle == nil {
+		return nil, ErrInvalidEnvelope
+	}
+
+	s, err := puzzle.SolvePuzzleWithTrapdoor(envelope.Params, secret, envelope.Puzzle)
+	if err != nil {
+		return nil, err
+	}
+
+	return envelope.decrypt(s)
+}
+
+// lockSecret samples a random secret s in {0, ..., n^(y - 1) - 1} and hides it
+// in a puzzle.
+// Returns an error if the protocol parameters are invalid, if their message
+// space is smaller than 2^256 or if the generation of the puzzle fails.
+func lockSecret(params *params.Params, random io.Reader) (*big.Int, *puzzle.Puzzle, error) {
+	if err := params.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	if params.NExpYMinusOne.BitLen() <= minSecretBits {
+		return nil, nil, ErrMessageSpaceTooSmall
+	}
+
+	s, err := rand.Int(random, params.NExpYMinusOne)
+	if err != nil {
+		return nil, nil, ErrSampleSecret
+	}
+
+	z, _, err := puzzle.GeneratePuzzleAndReturnNonceWithOptions(params, s, &puzzle.GenerateOptions{Rand: random})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, z, nil
+}
+
+// decrypt decrypts the payload with the key that is derived from the secret.
+// Returns an error if the decryption fails.
+func (e *Envelope) decrypt(s *big.Int) ([]byte, error) {
+	aead, err := newAEAD(e.Algorithm, e.Params, s, keyInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(e.Nonce) != aead.NonceSize() {
+		return nil, ErrInvalidEnvelope
+	}
+
+	ad, err := e.additionalData()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := aead.Open(nil, e.Nonce, e.Ciphertext, ad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	return payload, nil
+}
+
+// newAEAD initializes the AEAD algorithm with the key that is derived from the
+// secret and the HKDF info.
+// Returns an error if the algorithm isn't supported or can't be initialized.
+func newAEAD(algorithm Algorithm, params *params.Params, s *big.Int, info string) (cipher.AEAD, error) {
+	if s.Sign() < 0 || s.Cmp(params.NExpYMinusOne) >= 0 {
+		return nil, ErrDeriveKey
+	}
+
+	// Encode s with a fixed length so that the key doesn't depend on its size.
+	secret := make([]byte, (params.NExpYMinusOne.BitLen()+7)/8)
+	s.FillBytes(secret)
+
+	key, err := hkdf.Key(sha256.New, secret, nil, info, keySize)
+	if err != nil {
+		return nil, ErrDeriveKey
+	}
+
+	switch algorithm {
+	case AlgorithmAES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, ErrInitializeAEAD
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, ErrInitializeAEAD
+		}
+
+		return aead, nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
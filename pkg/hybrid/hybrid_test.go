@@ -0,0 +1,162 @@
+package hybrid_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/hybrid"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestHybrid(t *testing.T) {
+	t.Parallel()
+
+	params1, secret, _ := params.GenerateParamsWithTrapdoor(512, 2, big.NewInt(1_000))
+
+	payload := make([]byte, 1<<20)
+	rand.Read(payload)
+
+	t.Run("Seal / Open", func(t *testing.T) {
+		t.Parallel()
+
+		envelope, err := hybrid.Seal(params1, payload)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		result, err := hybrid.Open(context.Background(), envelope, nil)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if !bytes.Equal(result, payload) {
+			t.Error("want equal payloads")
+		}
+	})
+
+	t.Run("Seal / Open - Empty Payload", func(t *testing.T) {
+		t.Parallel()
+
+		envelope, _ := hybrid.Seal(params1, nil)
+		result, err := hybrid.Open(context.Background(), envelope, nil)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if len(result) != 0 {
+			t.Errorf("want empty payload, got %v", result)
+		}
+	})
+
+	t.Run("Seal / Open - Trapdoor", func(t *testing.T) {
+		t.Parallel()
+
+		envelope, _ := hybrid.Seal(params1, payload)
+		result, err := hybrid.OpenWithTrapdoor(envelope, secret)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if !bytes.Equal(result, payload) {
+			t.Error("want equal payloads")
+		}
+	})
+
+	t.Run("Seal / Marshal / Unmarshal / Open", func(t *testing.T) {
+		t.Parallel()
+
+		envelope1, _ := hybrid.Seal(params1, []byte("hello"))
+
+		data1, _ := envelope1.MarshalBinary()
+		var envelope2 hybrid.Envelope
+		if err := envelope2.UnmarshalBinary(data1); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		data2, _ := envelope2.MarshalText()
+		var envelope3 hybrid.Envelope
+		if err := envelope3.UnmarshalText(data2); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		result, _ := hybrid.OpenWithTrapdoor(&envelope3, secret)
+
+		if string(result) != "hello" {
+			t.Errorf("want %v, got %v", "hello", string(result))
+		}
+	})
+
+	t.Run("Error when envelope was tampered with", func(t *testing.T) {
+		t.Parallel()
+
+		otherPuzzle, _ := puzzle.GeneratePuzzle(params1, big.NewInt(42))
+
+		tests := []struct {
+			name   string
+			tamper func(e *hybrid.Envelope)
+		}{
+			{"Ciphertext", func(e *hybrid.Envelope) { e.Ciphertext[0] ^= 1 }},
+			{"Nonce", func(e *hybrid.Envelope) { e.Nonce[0] ^= 1 }},
+			{"Puzzle", func(e *hybrid.Envelope) { e.Puzzle = otherPuzzle }},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				envelope, _ := hybrid.Seal(params1, []byte("hello"))
+				tc.tamper(envelope)
+
+				_, err := hybrid.OpenWithTrapdoor(envelope, secret)
+
+				if !errors.Is(err, hybrid.ErrDecrypt) {
+					t.Errorf("want error %v, got %v", hybrid.ErrDecrypt, err)
+				}
+			})
+		}
+	})
+
+	t.Run("Error when message space is too small", func(t *testing.T) {
+		t.Parallel()
+
+		params2, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		_, err := hybrid.Seal(params2, payload)
+
+		if !errors.Is(err, hybrid.ErrMessageSpaceTooSmall) {
+			t.Errorf("want error %v, got %v", hybrid.ErrMessageSpaceTooSmall, err)
+		}
+	})
+
+	t.Run("Error when algorithm is not supported", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &hybrid.SealOptions{Algorithm: 0xff}
+
+		_, err := hybrid.SealWithOptions(params1, payload, opts)
+
+		if !errors.Is(err, hybrid.ErrUnsupportedAlgorithm) {
+			t.Errorf("want error %v, got %v", hybrid.ErrUnsupportedAlgorithm, err)
+		}
+	})
+
+	t.Run("Error when context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		envelope, _ := hybrid.Seal(params1, payload)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := hybrid.Open(ctx, envelope, nil)
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want error %v, got %v", context.Canceled, err)
+		}
+	})
+}
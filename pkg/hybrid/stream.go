@@ -0,0 +1,392 @@
+package hybrid
+
+import (
+	"bufio"
+	"context"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// DefaultChunkSize is the default size (in bytes) of the plaintext chunks of
+// streams.
+const DefaultChunkSize = 64 * 1024
+
+// MaxChunkSize is the maximum size (in bytes) of the plaintext chunks of
+// streams.
+const MaxChunkSize = 16 * 1024 * 1024
+
+// maxHeaderSize is the maximum size (in bytes) of the encoded stream header.
+const maxHeaderSize = 1024 * 1024
+
+// noncePrefixSize is the size (in bytes) of the random nonce prefix. The rest
+// of the nonce consists of a 4 byte chunk counter and a 1 byte last chunk flag.
+const noncePrefixSize = 7
+
+// StreamHeader is the header of a time-locked stream which carries everything
+// that is needed to eventually decrypt the stream.
+type StreamHeader struct {
+	// Params are the protocol parameters that were used to generate the puzzle.
+	Params *params.Params
+	// Puzzle is the puzzle that hides the secret.
+	Puzzle *puzzle.Puzzle
+	// Algorithm is the AEAD algorithm that was used to encrypt the chunks.
+	Algorithm Algorithm
+	// ChunkSize is the size (in bytes) of the plaintext chunks.
+	ChunkSize int
+	// NoncePrefix is the random prefix of the chunk nonces.
+	NoncePrefix []byte
+}
+
+// NewStreamHeader creates a new instance of a stream header.
+func NewStreamHeader(params *params.Params, puzzle *puzzle.Puzzle, algorithm Algorithm, chunkSize int, noncePrefix []byte) *StreamHeader {
+	return &StreamHeader{
+		Params:      params,
+		Puzzle:      puzzle,
+		Algorithm:   algorithm,
+		ChunkSize:   chunkSize,
+		NoncePrefix: noncePrefix,
+	}
+}
+
+// StreamWriter encrypts a stream of arbitrary length in authenticated chunks
+// via the STREAM construction (see https://eprint.iacr.org/2015/189.pdf) so
+// that memory use stays constant. The nonce of every chunk consists of a
+// random prefix, the chunk counter and a flag that marks the last chunk which
+// is why reordered, dropped or truncated chunks are detected.
+type StreamWriter struct {
+	w         io.Writer
+	aead      cipher.AEAD
+	stream    *stream
+	buf       []byte
+	chunkSize int
+	closed    bool
+	err       error
+}
+
+// NewStreamWriter creates a stream writer that writes the stream header to w
+// and then encrypts all data that's written to it. The secret from which the
+// key is derived is hidden in a puzzle that was generated with the protocol
+// parameters. The options can be nil.
+// Note: Close needs to be called to write the last chunk. Close doesn't close
+// w.
+// Returns an error if the protocol parameters are invalid, if their message
+// space is smaller than 2^256, if the chunk size is invalid or if the header
+// can't be written.
+func NewStreamWriter(w io.Writer, params *params.Params, opts *SealOptions) (*StreamWriter, error) {
+	chunkSize := opts.chunkSize()
+	if chunkSize <= 0 || chunkSize > MaxChunkSize {
+		return nil, ErrInvalidChunkSize
+	}
+
+	random := opts.rand()
+
+	s, z, err := lockSecret(params, random)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(opts.algorithm(), params, s, streamKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	noncePrefix := make([]byte, noncePrefixSize)
+	if _, err := io.ReadFull(random, noncePrefix); err != nil {
+		return nil, ErrSampleNonce
+	}
+
+	header := NewStreamHeader(params, z, opts.algorithm(), chunkSize, noncePrefix)
+
+	data, err := header.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(utils.AppendBytes(nil, data)); err != nil {
+		return nil, err
+	}
+
+	sw := &StreamWriter{
+		w:         w,
+		aead:      aead,
+		stream:    newStream(noncePrefix, data),
+		buf:       make([]byte, 0, chunkSize),
+		chunkSize: chunkSize,
+	}
+
+	return sw, nil
+}
+
+// Write encrypts the data and writes the encrypted chunks to the underlying
+// writer once they are full.
+// Returns an error if the stream writer is closed or if a chunk can't be
+// written.
+func (sw *StreamWriter) Write(p []byte) (int, error) {
+	if sw.closed {
+		return 0, ErrStreamClosed
+	}
+
+	if sw.err != nil {
+		return 0, sw.err
+	}
+
+	n := 0
+	for len(p) > 0 {
+		// A full chunk is only written once more data follows as the last
+		// chunk has to be marked as such.
+		if len(sw.buf) == sw.chunkSize {
+			if err := sw.writeChunk(false); err != nil {
+				return n, err
+			}
+		}
+
+		m := copy(sw.buf[len(sw.buf):sw.chunkSize], p)
+		sw.buf = sw.buf[:len(sw.buf)+m]
+		p = p[m:]
+		n += m
+	}
+
+	return n, nil
+}
+
+// Close encrypts the buffered data as the last chunk and writes it to the
+// underlying writer.
+// Returns an error if the last chunk can't be written.
+func (sw *StreamWriter) Close() error {
+	if sw.closed {
+		return nil
+	}
+
+	if sw.err != nil {
+		return sw.err
+	}
+
+	sw.closed = true
+
+	return sw.writeChunk(true)
+}
+
+// writeChunk encrypts the buffered data and writes it to the underlying
+// writer.
+// Returns an error if the chunk can't be written.
+func (sw *StreamWriter) writeChunk(last bool) error {
+	nonce, err := sw.stream.next(last)
+	if err != nil {
+		sw.err = err
+		return err
+	}
+
+	ciphertext := sw.aead.Seal(nil, nonce, sw.buf, sw.stream.ad)
+	if _, err := sw.w.Write(ciphertext); err != nil {
+		sw.err = err
+		return err
+	}
+
+	sw.buf = sw.buf[:0]
+
+	return nil
+}
+
+// StreamReader decrypts a stream that was encrypted via a StreamWriter.
+type StreamReader struct {
+	// Header is the header of the stream.
+	Header *StreamHeader
+
+	r      *bufio.Reader
+	aead   cipher.AEAD
+	stream *stream
+	buf    []byte
+	done   bool
+	err    error
+}
+
+// NewStreamReader creates a stream reader that reads the stream header from r.
+// The stream can only be read after it was unlocked via Unlock or
+// UnlockWithTrapdoor.
+// Returns an error if the header can't be read or decoded.
+func NewStreamReader(r io.Reader) (*StreamReader, error) {
+	br := bufio.NewReader(r)
+
+	var length [4]byte
+	if _, err := io.ReadFull(br, length[:]); err != nil {
+		return nil, ErrDecodeStreamHeader
+	}
+
+	size := binary.BigEndian.Uint32(length[:])
+	if size > maxHeaderSize {
+		return nil, ErrDecodeStreamHeader
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return nil, ErrDecodeStreamHeader
+	}
+
+	var header StreamHeader
+	if err := header.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+
+	sr := &StreamReader{
+		Header: &header,
+		r:      br,
+		stream: newStream(header.NoncePrefix, data),
+	}
+
+	return sr, nil
+}
+
+// Unlock solves the puzzle of the stream header so that the stream can be
+// read. The context and the options (which can be nil) are handled like in
+// puzzle.SolvePuzzleContext.
+// Returns an error if the puzzle can't be solved or if the algorithm isn't
+// supported.
+func (sr *StreamReader) Unlock(ctx context.Context, opts *puzzle.SolveOptions) error {
+	s, err := puzzle.SolvePuzzleContext(ctx, sr.Header.Params, sr.Header.Puzzle, opts)
+	if err != nil {
+		return err
+	}
+
+	return sr.unlock(s)
+}
+
+// UnlockWithTrapdoor solves the puzzle of the stream header via the secret
+// protocol parameters so that the stream can be read without doing the
+// sequential computation.
+// Returns an error if the protocol parameters, the puzzle or the secret
+// protocol parameters are invalid or if the algorithm isn't supported.
+func (sr *StreamReader) UnlockWithTrapdoor(secret *params.SecretParams) error {
+	s, err := puzzle.SolvePuzzleWithTrapdoor(sr.Header.Params, secret, sr.Header.Puzzle)
+	if err != nil {
+		return err
+	}
+
+	return sr.unlock(s)
+}
+
+// unlock initializes the AEAD algorithm with the key that is derived from the
+// secret.
+// Returns an error if the algorithm isn't supported.
+func (sr *StreamReader) unlock(s *big.Int) error {
+	aead, err := newAEAD(sr.Header.Algorithm, sr.Header.Params, s, streamKeyInfo)
+	if err != nil {
+		return err
+	}
+
+	sr.aead = aead
+
+	return nil
+}
+
+// Read reads and decrypts data from the stream.
+// Returns an error if the stream is locked or if a chunk can't be read or
+// authenticated (e.g. because the stream was truncated).
+func (sr *StreamReader) Read(p []byte) (int, error) {
+	if sr.aead == nil {
+		return 0, ErrStreamLocked
+	}
+
+	for len(sr.buf) == 0 {
+		if sr.err != nil {
+			return 0, sr.err
+		}
+
+		if sr.done {
+			return 0, io.EOF
+		}
+
+		sr.err = sr.readChunk()
+	}
+
+	n := copy(p, sr.buf)
+	sr.buf = sr.buf[n:]
+
+	return n, nil
+}
+
+// readChunk reads and decrypts the next chunk.
+// Returns an error if the chunk can't be read or authenticated.
+func (sr *StreamReader) readChunk() error {
+	ciphertext := make([]byte, sr.Header.ChunkSize+sr.aead.Overhead())
+
+	n, err := io.ReadFull(sr.r, ciphertext)
+	switch err {
+	case nil:
+	case io.EOF, io.ErrUnexpectedEOF:
+		// A short chunk is the last chunk.
+		sr.done = true
+	default:
+		return err
+	}
+
+	// A full chunk is the last chunk if it's followed by the end of the stream.
+	if !sr.done {
+		if _, err := sr.r.Peek(1); err == io.EOF {
+			sr.done = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	nonce, err := sr.stream.next(sr.done)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := sr.aead.Open(nil, nonce, ciphertext[:n], sr.stream.ad)
+	if err != nil {
+		return ErrDecrypt
+	}
+
+	sr.buf = plaintext
+
+	return nil
+}
+
+// stream derives the nonces of the STREAM construction.
+type stream struct {
+	prefix  []byte
+	counter uint64
+	// ad is the additional data of every chunk which is the hash of the
+	// stream header.
+	ad []byte
+}
+
+// newStream creates a new nonce sequence for the nonce prefix and binds every
+// chunk to the encoded stream header.
+func newStream(prefix, header []byte) *stream {
+	hash := sha256.Sum256(header)
+
+	return &stream{
+		prefix: prefix,
+		ad:     hash[:],
+	}
+}
+
+// next returns the nonce prefix || counter || last flag of the next chunk.
+// Returns an error if the counter is exhausted.
+func (s *stream) next(last bool) ([]byte, error) {
+	if s.counter > math.MaxUint32 {
+		return nil, ErrStreamTooLarge
+	}
+
+	nonce := append([]byte{}, s.prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, uint32(s.counter))
+	if last {
+		nonce = append(nonce, 1)
+	} else {
+		nonce = append(nonce, 0)
+	}
+
+	s.counter++
+
+	return nonce, nil
+}
@@ -0,0 +1,197 @@
+package hybrid_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+	"testing/iotest"
+
+	"github.com/primefactor-io/lhtlp/pkg/hybrid"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	params1, secret, _ := params.GenerateParamsWithTrapdoor(512, 2, big.NewInt(1_000))
+
+	chunkSize := 1024
+	opts := &hybrid.SealOptions{ChunkSize: chunkSize}
+
+	// encrypt encrypts the payload by writing it in small pieces.
+	encrypt := func(t *testing.T, payload []byte) []byte {
+		var buf bytes.Buffer
+
+		sw, err := hybrid.NewStreamWriter(&buf, params1, opts)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if _, err := io.Copy(sw, iotest.HalfReader(bytes.NewReader(payload))); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if err := sw.Close(); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		return buf.Bytes()
+	}
+
+	// decrypt decrypts the stream via the trapdoor.
+	decrypt := func(stream []byte) ([]byte, error) {
+		sr, err := hybrid.NewStreamReader(bytes.NewReader(stream))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := sr.UnlockWithTrapdoor(secret); err != nil {
+			return nil, err
+		}
+
+		return io.ReadAll(sr)
+	}
+
+	for _, length := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 10 * chunkSize, 10*chunkSize + 42} {
+		payload := make([]byte, length)
+		rand.Read(payload)
+
+		stream := encrypt(t, payload)
+
+		result, err := decrypt(stream)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if !bytes.Equal(result, payload) {
+			t.Errorf("want equal payloads (length %v)", length)
+		}
+	}
+
+	t.Run("Encrypt / Unlock / Decrypt", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte("time-locked document")
+		stream := encrypt(t, payload)
+
+		sr, _ := hybrid.NewStreamReader(bytes.NewReader(stream))
+
+		if sr.Header.Params.N.Cmp(params1.N) != 0 || sr.Header.ChunkSize != chunkSize {
+			t.Error("want header to carry the params and the chunk size")
+		}
+
+		if err := sr.Unlock(context.Background(), nil); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		result, _ := io.ReadAll(sr)
+
+		if !bytes.Equal(result, payload) {
+			t.Errorf("want %v, got %v", payload, result)
+		}
+	})
+
+	t.Run("Error when stream was tampered with", func(t *testing.T) {
+		t.Parallel()
+
+		payload := make([]byte, 3*chunkSize+42)
+		stream := encrypt(t, payload)
+
+		// The stream consists of the header and 4 chunks of which the last one
+		// is short.
+		fullChunk := chunkSize + 16
+		lastChunk := 42 + 16
+		headerSize := len(stream) - 3*fullChunk - lastChunk
+
+		flipped := bytes.Clone(stream)
+		flipped[len(flipped)-1] ^= 1
+
+		truncated := stream[:headerSize+2*fullChunk]
+
+		dropped := bytes.Clone(stream[:headerSize+fullChunk])
+		dropped = append(dropped, stream[headerSize+2*fullChunk:]...)
+
+		reordered := bytes.Clone(stream[:headerSize])
+		reordered = append(reordered, stream[headerSize+fullChunk:headerSize+2*fullChunk]...)
+		reordered = append(reordered, stream[headerSize:headerSize+fullChunk]...)
+		reordered = append(reordered, stream[headerSize+2*fullChunk:]...)
+
+		tests := []struct {
+			name   string
+			stream []byte
+		}{
+			{"Flipped Bit", flipped},
+			{"Truncated", truncated},
+			{"Dropped Chunk", dropped},
+			{"Reordered Chunks", reordered},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := decrypt(tc.stream)
+
+				if !errors.Is(err, hybrid.ErrDecrypt) {
+					t.Errorf("want error %v, got %v", hybrid.ErrDecrypt, err)
+				}
+			})
+		}
+	})
+
+	t.Run("Error when stream is locked", func(t *testing.T) {
+		t.Parallel()
+
+		stream := encrypt(t, []byte("hello"))
+		sr, _ := hybrid.NewStreamReader(bytes.NewReader(stream))
+
+		_, err := sr.Read(make([]byte, 10))
+
+		if !errors.Is(err, hybrid.ErrStreamLocked) {
+			t.Errorf("want error %v, got %v", hybrid.ErrStreamLocked, err)
+		}
+	})
+
+	t.Run("Error when header is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		stream := encrypt(t, []byte("hello"))
+
+		for _, s := range [][]byte{nil, stream[:10], {0xff, 0xff, 0xff, 0xff}} {
+			_, err := hybrid.NewStreamReader(bytes.NewReader(s))
+
+			if !errors.Is(err, hybrid.ErrDecodeStreamHeader) {
+				t.Errorf("want error %v, got %v", hybrid.ErrDecodeStreamHeader, err)
+			}
+		}
+	})
+
+	t.Run("Error when chunk size is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, size := range []int{-1, hybrid.MaxChunkSize + 1} {
+			_, err := hybrid.NewStreamWriter(io.Discard, params1, &hybrid.SealOptions{ChunkSize: size})
+
+			if !errors.Is(err, hybrid.ErrInvalidChunkSize) {
+				t.Errorf("want error %v, got %v", hybrid.ErrInvalidChunkSize, err)
+			}
+		}
+	})
+
+	t.Run("Error when stream is closed", func(t *testing.T) {
+		t.Parallel()
+
+		sw, _ := hybrid.NewStreamWriter(io.Discard, params1, opts)
+		sw.Close()
+
+		_, err := sw.Write([]byte("hello"))
+
+		if !errors.Is(err, hybrid.ErrStreamClosed) {
+			t.Errorf("want error %v, got %v", hybrid.ErrStreamClosed, err)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+// Package scheme implements the arithmetic of the LHTLP scheme without
+// validating its inputs so that it can be shared by the packages that
+// validate the protocol parameters and puzzles once at their API boundary.
+//
+// Note: The callers need to ensure that the protocol parameters are valid.
+package scheme
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+// SampleNonce samples a random nonce r in [0, n^y - 1).
+// Returns an error if the source of randomness fails.
+func SampleNonce(params *params.Params, random io.Reader) (*big.Int, error) {
+	nExpMinusOne := new(big.Int).Sub(params.NExpY, big.NewInt(1)) // n^y - 1
+
+	return rand.Int(random, nExpMinusOne)
+}
+
+// GeneratePuzzle computes the values u and v of the puzzle that hides the
+// plaintext using the nonce for randomness.
+func GeneratePuzzle(params *params.Params, nonce, plaintext *big.Int) (*big.Int, *big.Int) {
+	r := nonce
+	s := plaintext
+
+	// Compute u.
+	u := new(big.Int).Exp(params.G, r, params.N) // g^r mod n
+
+	// Compute v.
+	in1 := new(big.Int).Mul(r, params.NExpYMinusOne)     // r * n^(y - 1)
+	in2 := new(big.Int).Exp(params.H, in1, params.NExpY) // h^(r * n^(y - 1)) mod n^y
+	in3 := new(big.Int).Add(big.NewInt(1), params.N)     // 1 + n
+	in4 := new(big.Int).Exp(in3, s, params.NExpY)        // (1 + n)^s mod n^y
+	in5 := new(big.Int).Mul(in2, in4)                    // h^(r * n^(y - 1)) * (1 + n)^s
+	v := new(big.Int).Mod(in5, params.NExpY)             // h^(r * n^(y - 1)) * (1 + n)^s mod n^y
+
+	return u, v
+}
+
+// AddPlaintextValue computes the values u and v of the puzzle that hides the
+// sum of the plaintext value and the value that is hidden in the puzzle with
+// the values u and v.
+func AddPlaintextValue(params *params.Params, u, v, p *big.Int) (*big.Int, *big.Int) {
+	// Compute u' and v' of the puzzle that hides p using the nonce p.
+	uPrime, vPrime := GeneratePuzzle(params, p, p)
+
+	in1 := new(big.Int).Mul(u, uPrime)          // u * u'
+	uSum := new(big.Int).Mod(in1, params.N)     // u * u' mod n
+	in2 := new(big.Int).Mul(v, vPrime)          // v * v'
+	vSum := new(big.Int).Mod(in2, params.NExpY) // v * v' mod n^y
+
+	return uSum, vSum
+}
@@ -0,0 +1,36 @@
+package scheme_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/internal/scheme"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestScheme(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate Puzzle / Add Plaintext Value / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		nonce := big.NewInt(7)
+
+		u, v := scheme.GeneratePuzzle(params, nonce, big.NewInt(40))
+		want, _ := puzzle.GeneratePuzzleWithCustomNonce(params, nonce, big.NewInt(40))
+
+		if !puzzle.NewPuzzle(u, v).Equal(want) {
+			t.Errorf("want %v, got %v", want, puzzle.NewPuzzle(u, v))
+		}
+
+		u, v = scheme.AddPlaintextValue(params, u, v, big.NewInt(2))
+		plaintext, _ := puzzle.SolvePuzzleContext(context.Background(), params, puzzle.NewPuzzle(u, v), nil)
+
+		if plaintext.Cmp(big.NewInt(42)) != 0 {
+			t.Errorf("want %v, got %v", 42, plaintext)
+		}
+	})
+}
@@ -0,0 +1,374 @@
+package params
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// calibrationBatch is the number of squarings that are computed between two
+// checks of the elapsed time and the context.
+const calibrationBatch = 1 << 10
+
+// Measurement is the result of benchmarking modular squaring for a modulus of a
+// specific size.
+type Measurement struct {
+	// Bits is the bit length of the modulus.
+	Bits int
+	// Squarings is the number of sequential squarings that were computed.
+	Squarings uint64
+	// Elapsed is the time it took to compute the squarings.
+	Elapsed time.Duration
+}
+
+// SquaringsPerSecond returns the number of sequential squarings per second.
+func (m *Measurement) SquaringsPerSecond() float64 {
+	return float64(m.Squarings) / m.Elapsed.Seconds()
+}
+
+// Profile is a calibration profile which contains the squaring speed of the
+// host it was created on for one or more modulus sizes.
+type Profile struct {
+	// Host is the name of the host the profile was created on.
+	Host string
+	// OS is the operating system of the host.
+	OS string
+	// Arch is the architecture of the host.
+	Arch string
+	// CreatedAt is the time the profile was created at.
+	CreatedAt time.Time
+	// Measurements contains one measurement per modulus size.
+	Measurements []*Measurement
+}
+
+// NewProfile creates a new (empty) calibration profile for the current host.
+func NewProfile() *Profile {
+	host, _ := os.Hostname()
+
+	return &Profile{
+		Host:      host,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// Measurement returns the measurement for a modulus with the bit length.
+func (p *Profile) Measurement(bits int) (*Measurement, bool) {
+	for _, m := range p.Measurements {
+		if m.Bits == bits {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+// SetMeasurement adds the measurement to the profile while replacing an
+// existing measurement for the same modulus size.
+func (p *Profile) SetMeasurement(m *Measurement) {
+	p.Measurements = slices.DeleteFunc(p.Measurements, func(x *Measurement) bool {
+		return x.Bits == m.Bits
+	})
+	p.Measurements = append(p.Measurements, m)
+
+	slices.SortFunc(p.Measurements, func(a, b *Measurement) int {
+		return a.Bits - b.Bits
+	})
+}
+
+// Calibrate benchmarks sequential modular squaring on the current host for
+// moduli with the bit lengths and returns the resulting calibration profile.
+// Every modulus size is benchmarked for the duration (a few seconds give
+// stable results).
+// Returns an error if a bit length or the duration is invalid, if a modulus
+// can't be sampled or if the context is canceled.
+func Calibrate(ctx context.Context, duration time.Duration, bits ...int) (*Profile, error) {
+	if duration <= 0 {
+		return nil, ErrInvalidDuration
+	}
+
+	profile := NewProfile()
+
+	for _, b := range bits {
+		m, err := measure(ctx, b, duration)
+		if err != nil {
+			return nil, err
+		}
+
+		profile.SetMeasurement(m)
+	}
+
+	return profile, nil
+}
+
+// measure benchmarks sequential modular squaring for a random modulus with the
+// bit length. The squarings are computed exactly like they are computed when
+// solving a puzzle.
+// Returns an error if the bit length is invalid, if the modulus can't be
+// sampled or if the context is canceled.
+func measure(ctx context.Context, bits int, duration time.Duration) (*Measurement, error) {
+	if bits < 2 {
+		return nil, ErrInvalidBits
+	}
+
+	// Sample a random odd modulus n with the bit length. The speed of squaring
+	// doesn't depend on the factorization of n.
+	lower := new(big.Int).Lsh(big.NewInt(1), uint(bits-1)) // 2^(bits - 1)
+	n, err := rand.Int(rand.Reader, lower)
+	if err != nil {
+		return nil, ErrSampleModulus
+	}
+	n.Add(n, lower).SetBit(n, 0, 1)
+
+	// Sample a random base w in [2, n).
+	nMinusTwo := new(big.Int).Sub(n, big.NewInt(2)) // n - 2
+	w, err := rand.Int(rand.Reader, nMinusTwo)
+	if err != nil {
+		return nil, ErrSampleModulus
+	}
+	w.Add(w, big.NewInt(2))
+
+	done := ctx.Done()
+	squarings := uint64(0)
+	start := time.Now()
+	for {
+		select {
+		case <-done:
+			return nil, ctx.Err()
+		default:
+		}
+
+		for range calibrationBatch {
+			w.Mul(w, w).Mod(w, n) // w^2 mod n
+		}
+		squarings += calibrationBatch
+
+		if elapsed := time.Since(start); elapsed >= duration {
+			m := &Measurement{
+				Bits:      bits,
+				Squarings: squarings,
+				Elapsed:   elapsed,
+			}
+
+			return m, nil
+		}
+	}
+}
+
+// DifficultyForDuration returns the difficulty t (the number of sequential
+// squarings) that takes the duration to compute on the host of the calibration
+// profile when using a modulus with the bit length. The difficulty is rounded
+// down but is at least 1.
+// Like GenerateParams, an odd bit length is rounded down to the next even bit
+// length which is the size of the modulus n = p * q that's actually generated
+// (and looked up by EstimateDuration).
+// Returns an error if the duration is invalid or if the profile doesn't
+// contain a measurement for the size of the modulus.
+func DifficultyForDuration(bits int, d time.Duration, profile *Profile) (*big.Int, error) {
+	if d <= 0 {
+		return nil, ErrInvalidDuration
+	}
+
+	m, err := lookupMeasurement(profile, modulusBits(bits))
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute t = squarings * d / elapsed.
+	in1 := new(big.Int).SetUint64(m.Squarings)         // squarings
+	in2 := new(big.Int).Mul(in1, big.NewInt(int64(d))) // squarings * d
+	t := in2.Quo(in2, big.NewInt(int64(m.Elapsed)))    // squarings * d / elapsed
+
+	if t.Sign() == 0 {
+		t.SetInt64(1)
+	}
+
+	return t, nil
+}
+
+// EstimateDuration estimates the time it takes to solve a puzzle that was
+// generated with the protocol parameters on the host of the calibration
+// profile. This is the inverse of DifficultyForDuration.
+// Returns an error if the protocol parameters are invalid, if the profile
+// doesn't contain a measurement for the size of the modulus or if the duration
+// can't be represented.
+func EstimateDuration(params *Params, profile *Profile) (time.Duration, error) {
+	if err := params.Validate(); err != nil {
+		return 0, err
+	}
+
+	m, err := lookupMeasurement(profile, params.N.BitLen())
+	if err != nil {
+		return 0, err
+	}
+
+	// Compute d = t * elapsed / squarings.
+	in1 := new(big.Int).Mul(params.T, big.NewInt(int64(m.Elapsed))) // t * elapsed
+	d := in1.Quo(in1, new(big.Int).SetUint64(m.Squarings))          // t * elapsed / squarings
+
+	if !d.IsInt64() {
+		return 0, ErrDurationOverflow
+	}
+
+	return time.Duration(d.Int64()), nil
+}
+
+// modulusBits returns the bit length of the modulus n = p * q that's generated
+// for the bit length. Both primes have bits / 2 bits and their two most
+// significant bits set which is why n has exactly 2 * (bits / 2) bits.
+func modulusBits(bits int) int {
+	return 2 * (bits / 2)
+}
+
+// lookupMeasurement returns the (valid) measurement of the calibration profile
+// for the bit length.
+// Returns an error if there's no such measurement.
+func lookupMeasurement(profile *Profile, bits int) (*Measurement, error) {
+	if profile == nil {
+		return nil, ErrMissingMeasurement
+	}
+
+	m, ok := profile.Measurement(bits)
+	if !ok || m.Squarings == 0 || m.Elapsed <= 0 {
+		return nil, ErrMissingMeasurement
+	}
+
+	return m, nil
+}
+
+// DefaultProfilePath returns the default location of the calibration profile
+// which is inside of the user's configuration directory.
+// Returns an error if the configuration directory can't be determined.
+func DefaultProfilePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "lhtlp", "calibration.json"), nil
+}
+
+// SaveProfile persists the calibration profile as JSON at the path while
+// creating missing parent directories.
+// Returns an error if the profile can't be encoded or written.
+func SaveProfile(path string, profile *Profile) error {
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadProfile loads a calibration profile that was persisted via SaveProfile.
+// Returns an error if the profile can't be read or decoded.
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile Profile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// profileJSON is the JSON representation of a calibration profile.
+type profileJSON struct {
+	Version      int               `json:"version"`
+	Host         string            `json:"host"`
+	OS           string            `json:"os"`
+	Arch         string            `json:"arch"`
+	CreatedAt    time.Time         `json:"created_at"`
+	Measurements []measurementJSON `json:"measurements"`
+}
+
+// measurementJSON is the JSON representation of a measurement.
+type measurementJSON struct {
+	Bits      int    `json:"bits"`
+	Squarings uint64 `json:"squarings"`
+	ElapsedNS int64  `json:"elapsed_ns"`
+}
+
+// MarshalJSON encodes the calibration profile into its JSON form.
+// Returns an error if a measurement is missing.
+func (p *Profile) MarshalJSON() ([]byte, error) {
+	measurements := make([]measurementJSON, len(p.Measurements))
+	for i, m := range p.Measurements {
+		if m == nil {
+			return nil, ErrEncodeProfile
+		}
+
+		measurements[i] = measurementJSON{
+			Bits:      m.Bits,
+			Squarings: m.Squarings,
+			ElapsedNS: int64(m.Elapsed),
+		}
+	}
+
+	return json.Marshal(profileJSON{
+		Version:      int(utils.EncodingVersion),
+		Host:         p.Host,
+		OS:           p.OS,
+		Arch:         p.Arch,
+		CreatedAt:    p.CreatedAt,
+		Measurements: measurements,
+	})
+}
+
+// UnmarshalJSON decodes the calibration profile from its JSON form.
+// Returns an error if the data isn't a JSON encoding of a calibration profile
+// or if a measurement is invalid.
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	var v profileJSON
+	if err := utils.DecodeJSON(data, &v); err != nil {
+		return ErrDecodeProfile
+	}
+
+	if v.Version != int(utils.EncodingVersion) {
+		return ErrDecodeProfile
+	}
+
+	profile := Profile{
+		Host:      v.Host,
+		OS:        v.OS,
+		Arch:      v.Arch,
+		CreatedAt: v.CreatedAt,
+	}
+
+	for _, m := range v.Measurements {
+		if m.Bits < 2 || m.Squarings == 0 || m.ElapsedNS <= 0 {
+			return ErrDecodeProfile
+		}
+
+		if _, ok := profile.Measurement(m.Bits); ok {
+			return ErrDecodeProfile
+		}
+
+		profile.SetMeasurement(&Measurement{
+			Bits:      m.Bits,
+			Squarings: m.Squarings,
+			Elapsed:   time.Duration(m.ElapsedNS),
+		})
+	}
+
+	*p = profile
+
+	return nil
+}
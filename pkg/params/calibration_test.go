@@ -0,0 +1,245 @@
+package params_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+func TestCalibration(t *testing.T) {
+	t.Parallel()
+
+	// profile can compute 1,000,000 squarings per second with 128 bit moduli.
+	profile := params.NewProfile()
+	profile.SetMeasurement(&params.Measurement{
+		Bits:      128,
+		Squarings: 1_000_000,
+		Elapsed:   time.Second,
+	})
+
+	t.Run("Calibrate", func(t *testing.T) {
+		t.Parallel()
+
+		profile, err := params.Calibrate(context.Background(), 10*time.Millisecond, 256, 128)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if len(profile.Measurements) != 2 {
+			t.Fatalf("want %v measurements, got %v", 2, len(profile.Measurements))
+		}
+
+		for i, bits := range []int{128, 256} {
+			m := profile.Measurements[i]
+
+			if m.Bits != bits {
+				t.Errorf("want %v, got %v", bits, m.Bits)
+			}
+			if m.Squarings == 0 || m.Elapsed < 10*time.Millisecond {
+				t.Errorf("want a measurement of at least %v, got %v squarings in %v", 10*time.Millisecond, m.Squarings, m.Elapsed)
+			}
+		}
+	})
+
+	t.Run("Difficulty For Duration", func(t *testing.T) {
+		t.Parallel()
+
+		difficulty, err := params.DifficultyForDuration(128, 24*time.Hour, profile)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		expected := big.NewInt(86_400_000_000)
+		if difficulty.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, difficulty)
+		}
+	})
+
+	t.Run("Difficulty For Duration - Minimum", func(t *testing.T) {
+		t.Parallel()
+
+		difficulty, _ := params.DifficultyForDuration(128, time.Nanosecond, profile)
+
+		if difficulty.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("want %v, got %v", 1, difficulty)
+		}
+	})
+
+	t.Run("Estimate Duration", func(t *testing.T) {
+		t.Parallel()
+
+		difficulty, _ := params.DifficultyForDuration(128, time.Minute, profile)
+		params1, _ := params.GenerateParams(128, 2, difficulty)
+
+		duration, err := params.EstimateDuration(params1, profile)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if duration != time.Minute {
+			t.Errorf("want %v, got %v", time.Minute, duration)
+		}
+	})
+
+	t.Run("Estimate Duration - Odd Bit Length", func(t *testing.T) {
+		t.Parallel()
+
+		// A modulus that's generated for 129 bits has 128 bits.
+		difficulty, err := params.DifficultyForDuration(129, time.Minute, profile)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		params1, _ := params.GenerateParams(129, 2, difficulty)
+
+		if params1.N.BitLen() != 128 {
+			t.Fatalf("want %v bits, got %v", 128, params1.N.BitLen())
+		}
+
+		duration, err := params.EstimateDuration(params1, profile)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if duration != time.Minute {
+			t.Errorf("want %v, got %v", time.Minute, duration)
+		}
+	})
+
+	t.Run("Error when duration is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err1 := params.DifficultyForDuration(128, 0, profile)
+		_, err2 := params.Calibrate(context.Background(), -time.Second, 128)
+
+		for _, err := range []error{err1, err2} {
+			if !errors.Is(err, params.ErrInvalidDuration) {
+				t.Errorf("want error %v, got %v", params.ErrInvalidDuration, err)
+			}
+		}
+	})
+
+	t.Run("Error when bit length is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := params.Calibrate(context.Background(), time.Millisecond, 1)
+
+		if !errors.Is(err, params.ErrInvalidBits) {
+			t.Errorf("want error %v, got %v", params.ErrInvalidBits, err)
+		}
+	})
+
+	t.Run("Error when measurement is missing", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(256, 2, big.NewInt(1))
+
+		_, err1 := params.DifficultyForDuration(256, time.Hour, profile)
+		_, err2 := params.EstimateDuration(params1, profile)
+		_, err3 := params.DifficultyForDuration(128, time.Hour, nil)
+
+		for _, err := range []error{err1, err2, err3} {
+			if !errors.Is(err, params.ErrMissingMeasurement) {
+				t.Errorf("want error %v, got %v", params.ErrMissingMeasurement, err)
+			}
+		}
+	})
+
+	t.Run("Error when duration overflows", func(t *testing.T) {
+		t.Parallel()
+
+		difficulty := new(big.Int).Lsh(big.NewInt(1), 64) // 2^64
+		params1, _ := params.GenerateParams(128, 2, difficulty)
+
+		_, err := params.EstimateDuration(params1, profile)
+
+		if !errors.Is(err, params.ErrDurationOverflow) {
+			t.Errorf("want error %v, got %v", params.ErrDurationOverflow, err)
+		}
+	})
+
+	t.Run("Error when context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := params.Calibrate(ctx, time.Hour, 128)
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want error %v, got %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestProfileEncoding(t *testing.T) {
+	t.Parallel()
+
+	profile1 := params.NewProfile()
+	profile1.SetMeasurement(&params.Measurement{Bits: 2048, Squarings: 3_000, Elapsed: 2 * time.Millisecond})
+	profile1.SetMeasurement(&params.Measurement{Bits: 1024, Squarings: 5_000, Elapsed: time.Millisecond})
+
+	t.Run("Save / Load", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "lhtlp", "calibration.json")
+
+		if err := params.SaveProfile(path, profile1); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		profile2, err := params.LoadProfile(path)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if profile2.Host != profile1.Host || !profile2.CreatedAt.Equal(profile1.CreatedAt) {
+			t.Errorf("want %v, got %v", profile1, profile2)
+		}
+
+		if len(profile2.Measurements) != 2 {
+			t.Fatalf("want %v measurements, got %v", 2, len(profile2.Measurements))
+		}
+
+		for i, m := range profile1.Measurements {
+			if *profile2.Measurements[i] != *m {
+				t.Errorf("want %v, got %v", m, profile2.Measurements[i])
+			}
+		}
+	})
+
+	t.Run("Error when JSON is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name string
+			data string
+		}{
+			{"Unknown Field", `{"version":1,"foo":1,"measurements":[]}`},
+			{"Unsupported Version", `{"version":2,"measurements":[]}`},
+			{"Invalid Bits", `{"version":1,"measurements":[{"bits":1,"squarings":1,"elapsed_ns":1}]}`},
+			{"Zero Squarings", `{"version":1,"measurements":[{"bits":128,"squarings":0,"elapsed_ns":1}]}`},
+			{"Zero Elapsed", `{"version":1,"measurements":[{"bits":128,"squarings":1,"elapsed_ns":0}]}`},
+			{"Duplicate Bits", `{"version":1,"measurements":[{"bits":128,"squarings":1,"elapsed_ns":1},{"bits":128,"squarings":1,"elapsed_ns":1}]}`},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				var profile params.Profile
+				err := json.Unmarshal([]byte(tc.data), &profile)
+
+				if !errors.Is(err, params.ErrDecodeProfile) {
+					t.Errorf("want error %v, got %v", params.ErrDecodeProfile, err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package params
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// armorType is the type of the PEM block that contains the protocol parameters.
+const armorType = "LHTLP PARAMS"
+
+// paramsJSON is the JSON representation of the protocol parameters.
+type paramsJSON struct {
+	Version       int    `json:"version"`
+	Y             *int   `json:"y"`
+	T             string `json:"t"`
+	N             string `json:"n"`
+	G             string `json:"g"`
+	H             string `json:"h"`
+	NExpY         string `json:"n_exp_y"`
+	NExpYMinusOne string `json:"n_exp_y_minus_one"`
+	Modulus       string `json:"modulus,omitempty"`
+}
+
+// AppendBinary appends the binary encoding of the protocol parameters to b.
+// The encoding consists of the header followed by y (as an 8 byte big-endian
+// integer) and the big integers t, n, g, h, n^y and n^(y - 1). The modulus
+// type is appended as a single byte unless it's ModulusRandomPrimes (which
+// keeps the encoding of such protocol parameters unchanged).
+// Returns an error if a protocol parameter is missing or if the modulus type
+// is unknown.
+func (p *Params) AppendBinary(b []byte) ([]byte, error) {
+	for _, x := range []*big.Int{p.T, p.N, p.G, p.H, p.NExpY, p.NExpYMinusOne} {
+		if x == nil {
+			return nil, ErrEncodeParams
+		}
+	}
+
+	if p.Y < 0 || !p.ModulusType.isValid() {
+		return nil, ErrEncodeParams
+	}
+
+	b = utils.AppendHeader(b, utils.TypeParams)
+	b = utils.AppendUint64(b, uint64(p.Y))
+	b = utils.AppendBigInt(b, p.T)
+	b = utils.AppendBigInt(b, p.N)
+	b = utils.AppendBigInt(b, p.G)
+	b = utils.AppendBigInt(b, p.H)
+	b = utils.AppendBigInt(b, p.NExpY)
+	b = utils.AppendBigInt(b, p.NExpYMinusOne)
+
+	if p.ModulusType != ModulusRandomPrimes {
+		b = append(b, byte(p.ModulusType))
+	}
+
+	return b, nil
+}
+
+// MarshalBinary encodes the protocol parameters into their binary form.
+// Returns an error if a protocol parameter is missing or if the modulus type
+// is unknown.
+func (p *Params) MarshalBinary() ([]byte, error) {
+	return p.AppendBinary(nil)
+}
+
+// UnmarshalBinary decodes the protocol parameters from their binary form.
+// Returns an error if the data isn't a canonical encoding of protocol
+// parameters.
+func (p *Params) UnmarshalBinary(data []byte) error {
+	d := utils.NewDecoder(data)
+
+	if err := d.ReadHeader(utils.TypeParams); err != nil {
+		return ErrDecodeParams
+	}
+
+	y, err := d.ReadUint64()
+	if err != nil || y > math.MaxInt32 {
+		return ErrDecodeParams
+	}
+
+	values := make([]*big.Int, 6)
+	for i := range values {
+		values[i], err = d.ReadBigInt()
+		if err != nil {
+			return ErrDecodeParams
+		}
+	}
+
+	// The modulus type is only encoded if it's not ModulusRandomPrimes.
+	modulusType := ModulusRandomPrimes
+	if d.Len() > 0 {
+		m, err := d.ReadByte()
+		modulusType = ModulusType(m)
+		if err != nil || modulusType == ModulusRandomPrimes || !modulusType.isValid() {
+			return ErrDecodeParams
+		}
+	}
+
+	if err := d.Finish(); err != nil {
+		return ErrDecodeParams
+	}
+
+	*p = *NewParams(int(y), values[0], values[1], values[2], values[3], values[4], values[5])
+	p.ModulusType = modulusType
+
+	return nil
+}
+
+// MarshalJSON encodes the protocol parameters into their JSON form.
+// The big integers are encoded as canonical hex strings and the modulus type
+// is omitted if it's ModulusRandomPrimes.
+// Returns an error if a protocol parameter is missing or if the modulus type
+// is unknown.
+func (p *Params) MarshalJSON() ([]byte, error) {
+	for _, x := range []*big.Int{p.T, p.N, p.G, p.H, p.NExpY, p.NExpYMinusOne} {
+		if x == nil {
+			return nil, ErrEncodeParams
+		}
+	}
+
+	if !p.ModulusType.isValid() {
+		return nil, ErrEncodeParams
+	}
+
+	var modulus string
+	if p.ModulusType != ModulusRandomPrimes {
+		modulus = p.ModulusType.String()
+	}
+
+	return json.Marshal(paramsJSON{
+		Version:       int(utils.EncodingVersion),
+		Y:             &p.Y,
+		T:             utils.BigIntToHex(p.T),
+		N:             utils.BigIntToHex(p.N),
+		G:             utils.BigIntToHex(p.G),
+		H:             utils.BigIntToHex(p.H),
+		NExpY:         utils.BigIntToHex(p.NExpY),
+		NExpYMinusOne: utils.BigIntToHex(p.NExpYMinusOne),
+		Modulus:       modulus,
+	})
+}
+
+// UnmarshalJSON decodes the protocol parameters from their JSON form.
+// Returns an error if the data isn't a canonical JSON encoding of protocol
+// parameters.
+func (p *Params) UnmarshalJSON(data []byte) error {
+	var v paramsJSON
+	if err := utils.DecodeJSON(data, &v); err != nil {
+		return ErrDecodeParams
+	}
+
+	if v.Version != int(utils.EncodingVersion) || v.Y == nil {
+		return ErrDecodeParams
+	}
+
+	values := make([]*big.Int, 6)
+	for i, s := range []string{v.T, v.N, v.G, v.H, v.NExpY, v.NExpYMinusOne} {
+		x, err := utils.HexToBigInt(s)
+		if err != nil {
+			return ErrDecodeParams
+		}
+		values[i] = x
+	}
+
+	// The modulus type is only encoded if it's not ModulusRandomPrimes.
+	modulusType := ModulusRandomPrimes
+	switch v.Modulus {
+	case "":
+	case ModulusSafePrimes.String():
+		modulusType = ModulusSafePrimes
+	default:
+		return ErrDecodeParams
+	}
+
+	*p = *NewParams(*v.Y, values[0], values[1], values[2], values[3], values[4], values[5])
+	p.ModulusType = modulusType
+
+	return nil
+}
+
+// MarshalText encodes the protocol parameters into their armored text form
+// which is a PEM block that contains the binary encoding.
+// Returns an error if a protocol parameter is missing or if the modulus type
+// is unknown.
+func (p *Params) MarshalText() ([]byte, error) {
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.Armor(armorType, data), nil
+}
+
+// UnmarshalText decodes the protocol parameters from their armored text form.
+// Returns an error if the text isn't a valid armored encoding of protocol
+// parameters.
+func (p *Params) UnmarshalText(text []byte) error {
+	data, err := utils.Dearmor(armorType, text)
+	if err != nil {
+		return ErrDecodeParams
+	}
+
+	return p.UnmarshalBinary(data)
+}
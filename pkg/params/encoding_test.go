@@ -0,0 +1,299 @@
+package params_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+func TestParamsBinaryEncoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Marshal / Unmarshal", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 3, big.NewInt(1_000))
+
+		data, err := params1.MarshalBinary()
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		var params2 params.Params
+		if err := params2.UnmarshalBinary(data); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if params2.Y != params1.Y {
+			t.Errorf("want %v, got %v", params1.Y, params2.Y)
+		}
+
+		want := []*big.Int{params1.T, params1.N, params1.G, params1.H, params1.NExpY, params1.NExpYMinusOne}
+		got := []*big.Int{params2.T, params2.N, params2.G, params2.H, params2.NExpY, params2.NExpYMinusOne}
+		for i := range want {
+			if got[i].Cmp(want[i]) != 0 {
+				t.Errorf("want %v, got %v", want[i], got[i])
+			}
+		}
+	})
+
+	t.Run("Marshal / Unmarshal - Safe Primes", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &params.GenerateOptions{SafePrimes: true}
+		params1, _ := params.GenerateParamsWithOptions(128, 2, big.NewInt(1_000), opts)
+
+		data, _ := params1.MarshalBinary()
+		if data[len(data)-1] != byte(params.ModulusSafePrimes) {
+			t.Errorf("want trailing modulus type %v, got %v", byte(params.ModulusSafePrimes), data[len(data)-1])
+		}
+
+		var params2 params.Params
+		if err := params2.UnmarshalBinary(data); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if params2.ModulusType != params.ModulusSafePrimes || params2.N.Cmp(params1.N) != 0 {
+			t.Errorf("want %v, got %v", params1, params2)
+		}
+	})
+
+	t.Run("Error when modulus type is encoded explicitly or unknown", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		data, _ := params1.MarshalBinary()
+
+		for _, m := range []byte{byte(params.ModulusRandomPrimes), 0x2a} {
+			var params2 params.Params
+			err := params2.UnmarshalBinary(append(bytes.Clone(data), m))
+
+			if !errors.Is(err, params.ErrDecodeParams) {
+				t.Errorf("want error %v, got %v", params.ErrDecodeParams, err)
+			}
+		}
+
+		params1.ModulusType = params.ModulusType(42)
+		if _, err := params1.MarshalBinary(); !errors.Is(err, params.ErrEncodeParams) {
+			t.Errorf("want error %v, got %v", params.ErrEncodeParams, err)
+		}
+	})
+
+	t.Run("Error when encoding contains trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		data, _ := params1.MarshalBinary()
+		data = append(data, 0x00)
+
+		var params2 params.Params
+		err := params2.UnmarshalBinary(data)
+
+		if !errors.Is(err, params.ErrDecodeParams) {
+			t.Errorf("want error %v, got %v", params.ErrDecodeParams, err)
+		}
+	})
+
+	t.Run("Error when encoding is truncated", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		data, _ := params1.MarshalBinary()
+
+		var params2 params.Params
+		err := params2.UnmarshalBinary(data[:len(data)-1])
+
+		if !errors.Is(err, params.ErrDecodeParams) {
+			t.Errorf("want error %v, got %v", params.ErrDecodeParams, err)
+		}
+	})
+
+	t.Run("Error when encoding has unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		data, _ := params1.MarshalBinary()
+		data[0] = 0xff
+
+		var params2 params.Params
+		err := params2.UnmarshalBinary(data)
+
+		if !errors.Is(err, params.ErrDecodeParams) {
+			t.Errorf("want error %v, got %v", params.ErrDecodeParams, err)
+		}
+	})
+
+	t.Run("Error when params are missing", func(t *testing.T) {
+		t.Parallel()
+
+		params1 := params.NewParams(2, big.NewInt(1), nil, nil, nil, nil, nil)
+
+		_, err := params1.MarshalBinary()
+
+		if !errors.Is(err, params.ErrEncodeParams) {
+			t.Errorf("want error %v, got %v", params.ErrEncodeParams, err)
+		}
+	})
+}
+
+func TestParamsTextEncoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Unmarshal / Marshal - Golden JSON", func(t *testing.T) {
+		t.Parallel()
+
+		golden, _ := os.ReadFile("../../testdata/params.json")
+
+		var params1 params.Params
+		if err := json.Unmarshal(golden, &params1); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		got, _ := json.Marshal(&params1)
+
+		var want bytes.Buffer
+		_ = json.Compact(&want, golden)
+
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Errorf("want %s, got %s", want.Bytes(), got)
+		}
+	})
+
+	t.Run("Unmarshal / Marshal - Golden Armored Text", func(t *testing.T) {
+		t.Parallel()
+
+		golden, _ := os.ReadFile("../../testdata/params.pem")
+
+		var params1 params.Params
+		if err := params1.UnmarshalText(golden); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		got, _ := params1.MarshalText()
+
+		if !bytes.Equal(got, golden) {
+			t.Errorf("want %s, got %s", golden, got)
+		}
+	})
+
+	t.Run("Golden JSON and Armored Text are equal", func(t *testing.T) {
+		t.Parallel()
+
+		goldenJSON, _ := os.ReadFile("../../testdata/params.json")
+		goldenText, _ := os.ReadFile("../../testdata/params.pem")
+
+		var params1 params.Params
+		_ = json.Unmarshal(goldenJSON, &params1)
+
+		var params2 params.Params
+		_ = params2.UnmarshalText(goldenText)
+
+		data1, _ := params1.MarshalBinary()
+		data2, _ := params2.MarshalBinary()
+
+		if !bytes.Equal(data1, data2) {
+			t.Errorf("want %v, got %v", data1, data2)
+		}
+	})
+
+	t.Run("Error when JSON contains unknown fields", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"version":1,"y":2,"t":"1","n":"1","g":"1","h":"1","n_exp_y":"1","n_exp_y_minus_one":"1","x":"1"}`)
+
+		var params1 params.Params
+		err := json.Unmarshal(data, &params1)
+
+		if !errors.Is(err, params.ErrDecodeParams) {
+			t.Errorf("want error %v, got %v", params.ErrDecodeParams, err)
+		}
+	})
+
+	t.Run("Error when JSON contains non-canonical hex", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"version":1,"y":2,"t":"01","n":"1","g":"1","h":"1","n_exp_y":"1","n_exp_y_minus_one":"1"}`)
+
+		var params1 params.Params
+		err := json.Unmarshal(data, &params1)
+
+		if !errors.Is(err, params.ErrDecodeParams) {
+			t.Errorf("want error %v, got %v", params.ErrDecodeParams, err)
+		}
+	})
+
+	t.Run("Error when JSON field is missing or duplicated", func(t *testing.T) {
+		t.Parallel()
+
+		for _, data := range []string{
+			`{"version":1,"t":"1","n":"1","g":"1","h":"1","n_exp_y":"1","n_exp_y_minus_one":"1"}`,
+			`{"version":1,"y":null,"t":"1","n":"1","g":"1","h":"1","n_exp_y":"1","n_exp_y_minus_one":"1"}`,
+			`{"version":1,"y":2,"n":"1","g":"1","h":"1","n_exp_y":"1","n_exp_y_minus_one":"1"}`,
+			`{"version":1,"y":2,"y":3,"t":"1","n":"1","g":"1","h":"1","n_exp_y":"1","n_exp_y_minus_one":"1"}`,
+		} {
+			var params1 params.Params
+			err := json.Unmarshal([]byte(data), &params1)
+
+			if !errors.Is(err, params.ErrDecodeParams) {
+				t.Errorf("want error %v, got %v", params.ErrDecodeParams, err)
+			}
+		}
+	})
+
+	t.Run("Marshal / Unmarshal JSON - Safe Primes", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &params.GenerateOptions{SafePrimes: true}
+		params1, _ := params.GenerateParamsWithOptions(128, 2, big.NewInt(1_000), opts)
+
+		data, _ := json.Marshal(params1)
+		if !bytes.Contains(data, []byte(`"modulus":"safe-primes"`)) {
+			t.Errorf("want modulus type in %s", data)
+		}
+
+		var params2 params.Params
+		if err := json.Unmarshal(data, &params2); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if params2.ModulusType != params.ModulusSafePrimes {
+			t.Errorf("want modulus type %v, got %v", params.ModulusSafePrimes, params2.ModulusType)
+		}
+	})
+
+	t.Run("Error when JSON contains explicit or unknown modulus type", func(t *testing.T) {
+		t.Parallel()
+
+		for _, modulus := range []string{"random-primes", "unknown"} {
+			data := []byte(`{"version":1,"y":2,"t":"1","n":"1","g":"1","h":"1","n_exp_y":"1","n_exp_y_minus_one":"1","modulus":"` + modulus + `"}`)
+
+			var params1 params.Params
+			err := json.Unmarshal(data, &params1)
+
+			if !errors.Is(err, params.ErrDecodeParams) {
+				t.Errorf("want error %v, got %v", params.ErrDecodeParams, err)
+			}
+		}
+	})
+
+	t.Run("Error when armored text has unexpected type", func(t *testing.T) {
+		t.Parallel()
+
+		golden, _ := os.ReadFile("../../testdata/puzzle.pem")
+
+		var params1 params.Params
+		err := params1.UnmarshalText(golden)
+
+		if !errors.Is(err, params.ErrDecodeParams) {
+			t.Errorf("want error %v, got %v", params.ErrDecodeParams, err)
+		}
+	})
+}
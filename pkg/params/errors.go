@@ -11,4 +11,40 @@ var (
 	ErrEqualPrimeNumbers = fmt.Errorf("equal prime numbers")
 	// ErrSampleGPrime is returned if the random g' can't be sampled.
 	ErrSampleGPrime = fmt.Errorf("unable to sample random g'")
+	// ErrMissingParams is returned if the protocol parameters are missing.
+	ErrMissingParams = fmt.Errorf("missing params")
+	// ErrInvalidY is returned if the exponent y is smaller than 2.
+	ErrInvalidY = fmt.Errorf("invalid exponent y")
+	// ErrInvalidModulusType is returned if the type of the modulus n is unknown.
+	ErrInvalidModulusType = fmt.Errorf("invalid modulus type")
+	// ErrInvalidT is returned if the difficulty t is missing or not positive.
+	ErrInvalidT = fmt.Errorf("invalid difficulty t")
+	// ErrInvalidN is returned if the modulus n is missing or too small.
+	ErrInvalidN = fmt.Errorf("invalid modulus n")
+	// ErrInvalidNExpY is returned if n^y is missing or inconsistent with n and y.
+	ErrInvalidNExpY = fmt.Errorf("invalid n^y")
+	// ErrInvalidNExpYMinusOne is returned if n^(y - 1) is missing or inconsistent with n and y.
+	ErrInvalidNExpYMinusOne = fmt.Errorf("invalid n^(y - 1)")
+	// ErrInvalidG is returned if g is missing, out of range or not coprime with n.
+	ErrInvalidG = fmt.Errorf("invalid g")
+	// ErrInvalidH is returned if h is missing, out of range or not coprime with n.
+	ErrInvalidH = fmt.Errorf("invalid h")
+	// ErrInvalidBits is returned if the bit length of a modulus is too small.
+	ErrInvalidBits = fmt.Errorf("invalid bit length")
+	// ErrInvalidDuration is returned if a duration is not positive.
+	ErrInvalidDuration = fmt.Errorf("invalid duration")
+	// ErrSampleModulus is returned if the random modulus for the calibration can't be sampled.
+	ErrSampleModulus = fmt.Errorf("unable to sample random modulus")
+	// ErrMissingMeasurement is returned if the calibration profile doesn't contain a measurement for a modulus size.
+	ErrMissingMeasurement = fmt.Errorf("missing measurement for modulus size")
+	// ErrDurationOverflow is returned if an estimated duration can't be represented.
+	ErrDurationOverflow = fmt.Errorf("duration overflow")
+	// ErrEncodeParams is returned if the protocol parameters can't be encoded.
+	ErrEncodeParams = fmt.Errorf("unable to encode params")
+	// ErrDecodeParams is returned if the protocol parameters can't be decoded.
+	ErrDecodeParams = fmt.Errorf("unable to decode params")
+	// ErrEncodeProfile is returned if the calibration profile can't be encoded.
+	ErrEncodeProfile = fmt.Errorf("unable to encode calibration profile")
+	// ErrDecodeProfile is returned if the calibration profile can't be decoded.
+	ErrDecodeProfile = fmt.Errorf("unable to decode calibration profile")
 )
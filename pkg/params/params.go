@@ -1,13 +1,47 @@
 package params
 
 import (
+	"context"
 	"crypto/rand"
+	"io"
 	"math/big"
+	"runtime"
 	"sync"
 
 	"github.com/primefactor-io/lhtlp/pkg/utils"
 )
 
+// ModulusType describes how the prime numbers p and q of the modulus n were
+// chosen.
+type ModulusType uint8
+
+const (
+	// ModulusRandomPrimes is the type of moduli whose prime numbers p and q are
+	// random primes (the default).
+	ModulusRandomPrimes ModulusType = iota
+	// ModulusSafePrimes is the type of moduli whose prime numbers p and q are
+	// safe primes p = 2p' + 1 and q = 2q' + 1 (where p' and q' are prime) which
+	// makes the group of quadratic residues modulo n cyclic of order p' * q'.
+	ModulusSafePrimes
+)
+
+// String returns the name of the modulus type.
+func (m ModulusType) String() string {
+	switch m {
+	case ModulusRandomPrimes:
+		return "random-primes"
+	case ModulusSafePrimes:
+		return "safe-primes"
+	default:
+		return "unknown"
+	}
+}
+
+// isValid checks if the modulus type is known.
+func (m ModulusType) isValid() bool {
+	return m == ModulusRandomPrimes || m == ModulusSafePrimes
+}
+
 // Params is an instance of protocol parameters.
 type Params struct {
 	// Y is the exponent.
@@ -24,6 +58,10 @@ type Params struct {
 	NExpY *big.Int
 	// NExpYMinusOne is the value n^(y - 1).
 	NExpYMinusOne *big.Int
+	// ModulusType is the type of the modulus n. It's recorded by the party that
+	// generated the protocol parameters and can't be verified without the
+	// factorization of n.
+	ModulusType ModulusType
 }
 
 // NewParams creates a new instance of protocol parameters.
@@ -39,56 +77,330 @@ func NewParams(y int, t, n, g, h, nExpY, nExpYMinusOne *big.Int) *Params {
 	}
 }
 
+// Validate checks if the protocol parameters are internally consistent.
+// Returns an error if the protocol parameters are missing or if a protocol
+// parameter is missing or invalid.
+func (p *Params) Validate() error {
+	if p == nil {
+		return ErrMissingParams
+	}
+
+	one := big.NewInt(1)
+
+	// Check if y >= 2.
+	if p.Y < 2 {
+		return ErrInvalidY
+	}
+
+	if !p.ModulusType.isValid() {
+		return ErrInvalidModulusType
+	}
+
+	// Check if t > 0.
+	if p.T == nil || p.T.Sign() <= 0 {
+		return ErrInvalidT
+	}
+
+	// Check if n > 1.
+	if p.N == nil || p.N.Cmp(one) <= 0 {
+		return ErrInvalidN
+	}
+
+	// Check the bit length of n^y before computing it so that a huge y can't
+	// be used to exhaust resources.
+	if p.NExpY == nil || !hasBitLenOfPower(p.NExpY, p.N, p.Y) {
+		return ErrInvalidNExpY
+	}
+
+	// Compute n^(y - 1) and n^y.
+	nExpYMinusOne, nExpY, _ := utils.Exponentiate(p.N, p.Y)
+
+	if p.NExpY.Cmp(nExpY) != 0 {
+		return ErrInvalidNExpY
+	}
+
+	if p.NExpYMinusOne == nil || p.NExpYMinusOne.Cmp(nExpYMinusOne) != 0 {
+		return ErrInvalidNExpYMinusOne
+	}
+
+	// Check if g is an element of {1, ..., n - 1} and gcd(g, n) = 1.
+	if !utils.IsUnit(p.G, p.N) {
+		return ErrInvalidG
+	}
+
+	// Check if h is an element of {1, ..., n - 1} and gcd(h, n) = 1.
+	if !utils.IsUnit(p.H, p.N) {
+		return ErrInvalidH
+	}
+
+	return nil
+}
+
+// hasBitLenOfPower checks if the bit length of x is within the bounds of the
+// bit length of n^y which is in [(bitlen(n) - 1) * y + 1, bitlen(n) * y].
+func hasBitLenOfPower(x, n *big.Int, y int) bool {
+	bitLen := int64(x.BitLen())
+	nBitLen := int64(n.BitLen())
+
+	lower := (nBitLen-1)*int64(y) + 1
+	upper := nBitLen * int64(y)
+
+	return bitLen >= lower && bitLen <= upper
+}
+
+// SecretParams is an instance of the secret protocol parameters (the trapdoor)
+// that are only known to the party that generated the protocol parameters.
+type SecretParams struct {
+	// P is the prime p.
+	P *big.Int
+	// Q is the prime q.
+	Q *big.Int
+	// PhiN is the value phi(n) = (p - 1) * (q - 1).
+	PhiN *big.Int
+}
+
+// NewSecretParams creates a new instance of secret protocol parameters.
+func NewSecretParams(p, q, phiN *big.Int) *SecretParams {
+	return &SecretParams{
+		P:    p,
+		Q:    q,
+		PhiN: phiN,
+	}
+}
+
+// GenerateOptions configures the generation of protocol parameters, puzzles
+// and proofs.
+type GenerateOptions struct {
+	// Rand is the source of randomness (defaults to crypto/rand.Reader).
+	// A custom source is read sequentially which is why the same source always
+	// results in the same protocol parameters.
+	Rand io.Reader
+	// SafePrimes makes p and q safe primes (see ModulusSafePrimes) which takes
+	// considerably longer to generate than random primes and requires moduli
+	// with at least 12 bits. It only applies to protocol parameters.
+	SafePrimes bool
+	// Workers is the number of goroutines that search for each safe prime in
+	// parallel. Defaults to the number of CPUs if not set. A custom source of
+	// randomness is always read by a single goroutine.
+	Workers int
+}
+
+// Reader returns the configured source of randomness.
+func (o *GenerateOptions) Reader() io.Reader {
+	if o == nil || o.Rand == nil {
+		return rand.Reader
+	}
+
+	return o.Rand
+}
+
+// workers returns the configured number of goroutines per safe prime search
+// (which is 1 for a custom source of randomness).
+func (o *GenerateOptions) workers() int {
+	if o != nil && o.Rand != nil {
+		return 1
+	}
+
+	if o == nil || o.Workers <= 0 {
+		return runtime.NumCPU()
+	}
+
+	return o.Workers
+}
+
+// modulusType returns the configured type of the modulus.
+func (o *GenerateOptions) modulusType() ModulusType {
+	if o != nil && o.SafePrimes {
+		return ModulusSafePrimes
+	}
+
+	return ModulusRandomPrimes
+}
+
+// generate generates a prime number of the configured type with the bit
+// length. Random primes are generated via crypto/rand.Prime unless a custom
+// source of randomness is configured.
+// Returns an error if the bit length is invalid or if the source of randomness
+// fails.
+func (o *GenerateOptions) generate(random io.Reader, bits int) (*big.Int, error) {
+	if o.modulusType() == ModulusSafePrimes {
+		return generateSafePrime(random, bits, o.workers())
+	}
+
+	if o == nil || o.Rand == nil {
+		return rand.Prime(random, bits)
+	}
+
+	return generatePrime(random, bits)
+}
+
 // GenerateParams generates protocol parameters based on the desired security
 // (expressed in bits) and difficulty.
-// Returns an error if the generation of the protocol parameters fails.
+// Returns an error if the exponent y is smaller than 2, if the difficulty isn't
+// positive or if the generation of the protocol parameters fails.
 func GenerateParams(bits, y int, difficulty *big.Int) (*Params, error) {
+	return GenerateParamsWithOptions(bits, y, difficulty, nil)
+}
+
+// GenerateParamsWithOptions generates protocol parameters like GenerateParams
+// while using the options (which can be nil).
+// Returns an error if the exponent y is smaller than 2, if the difficulty isn't
+// positive, if safe primes are requested for a modulus with less than 12 bits
+// or if the generation of the protocol parameters fails.
+func GenerateParamsWithOptions(bits, y int, difficulty *big.Int, opts *GenerateOptions) (*Params, error) {
+	params, _, err := GenerateParamsWithTrapdoorAndOptions(bits, y, difficulty, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// GenerateParamsWithTrapdoor generates protocol parameters based on the desired
+// security (expressed in bits) and difficulty while also returning the secret
+// protocol parameters that can be used to solve puzzles without doing the
+// sequential computation.
+// Returns an error if the exponent y is smaller than 2, if the difficulty isn't
+// positive or if the generation of the protocol parameters fails.
+func GenerateParamsWithTrapdoor(bits, y int, difficulty *big.Int) (*Params, *SecretParams, error) {
+	return GenerateParamsWithTrapdoorAndOptions(bits, y, difficulty, nil)
+}
+
+// GenerateParamsContext generates protocol parameters like
+// GenerateParamsWithOptions while stopping early once the context is canceled.
+// Returns an error if the exponent y is smaller than 2, if the difficulty isn't
+// positive, if safe primes are requested for a modulus with less than 12 bits,
+// if the generation of the protocol parameters fails or the context's error
+// once the context is canceled.
+func GenerateParamsContext(ctx context.Context, bits, y int, difficulty *big.Int, opts *GenerateOptions) (*Params, error) {
+	params, _, err := generateParams(ctx, bits, y, difficulty, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// GenerateParamsWithTrapdoorAndOptions generates protocol parameters and the
+// secret protocol parameters like GenerateParamsWithTrapdoor while using the
+// options (which can be nil).
+// Returns an error if the exponent y is smaller than 2, if the difficulty isn't
+// positive, if safe primes are requested for a modulus with less than 12 bits
+// or if the generation of the protocol parameters fails.
+func GenerateParamsWithTrapdoorAndOptions(bits, y int, difficulty *big.Int, opts *GenerateOptions) (*Params, *SecretParams, error) {
+	// The background context is never canceled.
+	return generateParams(context.Background(), bits, y, difficulty, opts)
+}
+
+// generateParams generates protocol parameters and the secret protocol
+// parameters while stopping early once the context is canceled.
+// Returns an error if the exponent y is smaller than 2, if the difficulty isn't
+// positive, if safe primes are requested for a modulus with less than 12 bits,
+// if the generation of the protocol parameters fails or the context's error
+// once the context is canceled.
+func generateParams(ctx context.Context, bits, y int, difficulty *big.Int, opts *GenerateOptions) (*Params, *SecretParams, error) {
+	// Check if y >= 2.
+	if y < 2 {
+		return nil, nil, ErrInvalidY
+	}
+
+	// Check if t > 0.
+	if difficulty == nil || difficulty.Sign() <= 0 {
+		return nil, nil, ErrInvalidT
+	}
+
 	// Prime numbers p and q should have roughly the same size.
 	primeBits := bits / 2
 
+	// Check if safe primes with the bit length exist.
+	if opts.modulusType() == ModulusSafePrimes && primeBits < minSafePrimeBits {
+		return nil, nil, ErrInvalidBits
+	}
+
+	random := opts.Reader()
+	generate := opts.generate
+
+	// Only crypto/rand.Reader is safe for concurrent use.
+	concurrent := random == rand.Reader
+
+	// Every sampling step reads from the source of randomness which is why
+	// failing reads stop the generation once the context is canceled.
+	if ctx.Done() != nil {
+		random = &contextReader{ctx: ctx, r: random}
+	}
+
 	// Generate prime numbers p and q.
 	var p *big.Int
 	var q *big.Int
-	errCh := make(chan error, 2)
 
-	var wg sync.WaitGroup
-	wg.Add(2)
+	if concurrent {
+		errCh := make(chan error, 2)
 
-	// Generate prime p.
-	go func() {
-		defer wg.Done()
+		var wg sync.WaitGroup
+		wg.Add(2)
 
-		var err error
-		p, err = rand.Prime(rand.Reader, primeBits)
-		if err != nil {
-			err = ErrGeneratePrimeP
-		}
+		// Generate prime p.
+		go func() {
+			defer wg.Done()
+
+			var err error
+			p, err = generate(random, primeBits)
+			if err != nil {
+				err = ErrGeneratePrimeP
+			}
+
+			errCh <- err
+		}()
+
+		// Generate prime q.
+		go func() {
+			defer wg.Done()
+
+			var err error
+			q, err = generate(random, primeBits)
+			if err != nil {
+				err = ErrGeneratePrimeQ
+			}
 
-		errCh <- err
-	}()
+			errCh <- err
+		}()
 
-	// Generate prime q.
-	go func() {
-		defer wg.Done()
+		wg.Wait()
+		close(errCh)
 
+		// Both results are checked as p or q is missing if either one fails.
+		for err := range errCh {
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, nil, ctx.Err()
+				}
+				return nil, nil, err
+			}
+		}
+	} else {
+		// A custom source of randomness might not be safe for concurrent use
+		// and has to be read in a fixed order to be reproducible.
 		var err error
-		q, err = rand.Prime(rand.Reader, primeBits)
+		p, err = generate(random, primeBits)
 		if err != nil {
-			err = ErrGeneratePrimeQ
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			return nil, nil, ErrGeneratePrimeP
 		}
 
-		errCh <- err
-	}()
-
-	wg.Wait()
-
-	if err := <-errCh; err != nil {
-		return nil, err
+		q, err = generate(random, primeBits)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			return nil, nil, ErrGeneratePrimeQ
+		}
 	}
 
 	// Check if prime numbers are equal.
 	if p.Cmp(q) == 0 {
-		return nil, ErrEqualPrimeNumbers
+		return nil, nil, ErrEqualPrimeNumbers
 	}
 
 	t := difficulty
@@ -105,9 +417,12 @@ func GenerateParams(bits, y int, difficulty *big.Int) (*Params, error) {
 	phiNHalf := new(big.Int).Div(phiN, big.NewInt(2)) // phiN / 2
 
 	// Randomly sample g'.
-	gPrime, err := rand.Int(rand.Reader, nMinusOne)
+	gPrime, err := rand.Int(random, nMinusOne)
 	if err != nil {
-		return nil, ErrSampleGPrime
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		return nil, nil, ErrSampleGPrime
 	}
 
 	// Compute g.
@@ -121,6 +436,79 @@ func GenerateParams(bits, y int, difficulty *big.Int) (*Params, error) {
 	h := new(big.Int).Exp(g, hPrime, n) // g^(2^t) mod n
 
 	params := NewParams(y, t, n, g, h, nExpY, nExpYMinusOne)
+	params.ModulusType = opts.modulusType()
+	secret := NewSecretParams(p, q, phiN)
 
-	return params, nil
+	return params, secret, nil
+}
+
+// generatePrime generates a prime number with the bit length (which has to be
+// at least 2) that has its two most significant bits set. Unlike rand.Prime
+// the source of randomness is always used which is why the result is
+// reproducible with a custom source.
+// Returns an error if the source of randomness fails.
+func generatePrime(random io.Reader, bits int) (*big.Int, error) {
+	if bits < 2 {
+		return nil, ErrInvalidBits
+	}
+
+	for {
+		p, err := generateCandidate(random, bits)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// generateCandidate samples an odd number with the bit length (which has to be
+// at least 2) that has its two most significant bits set.
+// Returns an error if the source of randomness fails.
+func generateCandidate(random io.Reader, bits int) (*big.Int, error) {
+	b := uint(bits % 8)
+	if b == 0 {
+		b = 8
+	}
+
+	bytes := make([]byte, (bits+7)/8)
+	if _, err := io.ReadFull(random, bytes); err != nil {
+		return nil, err
+	}
+
+	// Clear the bits that exceed the bit length and set the two most
+	// significant bits so that the product of two primes has twice the bit
+	// length.
+	bytes[0] &= uint8(int(1<<b) - 1)
+	if b >= 2 {
+		bytes[0] |= 3 << (b - 2)
+	} else {
+		bytes[0] |= 1
+		if len(bytes) > 1 {
+			bytes[1] |= 0x80
+		}
+	}
+	// Make the value odd.
+	bytes[len(bytes)-1] |= 1
+
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+// contextReader is a source of randomness that fails once the context is
+// canceled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+// Read reads from the underlying source of randomness.
+// Returns the context's error once the context is canceled.
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return c.r.Read(p)
 }
@@ -1,9 +1,14 @@
 package params_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"math/big"
+	mrand "math/rand/v2"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/primefactor-io/lhtlp/pkg/params"
 )
@@ -20,4 +25,259 @@ func TestParamsGeneration(t *testing.T) {
 			t.Errorf("want error %v, got %v", params.ErrEqualPrimeNumbers, err)
 		}
 	})
+	t.Run("Generate Params With Trapdoor", func(t *testing.T) {
+		t.Parallel()
+
+		params, secret, _ := params.GenerateParamsWithTrapdoor(128, 2, big.NewInt(1))
+
+		n := new(big.Int).Mul(secret.P, secret.Q) // p * q
+		if n.Cmp(params.N) != 0 {
+			t.Errorf("want %v, got %v", params.N, n)
+		}
+
+		pMinusOne := new(big.Int).Sub(secret.P, big.NewInt(1)) // p - 1
+		qMinusOne := new(big.Int).Sub(secret.Q, big.NewInt(1)) // q - 1
+		phiN := new(big.Int).Mul(pMinusOne, qMinusOne)         // (p - 1) * (q - 1)
+		if phiN.Cmp(secret.PhiN) != 0 {
+			t.Errorf("want %v, got %v", phiN, secret.PhiN)
+		}
+	})
+
+	t.Run("Generate Params With Custom Randomness", func(t *testing.T) {
+		t.Parallel()
+
+		generate := func(seed byte) []byte {
+			opts := &params.GenerateOptions{Rand: mrand.NewChaCha8([32]byte{seed})}
+			params, _ := params.GenerateParamsWithOptions(128, 2, big.NewInt(1), opts)
+			data, _ := params.MarshalBinary()
+			return data
+		}
+
+		if !bytes.Equal(generate(1), generate(1)) {
+			t.Error("want equal params for the same randomness")
+		}
+		if bytes.Equal(generate(1), generate(2)) {
+			t.Error("want different params for different randomness")
+		}
+	})
+
+	t.Run("Generate Params With Safe Primes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, workers := range []int{1, 4} {
+			opts := &params.GenerateOptions{SafePrimes: true, Workers: workers}
+			params1, secret, err := params.GenerateParamsWithTrapdoorAndOptions(128, 2, big.NewInt(1), opts)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if params1.ModulusType != params.ModulusSafePrimes {
+				t.Errorf("want modulus type %v, got %v", params.ModulusSafePrimes, params1.ModulusType)
+			}
+
+			if params1.N.BitLen() != 128 {
+				t.Errorf("want bit length %v, got %v", 128, params1.N.BitLen())
+			}
+
+			for _, prime := range []*big.Int{secret.P, secret.Q} {
+				primePrime := new(big.Int).Rsh(prime, 1) // (p - 1) / 2
+				if !prime.ProbablyPrime(20) || !primePrime.ProbablyPrime(20) {
+					t.Errorf("want safe prime, got %v", prime)
+				}
+			}
+
+			if err := params1.Validate(); err != nil {
+				t.Errorf("want no error, got %v", err)
+			}
+		}
+	})
+
+	t.Run("Error when modulus is too small for safe primes", func(t *testing.T) {
+		t.Parallel()
+
+		for _, workers := range []int{1, 4} {
+			opts := &params.GenerateOptions{SafePrimes: true, Workers: workers}
+
+			for bits := 0; bits < 12; bits++ {
+				_, err := params.GenerateParamsWithOptions(bits, 2, big.NewInt(1), opts)
+
+				if !errors.Is(err, params.ErrInvalidBits) {
+					t.Errorf("%v bits: want error %v, got %v", bits, params.ErrInvalidBits, err)
+				}
+			}
+
+			// There's only a single safe prime with 6 bits.
+			_, err := params.GenerateParamsWithOptions(12, 2, big.NewInt(1), opts)
+
+			if !errors.Is(err, params.ErrEqualPrimeNumbers) {
+				t.Errorf("want error %v, got %v", params.ErrEqualPrimeNumbers, err)
+			}
+		}
+	})
+
+	t.Run("Generate Params With Random Primes by default", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		if params1.ModulusType != params.ModulusRandomPrimes {
+			t.Errorf("want modulus type %v, got %v", params.ModulusRandomPrimes, params1.ModulusType)
+		}
+	})
+
+	t.Run("Generate Params With Safe Primes and Custom Randomness", func(t *testing.T) {
+		t.Parallel()
+
+		generate := func(seed byte) []byte {
+			opts := &params.GenerateOptions{Rand: mrand.NewChaCha8([32]byte{seed}), SafePrimes: true}
+			params, _ := params.GenerateParamsWithOptions(128, 2, big.NewInt(1), opts)
+			data, _ := params.MarshalBinary()
+			return data
+		}
+
+		if !bytes.Equal(generate(1), generate(1)) {
+			t.Error("want equal params for the same randomness")
+		}
+		if bytes.Equal(generate(1), generate(2)) {
+			t.Error("want different params for different randomness")
+		}
+	})
+
+	t.Run("Error when randomness fails", func(t *testing.T) {
+		t.Parallel()
+
+		opts := &params.GenerateOptions{Rand: iotest.ErrReader(errors.New("failure"))}
+		_, err := params.GenerateParamsWithOptions(128, 2, big.NewInt(1), opts)
+
+		if !errors.Is(err, params.ErrGeneratePrimeP) {
+			t.Errorf("want error %v, got %v", params.ErrGeneratePrimeP, err)
+		}
+	})
+
+	t.Run("Error when context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		for _, opts := range []*params.GenerateOptions{nil, {SafePrimes: true}, {Rand: mrand.NewChaCha8([32]byte{})}} {
+			_, err := params.GenerateParamsContext(ctx, 128, 2, big.NewInt(1), opts)
+
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("want error %v, got %v", context.Canceled, err)
+			}
+		}
+	})
+
+	t.Run("Error when context is canceled during generation", func(t *testing.T) {
+		t.Parallel()
+
+		// The context is canceled at different points of the generation of p
+		// and q (which run concurrently).
+		for _, delay := range []time.Duration{0, time.Millisecond, 5 * time.Millisecond, 20 * time.Millisecond} {
+			ctx, cancel := context.WithTimeout(context.Background(), delay)
+			params1, err := params.GenerateParamsContext(ctx, 1024, 2, big.NewInt(1), nil)
+			cancel()
+
+			if (err == nil && params1 == nil) || (err != nil && !errors.Is(err, context.DeadlineExceeded)) {
+				t.Errorf("want params or error %v, got %v", context.DeadlineExceeded, err)
+			}
+		}
+	})
+
+	t.Run("Error when y or difficulty are invalid", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name       string
+			y          int
+			difficulty *big.Int
+			want       error
+		}{
+			{"y = 1", 1, big.NewInt(1), params.ErrInvalidY},
+			{"y = 0", 0, big.NewInt(1), params.ErrInvalidY},
+			{"t missing", 2, nil, params.ErrInvalidT},
+			{"t = 0", 2, big.NewInt(0), params.ErrInvalidT},
+			{"t < 0", 2, big.NewInt(-1), params.ErrInvalidT},
+		}
+
+		for _, tt := range tests {
+			_, _, err := params.GenerateParamsWithTrapdoor(64, tt.y, tt.difficulty)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("%v: want error %v, got %v", tt.name, tt.want, err)
+			}
+		}
+	})
+}
+
+func TestParamsValidation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid Params", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 3, big.NewInt(1))
+
+		if err := params1.Validate(); err != nil {
+			t.Errorf("want no error, got %v", err)
+		}
+	})
+
+	t.Run("Error when params are invalid", func(t *testing.T) {
+		t.Parallel()
+
+		p, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		nPlusOne := new(big.Int).Add(p.N, big.NewInt(1)) // n + 1
+		nExpY := new(big.Int).Mul(p.NExpY, p.N)          // n^(y + 1)
+
+		tests := []struct {
+			name   string
+			params *params.Params
+			want   error
+		}{
+			{"y < 2", params.NewParams(1, p.T, p.N, p.G, p.H, p.N, big.NewInt(1)), params.ErrInvalidY},
+			{"t = 0", params.NewParams(p.Y, big.NewInt(0), p.N, p.G, p.H, p.NExpY, p.NExpYMinusOne), params.ErrInvalidT},
+			{"t missing", params.NewParams(p.Y, nil, p.N, p.G, p.H, p.NExpY, p.NExpYMinusOne), params.ErrInvalidT},
+			{"n = 1", params.NewParams(p.Y, p.T, big.NewInt(1), p.G, p.H, p.NExpY, p.NExpYMinusOne), params.ErrInvalidN},
+			{"n^y inconsistent", params.NewParams(p.Y, p.T, p.N, p.G, p.H, nExpY, p.NExpYMinusOne), params.ErrInvalidNExpY},
+			{"n^y off by one", params.NewParams(p.Y, p.T, p.N, p.G, p.H, new(big.Int).Add(p.NExpY, big.NewInt(1)), p.NExpYMinusOne), params.ErrInvalidNExpY},
+			{"n^(y - 1) inconsistent", params.NewParams(p.Y, p.T, p.N, p.G, p.H, p.NExpY, nPlusOne), params.ErrInvalidNExpYMinusOne},
+			{"g = 0", params.NewParams(p.Y, p.T, p.N, big.NewInt(0), p.H, p.NExpY, p.NExpYMinusOne), params.ErrInvalidG},
+			{"g >= n", params.NewParams(p.Y, p.T, p.N, nPlusOne, p.H, p.NExpY, p.NExpYMinusOne), params.ErrInvalidG},
+			{"gcd(g, n) != 1", params.NewParams(p.Y, p.T, p.N, p.NExpYMinusOne, p.H, p.NExpY, p.NExpYMinusOne), params.ErrInvalidG},
+			{"h missing", params.NewParams(p.Y, p.T, p.N, p.G, nil, p.NExpY, p.NExpYMinusOne), params.ErrInvalidH},
+			{"h >= n", params.NewParams(p.Y, p.T, p.N, p.G, p.N, p.NExpY, p.NExpYMinusOne), params.ErrInvalidH},
+		}
+
+		for _, tt := range tests {
+			err := tt.params.Validate()
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("%v: want error %v, got %v", tt.name, tt.want, err)
+			}
+		}
+	})
+
+	t.Run("Error when params are missing", func(t *testing.T) {
+		t.Parallel()
+
+		var params1 *params.Params
+
+		if err := params1.Validate(); !errors.Is(err, params.ErrMissingParams) {
+			t.Errorf("want error %v, got %v", params.ErrMissingParams, err)
+		}
+	})
+
+	t.Run("Error when modulus type is unknown", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		params1.ModulusType = params.ModulusType(42)
+
+		if err := params1.Validate(); !errors.Is(err, params.ErrInvalidModulusType) {
+			t.Errorf("want error %v, got %v", params.ErrInvalidModulusType, err)
+		}
+	})
 }
@@ -0,0 +1,121 @@
+package params
+
+import (
+	"io"
+	"math/big"
+	"sync"
+)
+
+// minSafePrimeBits is the minimum bit length of safe primes. There are no safe
+// primes with 4 or 5 bits whose two most significant bits are set while every
+// bit length from 6 on has at least one.
+const minSafePrimeBits = 6
+
+// sieveMinBits is the minimum bit length of safe primes whose candidates are
+// sieved with the small primes (smaller candidates might be small primes).
+const sieveMinBits = 16
+
+// smallPrimes are the odd primes below 1024 which are used to discard most
+// candidates for safe primes without running a primality test.
+var smallPrimes = func() []*big.Int {
+	var primes []*big.Int
+	for x := int64(3); x < 1024; x += 2 {
+		if prime := big.NewInt(x); prime.ProbablyPrime(0) {
+			primes = append(primes, prime)
+		}
+	}
+	return primes
+}()
+
+// generateSafePrime generates a safe prime p = 2p' + 1 (where p' is prime)
+// with the bit length (which has to be at least minSafePrimeBits) that has its
+// two most significant bits set. The search runs on the number of workers (which has to
+// be 1 for a source of randomness that isn't safe for concurrent use).
+// Returns an error if the bit length is invalid or if the source of randomness
+// fails.
+func generateSafePrime(random io.Reader, bits, workers int) (*big.Int, error) {
+	if bits < minSafePrimeBits {
+		return nil, ErrInvalidBits
+	}
+
+	if workers <= 1 {
+		return searchSafePrime(random, bits, nil)
+	}
+
+	type result struct {
+		p   *big.Int
+		err error
+	}
+
+	done := make(chan struct{})
+	results := make(chan result, workers)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			p, err := searchSafePrime(random, bits, done)
+			results <- result{p, err}
+		}()
+	}
+
+	// The first worker that finishes stops the others.
+	r := <-results
+	close(done)
+	wg.Wait()
+
+	return r.p, r.err
+}
+
+// searchSafePrime samples candidates p' with bits - 1 bits until p' and
+// p = 2p' + 1 are prime or until done is closed (in which case nil is
+// returned).
+// Returns an error if the source of randomness fails.
+func searchSafePrime(random io.Reader, bits int, done <-chan struct{}) (*big.Int, error) {
+	p := new(big.Int)
+
+	for {
+		select {
+		case <-done:
+			return nil, nil
+		default:
+		}
+
+		// Two most significant bits of p' are set which is why the same holds
+		// for p = 2p' + 1.
+		pPrime, err := generateCandidate(random, bits-1)
+		if err != nil {
+			return nil, err
+		}
+
+		if bits > sieveMinBits && !passesSieve(pPrime) {
+			continue
+		}
+
+		p.Lsh(pPrime, 1).SetBit(p, 0, 1) // 2p' + 1
+
+		// A composite p or p' is detected by the first Miller-Rabin round in
+		// most cases which is why the expensive rounds only run for primes.
+		if p.ProbablyPrime(20) && pPrime.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// passesSieve checks if neither p' nor 2p' + 1 is divisible by one of the
+// small primes.
+func passesSieve(pPrime *big.Int) bool {
+	m := new(big.Int)
+	for _, prime := range smallPrimes {
+		m.Mod(pPrime, prime) // p' mod r
+
+		// Check if r divides p' or 2p' + 1.
+		if x := m.Uint64(); x == 0 || (2*x+1)%prime.Uint64() == 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,100 @@
+package plaintext
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+// lengthSize is the size (in bytes) of the length prefix of an encoded
+// message.
+const lengthSize = 8
+
+// ChunkSize returns the number of message bytes that are encoded per plaintext
+// value which is the largest number of bytes that is always smaller than
+// n^(y - 1).
+// Note: The caller needs to ensure that the protocol parameters are valid.
+func ChunkSize(params *params.Params) int {
+	return (params.NExpYMinusOne.BitLen() - 1) / 8
+}
+
+// EncodeBytes encodes the message into plaintext values. The message is
+// prefixed with its length (as an 8 byte big-endian integer), padded with zero
+// bytes to a multiple of the chunk size (see ChunkSize) and split into chunks
+// which are interpreted as big-endian integers. The length prefix ensures that
+// trailing zero bytes of the message are preserved.
+// Returns an error if the protocol parameters are invalid or if the message
+// space is too small to hold a single byte.
+func EncodeBytes(params *params.Params, message []byte) ([]*big.Int, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	size := ChunkSize(params)
+	if size < 1 {
+		return nil, ErrMessageSpaceTooSmall
+	}
+
+	data := binary.BigEndian.AppendUint64(nil, uint64(len(message)))
+	data = append(data, message...)
+
+	numChunks := (len(data) + size - 1) / size
+	data = append(data, make([]byte, numChunks*size-len(data))...)
+
+	plaintexts := make([]*big.Int, numChunks)
+	for i := range plaintexts {
+		plaintexts[i] = new(big.Int).SetBytes(data[i*size : (i+1)*size])
+	}
+
+	return plaintexts, nil
+}
+
+// DecodeBytes decodes the message from the (solved) plaintext values that were
+// encoded via EncodeBytes.
+// Returns an error if the protocol parameters are invalid or if the plaintext
+// values aren't a canonical encoding of a message.
+func DecodeBytes(params *params.Params, plaintexts []*big.Int) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	size := ChunkSize(params)
+	if size < 1 {
+		return nil, ErrMessageSpaceTooSmall
+	}
+
+	data := make([]byte, len(plaintexts)*size)
+	for i, m := range plaintexts {
+		if m == nil || m.Sign() < 0 || (m.BitLen()+7)/8 > size {
+			return nil, ErrInvalidEncoding
+		}
+
+		m.FillBytes(data[i*size : (i+1)*size])
+	}
+
+	if len(data) < lengthSize {
+		return nil, ErrInvalidEncoding
+	}
+
+	length := binary.BigEndian.Uint64(data)
+	data = data[lengthSize:]
+
+	if length > uint64(len(data)) {
+		return nil, ErrInvalidEncoding
+	}
+
+	// The padding has to fit into the last chunk and has to consist of zeros.
+	padding := data[length:]
+	if len(padding) >= size {
+		return nil, ErrInvalidEncoding
+	}
+
+	for _, b := range padding {
+		if b != 0 {
+			return nil, ErrInvalidEncoding
+		}
+	}
+
+	return data[:length], nil
+}
@@ -0,0 +1,65 @@
+package plaintext_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/plaintext"
+)
+
+func TestBytes(t *testing.T) {
+	t.Parallel()
+
+	params, _ := params.GenerateParams(128, 3, big.NewInt(1))
+	size := plaintext.ChunkSize(params)
+
+	t.Run("Encode / Decode", func(t *testing.T) {
+		t.Parallel()
+
+		for _, length := range []int{0, 1, size - 8, size - 7, size, 3*size + 1} {
+			message := bytes.Repeat([]byte{0xff, 0x00}, length)[:length]
+
+			plaintexts, err := plaintext.EncodeBytes(params, message)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			numChunks := (8 + length + size - 1) / size
+			if len(plaintexts) != numChunks {
+				t.Errorf("want %v chunks, got %v", numChunks, len(plaintexts))
+			}
+
+			for _, m := range plaintexts {
+				if m.Cmp(params.NExpYMinusOne) >= 0 {
+					t.Errorf("want value in message space, got %v", m)
+				}
+			}
+
+			result, _ := plaintext.DecodeBytes(params, plaintexts)
+
+			if !bytes.Equal(result, message) {
+				t.Errorf("want %v, got %v", message, result)
+			}
+		}
+	})
+
+	t.Run("Error when encoding is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		plaintexts, _ := plaintext.EncodeBytes(params, []byte("hello"))
+		extraChunk := append(plaintexts, big.NewInt(0))
+		tooLarge := []*big.Int{params.NExpYMinusOne}
+		nonZeroPadding := []*big.Int{new(big.Int).Add(plaintexts[0], big.NewInt(1))}
+
+		for _, p := range [][]*big.Int{nil, extraChunk, tooLarge, nonZeroPadding} {
+			_, err := plaintext.DecodeBytes(params, p)
+
+			if !errors.Is(err, plaintext.ErrInvalidEncoding) {
+				t.Errorf("want error %v, got %v", plaintext.ErrInvalidEncoding, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package plaintext
+
+import "fmt"
+
+var (
+	// ErrOutOfRange is returned if a value can't be encoded or decoded because it's out of range.
+	ErrOutOfRange = fmt.Errorf("value out of range")
+	// ErrInvalidScale is returned if the scale of a fixed-point encoding is negative or too large.
+	ErrInvalidScale = fmt.Errorf("invalid scale")
+	// ErrInexact is returned if a value has more fractional digits than the scale allows.
+	ErrInexact = fmt.Errorf("value can't be represented exactly")
+	// ErrInvalidDecimal is returned if a string isn't a decimal number.
+	ErrInvalidDecimal = fmt.Errorf("invalid decimal number")
+	// ErrMessageSpaceTooSmall is returned if the message space can't hold a single byte.
+	ErrMessageSpaceTooSmall = fmt.Errorf("message space too small")
+	// ErrInvalidEncoding is returned if plaintext values aren't a canonical encoding of a message.
+	ErrInvalidEncoding = fmt.Errorf("invalid message encoding")
+)
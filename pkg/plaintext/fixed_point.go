@@ -0,0 +1,112 @@
+package plaintext
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+// FixedPoint is a fixed-point encoding of decimal values with a fixed number
+// of fractional digits. A value x is encoded as the signed integer
+// x * 10^scale (see EncodeSigned).
+type FixedPoint struct {
+	// Scale is the number of fractional digits.
+	Scale int
+}
+
+// NewFixedPoint creates a new fixed-point encoding with the number of
+// fractional digits.
+func NewFixedPoint(scale int) *FixedPoint {
+	return &FixedPoint{
+		Scale: scale,
+	}
+}
+
+// Validate checks if the scale is not negative and if 10^scale fits into the
+// message space {0, ..., n^(y - 1) - 1}.
+// Returns an error if the protocol parameters or the scale are invalid.
+func (f *FixedPoint) Validate(params *params.Params) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	// Check if scale >= 0 and bitlen(10^scale) <= bitlen(n^(y - 1)).
+	maxScale := float64(params.NExpYMinusOne.BitLen()) / math.Log2(10)
+	if f.Scale < 0 || float64(f.Scale) > maxScale {
+		return ErrInvalidScale
+	}
+
+	return nil
+}
+
+// Factor returns the scale factor 10^scale.
+func (f *FixedPoint) Factor() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(f.Scale)), nil)
+}
+
+// Encode maps the decimal value into the message space by encoding the signed
+// integer x * 10^scale.
+// Returns an error if the protocol parameters or the scale are invalid, if the
+// value has more fractional digits than the scale allows or if the scaled
+// value is out of range (see EncodeSigned).
+func (f *FixedPoint) Encode(params *params.Params, x *big.Rat) (*big.Int, error) {
+	if err := f.Validate(params); err != nil {
+		return nil, err
+	}
+
+	if x == nil {
+		return nil, ErrOutOfRange
+	}
+
+	scaled := new(big.Rat).Mul(x, new(big.Rat).SetInt(f.Factor())) // x * 10^scale
+	if !scaled.IsInt() {
+		return nil, ErrInexact
+	}
+
+	return EncodeSigned(params, scaled.Num())
+}
+
+// EncodeString parses the decimal string (e.g. "-12.34") and maps the value
+// into the message space like Encode.
+// Returns an error if the string isn't a decimal number or if the value can't
+// be encoded.
+func (f *FixedPoint) EncodeString(params *params.Params, s string) (*big.Int, error) {
+	x, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, ErrInvalidDecimal
+	}
+
+	return f.Encode(params, x)
+}
+
+// Decode maps the (solved) plaintext value back into the decimal value
+// x = m / 10^scale where m is the signed integer (see DecodeSigned).
+// Returns an error if the protocol parameters or the scale are invalid or if
+// the plaintext value isn't an element of {0, ..., n^(y - 1) - 1}.
+func (f *FixedPoint) Decode(params *params.Params, m *big.Int) (*big.Rat, error) {
+	if err := f.Validate(params); err != nil {
+		return nil, err
+	}
+
+	signed, err := DecodeSigned(params, m)
+	if err != nil {
+		return nil, err
+	}
+
+	x := new(big.Rat).SetFrac(signed, f.Factor()) // m / 10^scale
+
+	return x, nil
+}
+
+// DecodeString maps the (solved) plaintext value back into a decimal string
+// with exactly scale fractional digits (e.g. "-12.34").
+// Returns an error if the plaintext value can't be decoded.
+func (f *FixedPoint) DecodeString(params *params.Params, m *big.Int) (string, error) {
+	x, err := f.Decode(params, m)
+	if err != nil {
+		return "", err
+	}
+
+	return x.FloatString(f.Scale), nil
+}
@@ -0,0 +1,108 @@
+package plaintext_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/plaintext"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestFixedPoint(t *testing.T) {
+	t.Parallel()
+
+	params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+	t.Run("Encode / Decode", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			scale    int
+			value    string
+			expected string
+		}{
+			{2, "12.34", "12.34"},
+			{2, "-12.34", "-12.34"},
+			{2, "12.3", "12.30"},
+			{2, "0", "0.00"},
+			{0, "42", "42"},
+			{6, "-0.000001", "-0.000001"},
+		}
+
+		for _, tc := range tests {
+			fp := plaintext.NewFixedPoint(tc.scale)
+
+			m, err := fp.EncodeString(params, tc.value)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			result, _ := fp.DecodeString(params, m)
+
+			if result != tc.expected {
+				t.Errorf("want %v, got %v", tc.expected, result)
+			}
+		}
+	})
+
+	t.Run("Encode / Generate Puzzle / Solve Puzzle / Decode", func(t *testing.T) {
+		t.Parallel()
+
+		fp := plaintext.NewFixedPoint(2)
+		expected := big.NewRat(-1234, 100)
+
+		m, _ := fp.Encode(params, expected)
+		puzzle1, _ := puzzle.GeneratePuzzle(params, m)
+
+		mPrime := puzzle.SolvePuzzle(params, puzzle1)
+		result, _ := fp.Decode(params, mPrime)
+
+		if result.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Error when value can't be encoded", func(t *testing.T) {
+		t.Parallel()
+
+		fp := plaintext.NewFixedPoint(2)
+		tooLarge := new(big.Int).Lsh(params.NExpYMinusOne, 1) // 2 * n^(y - 1)
+
+		tests := []struct {
+			name  string
+			value string
+			err   error
+		}{
+			{"Too Many Fractional Digits", "12.345", plaintext.ErrInexact},
+			{"Not A Decimal Number", "1/3", plaintext.ErrInexact},
+			{"Not A Number", "foo", plaintext.ErrInvalidDecimal},
+			{"Overflow", tooLarge.String(), plaintext.ErrOutOfRange},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := fp.EncodeString(params, tc.value)
+
+				if !errors.Is(err, tc.err) {
+					t.Errorf("want error %v, got %v", tc.err, err)
+				}
+			})
+		}
+	})
+
+	t.Run("Error when scale is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, scale := range []int{-1, 1_000} {
+			_, err := plaintext.NewFixedPoint(scale).EncodeString(params, "1")
+
+			if !errors.Is(err, plaintext.ErrInvalidScale) {
+				t.Errorf("want error %v, got %v", plaintext.ErrInvalidScale, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package plaintext
+
+import (
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+// EncodeSigned maps the signed integer into the message space
+// {0, ..., n^(y - 1) - 1} by reducing it modulo n^(y - 1) so that negative
+// integers are mapped to the upper half of the message space.
+// Returns an error if the protocol parameters are invalid or if the integer
+// isn't in the range [-(n^(y - 1) - 1) / 2, (n^(y - 1) - 1) / 2].
+func EncodeSigned(params *params.Params, x *big.Int) (*big.Int, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	if x == nil || new(big.Int).Abs(x).Cmp(MaxSigned(params)) > 0 {
+		return nil, ErrOutOfRange
+	}
+
+	m := new(big.Int).Mod(x, params.NExpYMinusOne) // x mod n^(y - 1)
+
+	return m, nil
+}
+
+// DecodeSigned maps the (solved) plaintext value from the message space back
+// into the centered range [-(n^(y - 1) - 1) / 2, (n^(y - 1) - 1) / 2] by mapping
+// the values m > (n^(y - 1) - 1) / 2 to the negative values m - n^(y - 1).
+// The decoded value is correct as long as the result of a homomorphic
+// computation is within the centered range.
+// Returns an error if the protocol parameters are invalid or if the plaintext
+// value isn't an element of {0, ..., n^(y - 1) - 1}.
+func DecodeSigned(params *params.Params, m *big.Int) (*big.Int, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	if m == nil || m.Sign() < 0 || m.Cmp(params.NExpYMinusOne) >= 0 {
+		return nil, ErrOutOfRange
+	}
+
+	if m.Cmp(MaxSigned(params)) <= 0 {
+		return new(big.Int).Set(m), nil
+	}
+
+	x := new(big.Int).Sub(m, params.NExpYMinusOne) // m - n^(y - 1)
+
+	return x, nil
+}
+
+// MaxSigned returns the largest signed integer (n^(y - 1) - 1) / 2 that can be
+// encoded. The smallest signed integer is its negation.
+// Note: The caller needs to ensure that the protocol parameters are valid.
+func MaxSigned(params *params.Params) *big.Int {
+	// n is odd which is why n^(y - 1) / 2 = (n^(y - 1) - 1) / 2.
+	return new(big.Int).Rsh(params.NExpYMinusOne, 1)
+}
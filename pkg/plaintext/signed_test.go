@@ -0,0 +1,87 @@
+package plaintext_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/homomorphic"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/plaintext"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestSigned(t *testing.T) {
+	t.Parallel()
+
+	params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+	maxSigned := plaintext.MaxSigned(params)
+	minSigned := new(big.Int).Neg(maxSigned)
+
+	t.Run("Encode / Decode", func(t *testing.T) {
+		t.Parallel()
+
+		for _, x := range []*big.Int{minSigned, big.NewInt(-42), big.NewInt(0), big.NewInt(42), maxSigned} {
+			m, err := plaintext.EncodeSigned(params, x)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if m.Sign() < 0 || m.Cmp(params.NExpYMinusOne) >= 0 {
+				t.Errorf("want value in message space, got %v", m)
+			}
+
+			xPrime, _ := plaintext.DecodeSigned(params, m)
+
+			if xPrime.Cmp(x) != 0 {
+				t.Errorf("want %v, got %v", x, xPrime)
+			}
+		}
+	})
+
+	t.Run("Encode / Generate Puzzles / Add Values / Solve Puzzle / Decode", func(t *testing.T) {
+		t.Parallel()
+
+		x1 := big.NewInt(-100)
+		x2 := big.NewInt(42)
+		expected := big.NewInt(-58)
+
+		m1, _ := plaintext.EncodeSigned(params, x1)
+		m2, _ := plaintext.EncodeSigned(params, x2)
+		puzzle1, _ := puzzle.GeneratePuzzle(params, m1)
+		puzzle2, _ := puzzle.GeneratePuzzle(params, m2)
+
+		puzzle3, _ := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2)
+
+		m3 := puzzle.SolvePuzzle(params, puzzle3)
+		result, _ := plaintext.DecodeSigned(params, m3)
+
+		if result.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, result)
+		}
+	})
+
+	t.Run("Error when value is out of range", func(t *testing.T) {
+		t.Parallel()
+
+		maxPlusOne := new(big.Int).Add(maxSigned, big.NewInt(1))  // max + 1
+		minMinusOne := new(big.Int).Sub(minSigned, big.NewInt(1)) // min - 1
+
+		for _, x := range []*big.Int{maxPlusOne, minMinusOne, nil} {
+			_, err := plaintext.EncodeSigned(params, x)
+
+			if !errors.Is(err, plaintext.ErrOutOfRange) {
+				t.Errorf("want error %v, got %v", plaintext.ErrOutOfRange, err)
+			}
+		}
+
+		for _, m := range []*big.Int{big.NewInt(-1), params.NExpYMinusOne, nil} {
+			_, err := plaintext.DecodeSigned(params, m)
+
+			if !errors.Is(err, plaintext.ErrOutOfRange) {
+				t.Errorf("want error %v, got %v", plaintext.ErrOutOfRange, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,508 @@
+package proofs
+
+import (
+	"encoding/json"
+
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// Types of the PEM blocks that contain the proofs.
+const (
+	armorType         = "LHTLP RANGE PROOF"
+	openingArmorType  = "LHTLP OPENING PROOF"
+	solutionArmorType = "LHTLP SOLUTION PROOF"
+)
+
+// rangeProofJSON is the JSON representation of the Range proof.
+type rangeProofJSON struct {
+	Version int                 `json:"version"`
+	D       []*puzzle.Puzzle    `json:"d"`
+	Values  []*puzzleValuesJSON `json:"values"`
+}
+
+// openingProofJSON is the JSON representation of the Opening proof.
+type openingProofJSON struct {
+	Version int               `json:"version"`
+	D       *puzzle.Puzzle    `json:"d"`
+	Values  *puzzleValuesJSON `json:"values"`
+}
+
+// solutionProofJSON is the JSON representation of the Solution proof.
+type solutionProofJSON struct {
+	Version int    `json:"version"`
+	W       string `json:"w"`
+	Pi      string `json:"pi"`
+}
+
+// puzzleValuesJSON is the JSON representation of a puzzle's values.
+type puzzleValuesJSON struct {
+	X string `json:"x"`
+	R string `json:"r"`
+}
+
+// minPuzzleValuesLen is the minimum length of encoded puzzle values (two empty
+// big integers).
+const minPuzzleValuesLen = 2 * 5
+
+// minPuzzleLen is the minimum length of a length-prefixed puzzle encoding
+// (length, header and two empty big integers).
+const minPuzzleLen = 4 + 2 + 2*5
+
+// AppendBinary appends the binary encoding of the Range proof to b.
+// The encoding consists of the header followed by the number of puzzles in D
+// (as an 8 byte big-endian integer), the length-prefixed binary encodings of
+// the puzzles in D, the number of puzzle values (as an 8 byte big-endian
+// integer) and the big integers x and r of every puzzle value.
+// Returns an error if a value of the proof is missing.
+func (p *RangeProof) AppendBinary(b []byte) ([]byte, error) {
+	b = utils.AppendHeader(b, utils.TypeRangeProof)
+
+	b = utils.AppendUint64(b, uint64(len(p.D)))
+	for _, d := range p.D {
+		if d == nil {
+			return nil, ErrEncodeRangeProof
+		}
+
+		data, err := d.MarshalBinary()
+		if err != nil {
+			return nil, ErrEncodeRangeProof
+		}
+
+		b = utils.AppendBytes(b, data)
+	}
+
+	b = utils.AppendUint64(b, uint64(len(p.Values)))
+	for _, v := range p.Values {
+		if v == nil || v.X == nil || v.R == nil {
+			return nil, ErrEncodeRangeProof
+		}
+
+		b = utils.AppendBigInt(b, v.X)
+		b = utils.AppendBigInt(b, v.R)
+	}
+
+	return b, nil
+}
+
+// MarshalBinary encodes the Range proof into its binary form.
+// Returns an error if a value of the proof is missing.
+func (p *RangeProof) MarshalBinary() ([]byte, error) {
+	return p.AppendBinary(nil)
+}
+
+// UnmarshalBinary decodes the Range proof from its binary form.
+// Returns an error if the data isn't a canonical encoding of a Range proof.
+func (p *RangeProof) UnmarshalBinary(data []byte) error {
+	dec := utils.NewDecoder(data)
+
+	if err := dec.ReadHeader(utils.TypeRangeProof); err != nil {
+		return ErrDecodeRangeProof
+	}
+
+	numPuzzles, err := dec.ReadUint64()
+	// Reject counts that can't possibly be backed by the remaining data.
+	if err != nil || numPuzzles > uint64(dec.Len()/minPuzzleLen) {
+		return ErrDecodeRangeProof
+	}
+
+	d := make([]*puzzle.Puzzle, numPuzzles)
+	for i := range d {
+		data, err := dec.ReadBytes()
+		if err != nil {
+			return ErrDecodeRangeProof
+		}
+
+		d[i] = new(puzzle.Puzzle)
+		if err := d[i].UnmarshalBinary(data); err != nil {
+			return ErrDecodeRangeProof
+		}
+	}
+
+	numValues, err := dec.ReadUint64()
+	if err != nil || numValues > uint64(dec.Len()/minPuzzleValuesLen) {
+		return ErrDecodeRangeProof
+	}
+
+	values := make([]*PuzzleValues, numValues)
+	for i := range values {
+		x, err := dec.ReadBigInt()
+		if err != nil {
+			return ErrDecodeRangeProof
+		}
+
+		r, err := dec.ReadBigInt()
+		if err != nil {
+			return ErrDecodeRangeProof
+		}
+
+		values[i] = NewPuzzleValues(x, r)
+	}
+
+	if err := dec.Finish(); err != nil {
+		return ErrDecodeRangeProof
+	}
+
+	*p = *NewRangeProof(d, values)
+
+	return nil
+}
+
+// MarshalJSON encodes the Range proof into its JSON form.
+// The puzzles are encoded via their JSON form and the big integers are encoded
+// as canonical hex strings.
+// Returns an error if a value of the proof is missing.
+func (p *RangeProof) MarshalJSON() ([]byte, error) {
+	for _, d := range p.D {
+		if d == nil || d.U == nil || d.V == nil {
+			return nil, ErrEncodeRangeProof
+		}
+	}
+
+	values := make([]*puzzleValuesJSON, len(p.Values))
+	for i, v := range p.Values {
+		if v == nil || v.X == nil || v.R == nil {
+			return nil, ErrEncodeRangeProof
+		}
+
+		values[i] = &puzzleValuesJSON{
+			X: utils.BigIntToHex(v.X),
+			R: utils.BigIntToHex(v.R),
+		}
+	}
+
+	d := p.D
+	if d == nil {
+		d = []*puzzle.Puzzle{}
+	}
+
+	return json.Marshal(rangeProofJSON{
+		Version: int(utils.EncodingVersion),
+		D:       d,
+		Values:  values,
+	})
+}
+
+// UnmarshalJSON decodes the Range proof from its JSON form.
+// Returns an error if the data isn't a canonical JSON encoding of a Range
+// proof.
+func (p *RangeProof) UnmarshalJSON(data []byte) error {
+	var v rangeProofJSON
+	if err := utils.DecodeJSON(data, &v); err != nil {
+		return ErrDecodeRangeProof
+	}
+
+	if v.Version != int(utils.EncodingVersion) || v.D == nil || v.Values == nil {
+		return ErrDecodeRangeProof
+	}
+
+	for _, d := range v.D {
+		if d == nil {
+			return ErrDecodeRangeProof
+		}
+	}
+
+	values := make([]*PuzzleValues, len(v.Values))
+	for i, value := range v.Values {
+		if value == nil {
+			return ErrDecodeRangeProof
+		}
+
+		x, err := utils.HexToBigInt(value.X)
+		if err != nil {
+			return ErrDecodeRangeProof
+		}
+
+		r, err := utils.HexToBigInt(value.R)
+		if err != nil {
+			return ErrDecodeRangeProof
+		}
+
+		values[i] = NewPuzzleValues(x, r)
+	}
+
+	*p = *NewRangeProof(v.D, values)
+
+	return nil
+}
+
+// MarshalText encodes the Range proof into its armored text form which is a
+// PEM block that contains the binary encoding.
+// Returns an error if a value of the proof is missing.
+func (p *RangeProof) MarshalText() ([]byte, error) {
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.Armor(armorType, data), nil
+}
+
+// UnmarshalText decodes the Range proof from its armored text form.
+// Returns an error if the text isn't a valid armored encoding of a Range proof.
+func (p *RangeProof) UnmarshalText(text []byte) error {
+	data, err := utils.Dearmor(armorType, text)
+	if err != nil {
+		return ErrDecodeRangeProof
+	}
+
+	return p.UnmarshalBinary(data)
+}
+
+// AppendBinary appends the binary encoding of the Opening proof to b.
+// The encoding consists of the header followed by the length-prefixed binary
+// encoding of the puzzle D and the big integers x and r of the values.
+// Returns an error if a value of the proof is missing.
+func (p *OpeningProof) AppendBinary(b []byte) ([]byte, error) {
+	if p.D == nil || p.Values == nil || p.Values.X == nil || p.Values.R == nil {
+		return nil, ErrEncodeOpeningProof
+	}
+
+	data, err := p.D.MarshalBinary()
+	if err != nil {
+		return nil, ErrEncodeOpeningProof
+	}
+
+	b = utils.AppendHeader(b, utils.TypeOpeningProof)
+	b = utils.AppendBytes(b, data)
+	b = utils.AppendBigInt(b, p.Values.X)
+	b = utils.AppendBigInt(b, p.Values.R)
+
+	return b, nil
+}
+
+// MarshalBinary encodes the Opening proof into its binary form.
+// Returns an error if a value of the proof is missing.
+func (p *OpeningProof) MarshalBinary() ([]byte, error) {
+	return p.AppendBinary(nil)
+}
+
+// UnmarshalBinary decodes the Opening proof from its binary form.
+// Returns an error if the data isn't a canonical encoding of an Opening proof.
+func (p *OpeningProof) UnmarshalBinary(data []byte) error {
+	dec := utils.NewDecoder(data)
+
+	if err := dec.ReadHeader(utils.TypeOpeningProof); err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	puzzleData, err := dec.ReadBytes()
+	if err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	d := new(puzzle.Puzzle)
+	if err := d.UnmarshalBinary(puzzleData); err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	x, err := dec.ReadBigInt()
+	if err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	r, err := dec.ReadBigInt()
+	if err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	if err := dec.Finish(); err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	*p = *NewOpeningProof(d, NewPuzzleValues(x, r))
+
+	return nil
+}
+
+// MarshalJSON encodes the Opening proof into its JSON form.
+// The puzzle is encoded via its JSON form and the big integers are encoded as
+// canonical hex strings.
+// Returns an error if a value of the proof is missing.
+func (p *OpeningProof) MarshalJSON() ([]byte, error) {
+	if p.D == nil || p.Values == nil || p.Values.X == nil || p.Values.R == nil {
+		return nil, ErrEncodeOpeningProof
+	}
+
+	data, err := json.Marshal(openingProofJSON{
+		Version: int(utils.EncodingVersion),
+		D:       p.D,
+		Values: &puzzleValuesJSON{
+			X: utils.BigIntToHex(p.Values.X),
+			R: utils.BigIntToHex(p.Values.R),
+		},
+	})
+	if err != nil {
+		return nil, ErrEncodeOpeningProof
+	}
+
+	return data, nil
+}
+
+// UnmarshalJSON decodes the Opening proof from its JSON form.
+// Returns an error if the data isn't a canonical JSON encoding of an Opening
+// proof.
+func (p *OpeningProof) UnmarshalJSON(data []byte) error {
+	var v openingProofJSON
+	if err := utils.DecodeJSON(data, &v); err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	if v.Version != int(utils.EncodingVersion) || v.D == nil || v.Values == nil {
+		return ErrDecodeOpeningProof
+	}
+
+	x, err := utils.HexToBigInt(v.Values.X)
+	if err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	r, err := utils.HexToBigInt(v.Values.R)
+	if err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	*p = *NewOpeningProof(v.D, NewPuzzleValues(x, r))
+
+	return nil
+}
+
+// MarshalText encodes the Opening proof into its armored text form which is a
+// PEM block that contains the binary encoding.
+// Returns an error if a value of the proof is missing.
+func (p *OpeningProof) MarshalText() ([]byte, error) {
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.Armor(openingArmorType, data), nil
+}
+
+// UnmarshalText decodes the Opening proof from its armored text form.
+// Returns an error if the text isn't a valid armored encoding of an Opening
+// proof.
+func (p *OpeningProof) UnmarshalText(text []byte) error {
+	data, err := utils.Dearmor(openingArmorType, text)
+	if err != nil {
+		return ErrDecodeOpeningProof
+	}
+
+	return p.UnmarshalBinary(data)
+}
+
+// AppendBinary appends the binary encoding of the Solution proof to b.
+// The encoding consists of the header followed by the big integers w and pi.
+// Returns an error if a value of the proof is missing.
+func (p *SolutionProof) AppendBinary(b []byte) ([]byte, error) {
+	if p.W == nil || p.Pi == nil {
+		return nil, ErrEncodeSolutionProof
+	}
+
+	b = utils.AppendHeader(b, utils.TypeSolutionProof)
+	b = utils.AppendBigInt(b, p.W)
+	b = utils.AppendBigInt(b, p.Pi)
+
+	return b, nil
+}
+
+// MarshalBinary encodes the Solution proof into its binary form.
+// Returns an error if a value of the proof is missing.
+func (p *SolutionProof) MarshalBinary() ([]byte, error) {
+	return p.AppendBinary(nil)
+}
+
+// UnmarshalBinary decodes the Solution proof from its binary form.
+// Returns an error if the data isn't a canonical encoding of a Solution proof.
+func (p *SolutionProof) UnmarshalBinary(data []byte) error {
+	dec := utils.NewDecoder(data)
+
+	if err := dec.ReadHeader(utils.TypeSolutionProof); err != nil {
+		return ErrDecodeSolutionProof
+	}
+
+	w, err := dec.ReadBigInt()
+	if err != nil {
+		return ErrDecodeSolutionProof
+	}
+
+	pi, err := dec.ReadBigInt()
+	if err != nil {
+		return ErrDecodeSolutionProof
+	}
+
+	if err := dec.Finish(); err != nil {
+		return ErrDecodeSolutionProof
+	}
+
+	*p = *NewSolutionProof(w, pi)
+
+	return nil
+}
+
+// MarshalJSON encodes the Solution proof into its JSON form.
+// The big integers are encoded as canonical hex strings.
+// Returns an error if a value of the proof is missing.
+func (p *SolutionProof) MarshalJSON() ([]byte, error) {
+	if p.W == nil || p.Pi == nil {
+		return nil, ErrEncodeSolutionProof
+	}
+
+	return json.Marshal(solutionProofJSON{
+		Version: int(utils.EncodingVersion),
+		W:       utils.BigIntToHex(p.W),
+		Pi:      utils.BigIntToHex(p.Pi),
+	})
+}
+
+// UnmarshalJSON decodes the Solution proof from its JSON form.
+// Returns an error if the data isn't a canonical JSON encoding of a Solution
+// proof.
+func (p *SolutionProof) UnmarshalJSON(data []byte) error {
+	var v solutionProofJSON
+	if err := utils.DecodeJSON(data, &v); err != nil {
+		return ErrDecodeSolutionProof
+	}
+
+	if v.Version != int(utils.EncodingVersion) {
+		return ErrDecodeSolutionProof
+	}
+
+	w, err := utils.HexToBigInt(v.W)
+	if err != nil {
+		return ErrDecodeSolutionProof
+	}
+
+	pi, err := utils.HexToBigInt(v.Pi)
+	if err != nil {
+		return ErrDecodeSolutionProof
+	}
+
+	*p = *NewSolutionProof(w, pi)
+
+	return nil
+}
+
+// MarshalText encodes the Solution proof into its armored text form which is a
+// PEM block that contains the binary encoding.
+// Returns an error if a value of the proof is missing.
+func (p *SolutionProof) MarshalText() ([]byte, error) {
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.Armor(solutionArmorType, data), nil
+}
+
+// UnmarshalText decodes the Solution proof from its armored text form.
+// Returns an error if the text isn't a valid armored encoding of a Solution
+// proof.
+func (p *SolutionProof) UnmarshalText(text []byte) error {
+	data, err := utils.Dearmor(solutionArmorType, text)
+	if err != nil {
+		return ErrDecodeSolutionProof
+	}
+
+	return p.UnmarshalBinary(data)
+}
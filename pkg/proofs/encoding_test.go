@@ -0,0 +1,187 @@
+package proofs_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestRangeProofBinaryEncoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Marshal / Unmarshal / Verify", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+		q := big.NewInt(1000)
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		v := proofs.NewPuzzleValues(m, r)
+
+		puzzles := []*puzzle.Puzzle{p}
+		values := []*proofs.PuzzleValues{v}
+
+		proof1, _ := proofs.GenerateRangeProof(bits, params, puzzles, q, values)
+
+		data, err := proof1.MarshalBinary()
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		var proof2 proofs.RangeProof
+		if err := proof2.UnmarshalBinary(data); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		isValid, _ := proofs.VerifyRangePoof(&proof2, bits, params, puzzles, q)
+
+		if isValid != true {
+			t.Error("Range proof verification failed")
+		}
+	})
+
+	t.Run("Marshal / Unmarshal - Negative Values", func(t *testing.T) {
+		t.Parallel()
+
+		d := []*puzzle.Puzzle{puzzle.NewPuzzle(big.NewInt(1), big.NewInt(2))}
+		values := []*proofs.PuzzleValues{proofs.NewPuzzleValues(big.NewInt(-3), big.NewInt(4))}
+
+		proof1 := proofs.NewRangeProof(d, values)
+
+		data, _ := proof1.MarshalBinary()
+
+		var proof2 proofs.RangeProof
+		if err := proof2.UnmarshalBinary(data); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if !proof2.D[0].Equal(d[0]) {
+			t.Errorf("puzzles are not equal %v %v", d[0], proof2.D[0])
+		}
+
+		if proof2.Values[0].X.Cmp(values[0].X) != 0 {
+			t.Errorf("want %v, got %v", values[0].X, proof2.Values[0].X)
+		}
+	})
+
+	t.Run("Error when encoding contains trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		proof1 := proofs.NewRangeProof(nil, nil)
+
+		data, _ := proof1.MarshalBinary()
+		data = append(data, 0x00)
+
+		var proof2 proofs.RangeProof
+		err := proof2.UnmarshalBinary(data)
+
+		if !errors.Is(err, proofs.ErrDecodeRangeProof) {
+			t.Errorf("want error %v, got %v", proofs.ErrDecodeRangeProof, err)
+		}
+	})
+
+	t.Run("Error when number of puzzles exceeds data", func(t *testing.T) {
+		t.Parallel()
+
+		// Header followed by a huge number of puzzles.
+		data := []byte{0x01, 0x03, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
+		var proof proofs.RangeProof
+		err := proof.UnmarshalBinary(data)
+
+		if !errors.Is(err, proofs.ErrDecodeRangeProof) {
+			t.Errorf("want error %v, got %v", proofs.ErrDecodeRangeProof, err)
+		}
+	})
+}
+
+func TestRangeProofTextEncoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Unmarshal / Marshal - Golden JSON", func(t *testing.T) {
+		t.Parallel()
+
+		golden, _ := os.ReadFile("../../testdata/range_proof.json")
+
+		var proof proofs.RangeProof
+		if err := json.Unmarshal(golden, &proof); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		got, _ := json.Marshal(&proof)
+
+		var want bytes.Buffer
+		_ = json.Compact(&want, golden)
+
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Errorf("want %s, got %s", want.Bytes(), got)
+		}
+	})
+
+	t.Run("Unmarshal / Marshal - Golden Armored Text", func(t *testing.T) {
+		t.Parallel()
+
+		golden, _ := os.ReadFile("../../testdata/range_proof.pem")
+
+		var proof proofs.RangeProof
+		if err := proof.UnmarshalText(golden); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		got, _ := proof.MarshalText()
+
+		if !bytes.Equal(got, golden) {
+			t.Errorf("want %s, got %s", golden, got)
+		}
+	})
+
+	t.Run("Unmarshal Golden Files / Verify", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 4
+		q := big.NewInt(1000)
+
+		goldenParams, _ := os.ReadFile("../../testdata/params.pem")
+		goldenPuzzle, _ := os.ReadFile("../../testdata/puzzle.pem")
+		goldenProof, _ := os.ReadFile("../../testdata/range_proof.pem")
+
+		var params1 params.Params
+		_ = params1.UnmarshalText(goldenParams)
+
+		var puzzle1 puzzle.Puzzle
+		_ = puzzle1.UnmarshalText(goldenPuzzle)
+
+		var proof proofs.RangeProof
+		_ = proof.UnmarshalText(goldenProof)
+
+		puzzles := []*puzzle.Puzzle{&puzzle1}
+		isValid, _ := proofs.VerifyRangePoof(&proof, bits, &params1, puzzles, q)
+
+		if isValid != true {
+			t.Error("Range proof verification failed")
+		}
+	})
+
+	t.Run("Error when JSON contains null puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"version":1,"d":[null],"values":[]}`)
+
+		var proof proofs.RangeProof
+		err := json.Unmarshal(data, &proof)
+
+		if !errors.Is(err, proofs.ErrDecodeRangeProof) {
+			t.Errorf("want error %v, got %v", proofs.ErrDecodeRangeProof, err)
+		}
+	})
+}
@@ -5,6 +5,10 @@ import "fmt"
 var (
 	// ErrNumPuzzlesAndWitnesses is returned if the number of puzzles is not equal to the number of witnesses.
 	ErrNumPuzzlesAndWitnesses = fmt.Errorf("number of puzzles is not equal to number of witnesses")
+	// ErrInvalidQ is returned if the bound q is missing or smaller than 2.
+	ErrInvalidQ = fmt.Errorf("invalid bound q")
+	// ErrInvalidWitness is returned if a witness or one of its values is missing.
+	ErrInvalidWitness = fmt.Errorf("invalid witness")
 	// ErrSampleY is returned if the drowning term y can't be sampled.
 	ErrSampleY = fmt.Errorf("unable to sample drowning term y")
 	// ErrComputeD is returned if D can't be computed.
@@ -15,8 +19,30 @@ var (
 	ErrInvalidBit = fmt.Errorf("bit is neither 0 nor 1")
 	// ErrNumPuzzlesAndValues is returned if the number of puzzles is not equal to the number of values.
 	ErrNumPuzzlesAndValues = fmt.Errorf("number of puzzles is not equal to number of values")
+	// ErrInvalidProof is returned if a proof is malformed (e.g. because it doesn't contain a puzzle and values for each bit).
+	ErrInvalidProof = fmt.Errorf("invalid proof")
 	// ErrComputeFiPrime is returned if Fi' can't be computed.
 	ErrComputeFiPrime = fmt.Errorf("unable to compute Fi'")
 	// ErrGenerateRandomBytes is returned if the random bytes can't be generated.
 	ErrGenerateRandomBytes = fmt.Errorf("unable to generate random bytes")
+	// ErrSampleMaskR is returned if the random mask for the nonce r can't be sampled.
+	ErrSampleMaskR = fmt.Errorf("unable to sample random mask for nonce r")
+	// ErrSampleMaskX is returned if the random mask for the plaintext value x can't be sampled.
+	ErrSampleMaskX = fmt.Errorf("unable to sample random mask for plaintext value x")
+	// ErrComputeFPrime is returned if F' can't be computed.
+	ErrComputeFPrime = fmt.Errorf("unable to compute F'")
+	// ErrInvalidBits is returned if the number of bits is too small.
+	ErrInvalidBits = fmt.Errorf("number of bits is too small")
+	// ErrEncodeRangeProof is returned if the Range proof can't be encoded.
+	ErrEncodeRangeProof = fmt.Errorf("unable to encode range proof")
+	// ErrDecodeRangeProof is returned if the Range proof can't be decoded.
+	ErrDecodeRangeProof = fmt.Errorf("unable to decode range proof")
+	// ErrEncodeOpeningProof is returned if the Opening proof can't be encoded.
+	ErrEncodeOpeningProof = fmt.Errorf("unable to encode opening proof")
+	// ErrDecodeOpeningProof is returned if the Opening proof can't be decoded.
+	ErrDecodeOpeningProof = fmt.Errorf("unable to decode opening proof")
+	// ErrEncodeSolutionProof is returned if the Solution proof can't be encoded.
+	ErrEncodeSolutionProof = fmt.Errorf("unable to encode solution proof")
+	// ErrDecodeSolutionProof is returned if the Solution proof can't be decoded.
+	ErrDecodeSolutionProof = fmt.Errorf("unable to decode solution proof")
 )
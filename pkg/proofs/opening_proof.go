@@ -0,0 +1,209 @@
+package proofs
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/internal/scheme"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// MinOpeningProofBits is the minimum number of bits for Opening proofs. A
+// forged proof passes verification with a probability of 2^(-bits).
+const MinOpeningProofBits = 64
+
+// OpeningProof is an instance of an Opening proof.
+type OpeningProof struct {
+	// D is the commitment puzzle.
+	D *puzzle.Puzzle
+	// Values contains the responses for the plaintext value (X) and the nonce
+	// (R).
+	Values *PuzzleValues
+}
+
+// NewOpeningProof creates a new instance of an Opening proof.
+func NewOpeningProof(d *puzzle.Puzzle, values *PuzzleValues) *OpeningProof {
+	return &OpeningProof{
+		D:      d,
+		Values: values,
+	}
+}
+
+// GenerateOpeningProof generates an Opening proof which proves knowledge of the
+// puzzle's plaintext value x and nonce r such that u = g^r mod n and
+// v = h^(r * n^(y - 1)) * (1 + n)^x mod n^y.
+// The bits determine the length of the challenge as well as the statistical
+// security of the response for the nonce and must be at least
+// MinOpeningProofBits. The nonce r is expected to be an element of
+// {0, ..., n^y - 1}.
+// Returns an error if the number of bits is too small, if the protocol
+// parameters, the puzzle or the witness are invalid or if the proof generation
+// fails.
+func GenerateOpeningProof(bits int, params *params.Params, z *puzzle.Puzzle, wit *PuzzleValues) (*OpeningProof, error) {
+	return GenerateOpeningProofWithOptions(bits, params, z, wit, nil)
+}
+
+// GenerateOpeningProofWithOptions generates an Opening proof like
+// GenerateOpeningProof while using the options (which can be nil).
+// Returns an error if the number of bits is too small, if the protocol
+// parameters, the puzzle or the witness are invalid or if the proof generation
+// fails.
+func GenerateOpeningProofWithOptions(bits int, params *params.Params, z *puzzle.Puzzle, wit *PuzzleValues, opts *GenerateOptions) (*OpeningProof, error) {
+	k := bits
+	random := opts.Reader()
+
+	if k < MinOpeningProofBits {
+		return nil, ErrInvalidBits
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := z.Validate(params); err != nil {
+		return nil, err
+	}
+
+	if wit == nil || wit.X == nil || wit.R == nil {
+		return nil, ErrInvalidWitness
+	}
+
+	// Sample random mask a for the nonce r in [0, n^y * 2^(2 * k)).
+	aBound := new(big.Int).Lsh(params.NExpY, uint(2*k)) // n^y * 2^(2 * k)
+	a, err := rand.Int(random, aBound)
+	if err != nil {
+		return nil, ErrSampleMaskR
+	}
+
+	// Sample random mask b for the plaintext value x in [0, n^(y - 1)).
+	b, err := rand.Int(random, params.NExpYMinusOne)
+	if err != nil {
+		return nil, ErrSampleMaskX
+	}
+
+	// Compute commitment D which is a puzzle that hides b using the nonce a.
+	d := puzzle.NewPuzzle(scheme.GeneratePuzzle(params, a, b))
+
+	// Generate challenge c via Fiat-Shamir transform.
+	c, err := openingProofDataToChallenge(k, params, z, d)
+	if err != nil {
+		return nil, ErrGenerateRandomness
+	}
+
+	// Compute responses.
+	in1 := new(big.Int).Mul(c, wit.X)                 // c * x
+	in2 := new(big.Int).Add(b, in1)                   // b + c * x
+	zx := new(big.Int).Mod(in2, params.NExpYMinusOne) // b + c * x mod n^(y - 1)
+
+	in3 := new(big.Int).Mul(c, wit.R) // c * r
+	zr := new(big.Int).Add(a, in3)    // a + c * r
+
+	proof := NewOpeningProof(d, NewPuzzleValues(zx, zr))
+
+	return proof, nil
+}
+
+// VerifyOpeningProof verifies an Opening proof which proves knowledge of the
+// puzzle's plaintext value x and nonce r such that u = g^r mod n and
+// v = h^(r * n^(y - 1)) * (1 + n)^x mod n^y.
+// Returns an error if the number of bits is too small (see
+// MinOpeningProofBits), if the protocol parameters or the puzzle are invalid or
+// if the proof verification fails.
+func VerifyOpeningProof(proof *OpeningProof, bits int, params *params.Params, z *puzzle.Puzzle) (bool, error) {
+	k := bits
+
+	if k < MinOpeningProofBits {
+		return false, ErrInvalidBits
+	}
+
+	if err := params.Validate(); err != nil {
+		return false, err
+	}
+
+	if err := z.Validate(params); err != nil {
+		return false, err
+	}
+
+	if proof == nil || proof.D == nil || proof.Values == nil || proof.Values.X == nil || proof.Values.R == nil {
+		return false, nil
+	}
+
+	if err := proof.D.Validate(params); err != nil {
+		return false, nil
+	}
+
+	zx := proof.Values.X
+	zr := proof.Values.R
+
+	// Check if z_x is an element of {0, ..., n^(y - 1) - 1}.
+	isZxInRange := zx.Sign() >= 0 && zx.Cmp(params.NExpYMinusOne) < 0
+	if !isZxInRange {
+		return false, nil
+	}
+
+	// Check if z_r is an element of {0, ..., n^y * 2^(2 * k) + 2^k * n^y}.
+	in1 := new(big.Int).Lsh(params.NExpY, uint(2*k)) // n^y * 2^(2 * k)
+	in2 := new(big.Int).Lsh(params.NExpY, uint(k))   // n^y * 2^k
+	zrBound := new(big.Int).Add(in1, in2)            // n^y * 2^(2 * k) + n^y * 2^k
+	isZrInRange := zr.Sign() >= 0 && zr.Cmp(zrBound) <= 0
+	if !isZrInRange {
+		return false, nil
+	}
+
+	// (Re)Generate challenge c via Fiat-Shamir transform.
+	c, err := openingProofDataToChallenge(k, params, z, proof.D)
+	if err != nil {
+		return false, ErrGenerateRandomness
+	}
+
+	// Compute D * Z^c.
+	in3 := new(big.Int).Exp(z.U, c, params.N) // u^c mod n
+	in4 := new(big.Int).Mul(proof.D.U, in3)   // D.u * u^c
+	fu := new(big.Int).Mod(in4, params.N)     // D.u * u^c mod n
+
+	in5 := new(big.Int).Exp(z.V, c, params.NExpY) // v^c mod n^y
+	in6 := new(big.Int).Mul(proof.D.V, in5)       // D.v * v^c
+	fv := new(big.Int).Mod(in6, params.NExpY)     // D.v * v^c mod n^y
+
+	f := puzzle.NewPuzzle(fu, fv)
+
+	fPrime := puzzle.NewPuzzle(scheme.GeneratePuzzle(params, zr, zx))
+
+	// Check if puzzles are equal.
+	if !f.Equal(fPrime) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// openingProofDataToChallenge implements the Fiat-Shamir transform to derive a
+// challenge with the desired number of bits.
+// Returns an error if the challenge can't be derived from the proof data.
+func openingProofDataToChallenge(k int, params *params.Params, z, d *puzzle.Puzzle) (*big.Int, error) {
+	var seed []byte
+
+	// Params.
+	seed = utils.AppendUint64(seed, uint64(params.Y))
+	seed = utils.AppendBigInt(seed, params.T)
+	seed = utils.AppendBigInt(seed, params.N)
+	seed = utils.AppendBigInt(seed, params.G)
+	seed = utils.AppendBigInt(seed, params.H)
+	// Puzzle (Z).
+	seed = utils.AppendBigInt(seed, z.U)
+	seed = utils.AppendBigInt(seed, z.V)
+	// Puzzle (D).
+	seed = utils.AppendBigInt(seed, d.U)
+	seed = utils.AppendBigInt(seed, d.V)
+
+	randBytes, err := utils.GenerateRandomBytesSeeded(seed, k)
+	if err != nil {
+		return nil, ErrGenerateRandomBytes
+	}
+
+	c := new(big.Int).SetBytes(randBytes)
+
+	return c, nil
+}
@@ -0,0 +1,230 @@
+package proofs_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestOpeningProof(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Prove / Verify - Valid", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		v := proofs.NewPuzzleValues(m, r)
+
+		proof, _ := proofs.GenerateOpeningProof(bits, params, p, v)
+		isValid, _ := proofs.VerifyOpeningProof(proof, bits, params, p)
+
+		if isValid != true {
+			t.Error("Opening proof verification failed")
+		}
+	})
+
+	t.Run("Prove / Verify - Valid (Large Message)", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		params, _ := params.GenerateParams(bits, 4, big.NewInt(1))
+
+		m := new(big.Int).Mul(params.N, params.N) // n^2
+
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		v := proofs.NewPuzzleValues(m, r)
+
+		proof, _ := proofs.GenerateOpeningProof(bits, params, p, v)
+		isValid, _ := proofs.VerifyOpeningProof(proof, bits, params, p)
+
+		if isValid != true {
+			t.Error("Opening proof verification failed")
+		}
+	})
+
+	t.Run("Prove / Verify - Invalid (Wrong Plaintext)", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		v := proofs.NewPuzzleValues(big.NewInt(24), r)
+
+		proof, _ := proofs.GenerateOpeningProof(bits, params, p, v)
+		isValid, _ := proofs.VerifyOpeningProof(proof, bits, params, p)
+
+		if isValid != false {
+			t.Error("Opening proof verification failed")
+		}
+	})
+
+	t.Run("Prove / Verify - Invalid (Wrong Nonce)", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		rPrime := new(big.Int).Add(r, big.NewInt(1)) // r + 1
+		v := proofs.NewPuzzleValues(m, rPrime)
+
+		proof, _ := proofs.GenerateOpeningProof(bits, params, p, v)
+		isValid, _ := proofs.VerifyOpeningProof(proof, bits, params, p)
+
+		if isValid != false {
+			t.Error("Opening proof verification failed")
+		}
+	})
+
+	t.Run("Prove / Verify - Invalid (Different Puzzle)", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p1, r1, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		p2, _ := puzzle.GeneratePuzzle(params, m)
+		v1 := proofs.NewPuzzleValues(m, r1)
+
+		proof, _ := proofs.GenerateOpeningProof(bits, params, p1, v1)
+		isValid, _ := proofs.VerifyOpeningProof(proof, bits, params, p2)
+
+		if isValid != false {
+			t.Error("Opening proof verification failed")
+		}
+	})
+
+	t.Run("Prove / Verify - Invalid (Response Out Of Range)", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		v := proofs.NewPuzzleValues(m, r)
+
+		proof, _ := proofs.GenerateOpeningProof(bits, params, p, v)
+		// z_x + n^(y - 1) hides the same plaintext value but isn't canonical.
+		proof.Values.X = new(big.Int).Add(proof.Values.X, params.NExpYMinusOne)
+		isValid, _ := proofs.VerifyOpeningProof(proof, bits, params, p)
+
+		if isValid != false {
+			t.Error("Opening proof verification failed")
+		}
+	})
+
+	t.Run("Prove / Marshal / Unmarshal / Verify", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		v := proofs.NewPuzzleValues(m, r)
+
+		proof1, _ := proofs.GenerateOpeningProof(bits, params, p, v)
+
+		data1, _ := proof1.MarshalBinary()
+		var proof2 proofs.OpeningProof
+		if err := proof2.UnmarshalBinary(data1); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		data2, _ := json.Marshal(&proof2)
+		var proof3 proofs.OpeningProof
+		if err := json.Unmarshal(data2, &proof3); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		data3, _ := proof3.MarshalText()
+		var proof4 proofs.OpeningProof
+		if err := proof4.UnmarshalText(data3); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		isValid, _ := proofs.VerifyOpeningProof(&proof4, bits, params, p)
+
+		if isValid != true {
+			t.Error("Opening proof verification failed")
+		}
+	})
+	t.Run("Error when bits are too small", func(t *testing.T) {
+		t.Parallel()
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		v := proofs.NewPuzzleValues(m, r)
+
+		// With 0 bits the challenge is always 0 which is why any commitment D
+		// that hides z_x using z_r would pass the verification.
+		zx := big.NewInt(0)
+		zr := big.NewInt(1)
+		d, _ := puzzle.GeneratePuzzleWithCustomNonce(params, zr, zx)
+		forged := proofs.NewOpeningProof(d, proofs.NewPuzzleValues(zx, zr))
+
+		for _, bits := range []int{-1, 0, proofs.MinOpeningProofBits - 1} {
+			_, err1 := proofs.GenerateOpeningProof(bits, params, p, v)
+			isValid, err2 := proofs.VerifyOpeningProof(forged, bits, params, p)
+
+			if !errors.Is(err1, proofs.ErrInvalidBits) {
+				t.Errorf("want error %v, got %v", proofs.ErrInvalidBits, err1)
+			}
+			if !errors.Is(err2, proofs.ErrInvalidBits) {
+				t.Errorf("want error %v, got %v", proofs.ErrInvalidBits, err2)
+			}
+			if isValid != false {
+				t.Error("Opening proof verification failed")
+			}
+		}
+	})
+
+	t.Run("Error when puzzle or witness is missing", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		v := proofs.NewPuzzleValues(m, r)
+
+		_, err1 := proofs.GenerateOpeningProof(bits, params, nil, v)
+		_, err2 := proofs.GenerateOpeningProof(bits, params, p, nil)
+		_, err3 := proofs.GenerateOpeningProof(bits, params, p, proofs.NewPuzzleValues(m, nil))
+
+		if !errors.Is(err1, puzzle.ErrMissingPuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrMissingPuzzle, err1)
+		}
+		if !errors.Is(err2, proofs.ErrInvalidWitness) {
+			t.Errorf("want error %v, got %v", proofs.ErrInvalidWitness, err2)
+		}
+		if !errors.Is(err3, proofs.ErrInvalidWitness) {
+			t.Errorf("want error %v, got %v", proofs.ErrInvalidWitness, err3)
+		}
+	})
+}
@@ -2,13 +2,19 @@ package proofs
 
 import (
 	"crypto/rand"
+	"io"
 	"math/big"
 
+	"github.com/primefactor-io/lhtlp/pkg/internal/scheme"
 	"github.com/primefactor-io/lhtlp/pkg/params"
 	"github.com/primefactor-io/lhtlp/pkg/puzzle"
 	"github.com/primefactor-io/lhtlp/pkg/utils"
 )
 
+// GenerateOptions configures the generation of proofs (only its source of
+// randomness applies).
+type GenerateOptions = params.GenerateOptions
+
 // Range proof is an instance of a Range proof.
 type RangeProof struct {
 	// D is the array that contains all puzzles.
@@ -44,8 +50,30 @@ func NewPuzzleValues(x, r *big.Int) *PuzzleValues {
 // GenerateRangeProof generates a Range proof which proves that all the puzzle's
 // plaintext values (their x values) are an element of {0, ..., q} and in the
 // range [-(q / 2), (q / 2)].
-// Returns an error if the proof generation fails.
+// Returns an error if the protocol parameters, q or a witness are invalid or if
+// the proof generation fails.
 func GenerateRangeProof(bits int, params *params.Params, z []*puzzle.Puzzle, q *big.Int, wit []*PuzzleValues) (*RangeProof, error) {
+	return GenerateRangeProofWithOptions(bits, params, z, q, wit, nil)
+}
+
+// GenerateRangeProofWithOptions generates a Range proof like GenerateRangeProof
+// while using the options (which can be nil).
+// Returns an error if the protocol parameters, q or a witness are invalid or if
+// the proof generation fails.
+func GenerateRangeProofWithOptions(bits int, params *params.Params, z []*puzzle.Puzzle, q *big.Int, wit []*PuzzleValues, opts *GenerateOptions) (*RangeProof, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return generateRangeProof(bits, params, z, q, wit, opts.Reader())
+}
+
+// generateRangeProof generates a Range proof like GenerateRangeProof while
+// using the source of randomness.
+// Note: The caller needs to ensure that the protocol parameters are valid.
+// Returns an error if q or a witness are invalid or if the proof generation
+// fails.
+func generateRangeProof(bits int, params *params.Params, z []*puzzle.Puzzle, q *big.Int, wit []*PuzzleValues, random io.Reader) (*RangeProof, error) {
 	k := bits
 	numPuzzles := len(z)
 
@@ -53,6 +81,14 @@ func GenerateRangeProof(bits int, params *params.Params, z []*puzzle.Puzzle, q *
 		return nil, ErrNumPuzzlesAndWitnesses
 	}
 
+	if !isValidQ(q) {
+		return nil, ErrInvalidQ
+	}
+
+	if err := validateWitnesses(wit); err != nil {
+		return nil, err
+	}
+
 	l := new(big.Int).SetInt64(int64(numPuzzles)) // l
 	l4 := new(big.Int).Mul(big.NewInt(4), l)      // 4 * l
 	b := new(big.Int).Div(q, big.NewInt(2))       // q / 2
@@ -67,18 +103,18 @@ func GenerateRangeProof(bits int, params *params.Params, z []*puzzle.Puzzle, q *
 
 	for i := range k {
 		// Sample random drowning term y_i in [0, 2 * (L / 4)).
-		yi, err := rand.Int(rand.Reader, n2)
+		yi, err := rand.Int(random, n2)
 		if err != nil {
 			return nil, ErrSampleY
 		}
 
 		// Compute D_i and r_i'.
-		di, riPrime, err := puzzle.GeneratePuzzleAndReturnNonce(params, yi)
+		riPrime, err := scheme.SampleNonce(params, random)
 		if err != nil {
 			return nil, ErrComputeD
 		}
 
-		d[i] = di
+		d[i] = puzzle.NewPuzzle(scheme.GeneratePuzzle(params, riPrime, yi))
 		y[i] = yi
 		rPrime[i] = riPrime
 	}
@@ -131,15 +167,50 @@ func GenerateRangeProof(bits int, params *params.Params, z []*puzzle.Puzzle, q *
 // VerifyRangePoof verifies a Range proof which proves that all the puzzle's
 // plaintext values (their x values) are an element of {0, ..., q} and in the
 // range [-(q / 2), (q / 2)].
-// Returns an error if the proof verification fails.
+// Returns an error if the protocol parameters, the bits, q or the puzzles are
+// invalid, if the proof is malformed or if the proof verification fails.
 func VerifyRangePoof(proof *RangeProof, bits int, params *params.Params, z []*puzzle.Puzzle, q *big.Int) (bool, error) {
+	if err := params.Validate(); err != nil {
+		return false, err
+	}
+
+	return verifyRangeProof(proof, bits, params, z, q)
+}
+
+// verifyRangeProof verifies a Range proof like VerifyRangePoof.
+// Note: The caller needs to ensure that the protocol parameters are valid.
+// Returns an error if the bits, q or the puzzles are invalid, if the proof is
+// malformed or if the proof verification fails.
+func verifyRangeProof(proof *RangeProof, bits int, params *params.Params, z []*puzzle.Puzzle, q *big.Int) (bool, error) {
 	k := bits
 	numPuzzles := len(z)
 
+	if k < 1 {
+		return false, ErrInvalidBits
+	}
+
+	if !isValidQ(q) {
+		return false, ErrInvalidQ
+	}
+
+	if proof == nil {
+		return false, ErrInvalidProof
+	}
+
 	if len(proof.D) != len(proof.Values) {
 		return false, ErrNumPuzzlesAndValues
 	}
 
+	if !isWellFormedRangeProof(proof, k) {
+		return false, ErrInvalidProof
+	}
+
+	for _, zj := range z {
+		if err := zj.Validate(params); err != nil {
+			return false, err
+		}
+	}
+
 	zero := big.NewInt(0)
 	l := new(big.Int).SetInt64(int64(numPuzzles)) // l
 	l4 := new(big.Int).Mul(big.NewInt(4), l)      // 4 * l
@@ -198,10 +269,7 @@ func VerifyRangePoof(proof *RangeProof, bits int, params *params.Params, z []*pu
 
 		fi := puzzle.NewPuzzle(fiu, fiv)
 
-		fiPrime, err := puzzle.GeneratePuzzleWithCustomNonce(params, wi, vi)
-		if err != nil {
-			return false, ErrComputeFiPrime
-		}
+		fiPrime := puzzle.NewPuzzle(scheme.GeneratePuzzle(params, wi, vi))
 
 		// Check if puzzles are equal.
 		if !fi.Equal(fiPrime) {
@@ -212,6 +280,40 @@ func VerifyRangePoof(proof *RangeProof, bits int, params *params.Params, z []*pu
 	return true, nil
 }
 
+// isWellFormedRangeProof checks if the proof contains a puzzle D_i and the
+// values v_i and w_i for each of the bits.
+func isWellFormedRangeProof(proof *RangeProof, bits int) bool {
+	if len(proof.D) != bits || len(proof.Values) != bits {
+		return false
+	}
+
+	for i := range bits {
+		d, values := proof.D[i], proof.Values[i]
+		if d == nil || d.U == nil || d.V == nil || values == nil || values.X == nil || values.R == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidQ checks if the bound q is at least 2 (which makes q / 2 positive).
+func isValidQ(q *big.Int) bool {
+	return q != nil && q.Cmp(big.NewInt(2)) >= 0
+}
+
+// validateWitnesses checks if the witnesses contain their x and r values.
+// Returns an error if a witness or one of its values is missing.
+func validateWitnesses(wit []*PuzzleValues) error {
+	for _, v := range wit {
+		if v == nil || v.X == nil || v.R == nil {
+			return ErrInvalidWitness
+		}
+	}
+
+	return nil
+}
+
 // proofDataToHashBytes implements the Fiat-Shamir transform to derive an array
 // of bytes.
 // Returns an error if random bytes can't be derived from the proof data.
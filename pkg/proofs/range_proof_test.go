@@ -1,8 +1,12 @@
 package proofs_test
 
 import (
+	"bytes"
 	"crypto/rand"
+	"errors"
 	"math/big"
+	mrand "math/rand/v2"
+	"slices"
 	"testing"
 
 	"github.com/primefactor-io/lhtlp/pkg/params"
@@ -281,4 +285,83 @@ func TestRangeProof(t *testing.T) {
 			t.Error("Range proof verification failed")
 		}
 	})
+
+	t.Run("Prove / Verify - Custom Randomness", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+		q := big.NewInt(1000)
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+		v := proofs.NewPuzzleValues(m, r)
+
+		puzzles := []*puzzle.Puzzle{p}
+		values := []*proofs.PuzzleValues{v}
+
+		generate := func(seed byte) *proofs.RangeProof {
+			opts := &proofs.GenerateOptions{Rand: mrand.NewChaCha8([32]byte{seed})}
+			proof, _ := proofs.GenerateRangeProofWithOptions(bits, params, puzzles, q, values, opts)
+			return proof
+		}
+
+		proof1 := generate(1)
+		data1, _ := proof1.MarshalBinary()
+		data2, _ := generate(1).MarshalBinary()
+		data3, _ := generate(2).MarshalBinary()
+
+		if !bytes.Equal(data1, data2) {
+			t.Error("want equal proofs for the same randomness")
+		}
+		if bytes.Equal(data1, data3) {
+			t.Error("want different proofs for different randomness")
+		}
+
+		isValid, _ := proofs.VerifyRangePoof(proof1, bits, params, puzzles, q)
+
+		if isValid != true {
+			t.Error("Range proof verification failed")
+		}
+	})
+	t.Run("Error when proof is malformed", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 16
+		q := big.NewInt(1000)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, big.NewInt(42))
+
+		puzzles := []*puzzle.Puzzle{p}
+		proof, _ := proofs.GenerateRangeProof(bits, params, puzzles, q, []*proofs.PuzzleValues{proofs.NewPuzzleValues(big.NewInt(42), r)})
+
+		nilD := proofs.NewRangeProof(slices.Clone(proof.D), proof.Values)
+		nilD.D[0] = nil
+		nilValue := proofs.NewRangeProof(proof.D, slices.Clone(proof.Values))
+		nilValue.Values[0] = proofs.NewPuzzleValues(nil, r)
+
+		tests := []struct {
+			name  string
+			proof *proofs.RangeProof
+			bits  int
+			want  error
+		}{
+			{"Empty Proof", &proofs.RangeProof{}, bits, proofs.ErrInvalidProof},
+			{"Missing Proof", nil, bits, proofs.ErrInvalidProof},
+			{"Truncated Proof", proofs.NewRangeProof(proof.D[:1], proof.Values[:1]), bits, proofs.ErrInvalidProof},
+			{"Missing Puzzle D", nilD, bits, proofs.ErrInvalidProof},
+			{"Missing Value", nilValue, bits, proofs.ErrInvalidProof},
+			{"bits = 0", &proofs.RangeProof{}, 0, proofs.ErrInvalidBits},
+		}
+
+		for _, tc := range tests {
+			isValid, err := proofs.VerifyRangePoof(tc.proof, tc.bits, params, puzzles, q)
+
+			if isValid || !errors.Is(err, tc.want) {
+				t.Errorf("%v: want error %v, got %v (%v)", tc.name, tc.want, err, isValid)
+			}
+		}
+	})
 }
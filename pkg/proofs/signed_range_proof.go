@@ -0,0 +1,97 @@
+package proofs
+
+import (
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/internal/scheme"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// GenerateSignedRangeProof generates a Range proof which proves that all the
+// puzzle's signed plaintext values are in the range [-(q / 2), (q / 2)].
+// The puzzles hide the values that were encoded via plaintext.EncodeSigned
+// while the witnesses' x values are the signed values themselves (e.g. -42 and
+// not its encoding).
+// The puzzles are shifted by q / 2 via homomorphic addition so that the Range
+// proof for {0, ..., q} can be used.
+// Returns an error if the protocol parameters, a puzzle, q or a witness are
+// invalid or if the proof generation fails.
+func GenerateSignedRangeProof(bits int, params *params.Params, z []*puzzle.Puzzle, q *big.Int, wit []*PuzzleValues) (*RangeProof, error) {
+	return GenerateSignedRangeProofWithOptions(bits, params, z, q, wit, nil)
+}
+
+// GenerateSignedRangeProofWithOptions generates a Range proof for signed
+// plaintext values like GenerateSignedRangeProof while using the options
+// (which can be nil).
+// Returns an error if the protocol parameters, a puzzle, q or a witness are
+// invalid or if the proof generation fails.
+func GenerateSignedRangeProofWithOptions(bits int, params *params.Params, z []*puzzle.Puzzle, q *big.Int, wit []*PuzzleValues, opts *GenerateOptions) (*RangeProof, error) {
+	if len(wit) != len(z) {
+		return nil, ErrNumPuzzlesAndWitnesses
+	}
+
+	if !isValidQ(q) {
+		return nil, ErrInvalidQ
+	}
+
+	if err := validateWitnesses(wit); err != nil {
+		return nil, err
+	}
+
+	b := new(big.Int).Div(q, big.NewInt(2)) // q / 2
+
+	zPrime, err := shiftPuzzles(params, z, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Adding q / 2 to a puzzle adds q / 2 to its plaintext value and its nonce.
+	witPrime := make([]*PuzzleValues, len(wit))
+	for i, v := range wit {
+		xPrime := new(big.Int).Add(v.X, b) // x + q / 2
+		rPrime := new(big.Int).Add(v.R, b) // r + q / 2
+		witPrime[i] = NewPuzzleValues(xPrime, rPrime)
+	}
+
+	return generateRangeProof(bits, params, zPrime, q, witPrime, opts.Reader())
+}
+
+// VerifySignedRangeProof verifies a Range proof which proves that all the
+// puzzle's signed plaintext values are in the range [-(q / 2), (q / 2)].
+// Returns an error if the protocol parameters, the bits, a puzzle or q are
+// invalid, if the proof is malformed or if the proof verification fails.
+func VerifySignedRangeProof(proof *RangeProof, bits int, params *params.Params, z []*puzzle.Puzzle, q *big.Int) (bool, error) {
+	if !isValidQ(q) {
+		return false, ErrInvalidQ
+	}
+
+	b := new(big.Int).Div(q, big.NewInt(2)) // q / 2
+
+	zPrime, err := shiftPuzzles(params, z, b)
+	if err != nil {
+		return false, err
+	}
+
+	return verifyRangeProof(proof, bits, params, zPrime, q)
+}
+
+// shiftPuzzles adds the plaintext value to the values that are hidden in the
+// puzzles.
+// Returns an error if the protocol parameters or a puzzle are invalid.
+func shiftPuzzles(params *params.Params, z []*puzzle.Puzzle, p *big.Int) ([]*puzzle.Puzzle, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	zPrime := make([]*puzzle.Puzzle, len(z))
+	for i, zi := range z {
+		if err := zi.Validate(params); err != nil {
+			return nil, err
+		}
+
+		zPrime[i] = puzzle.NewPuzzle(scheme.AddPlaintextValue(params, zi.U, zi.V, p))
+	}
+
+	return zPrime, nil
+}
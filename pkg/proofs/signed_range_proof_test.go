@@ -0,0 +1,108 @@
+package proofs_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/plaintext"
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestSignedRangeProof(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		m       *big.Int
+		isValid bool
+	}{
+		{"Valid (m = -(q / 2))", big.NewInt(-500), true},
+		{"Valid (m < 0)", big.NewInt(-42), true},
+		{"Valid (m = 0)", big.NewInt(0), true},
+		{"Valid (m > 0)", big.NewInt(42), true},
+		{"Valid (m = q / 2)", big.NewInt(500), true},
+		{"Invalid (m < -(q / 2))", big.NewInt(-2_000), false},
+		{"Invalid (m > q / 2)", big.NewInt(2_000), false},
+	}
+
+	for _, tc := range tests {
+		t.Run("Prove / Verify - Single Puzzle - "+tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			bits := 128
+			q := big.NewInt(1000)
+
+			params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+			m, _ := plaintext.EncodeSigned(params, tc.m)
+			p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+			v := proofs.NewPuzzleValues(tc.m, r)
+
+			puzzles := []*puzzle.Puzzle{p}
+			values := []*proofs.PuzzleValues{v}
+
+			proof, _ := proofs.GenerateSignedRangeProof(bits, params, puzzles, q, values)
+			isValid, _ := proofs.VerifySignedRangeProof(proof, bits, params, puzzles, q)
+
+			if isValid != tc.isValid {
+				t.Error("Signed Range proof verification failed")
+			}
+		})
+	}
+
+	t.Run("Prove / Verify - Multiple Puzzles - Valid", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+		q := big.NewInt(1000)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+
+		var puzzles []*puzzle.Puzzle
+		var values []*proofs.PuzzleValues
+		for _, x := range []int64{-500, -1, 0, 1, 500} {
+			m, _ := plaintext.EncodeSigned(params, big.NewInt(x))
+			p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, m)
+
+			puzzles = append(puzzles, p)
+			values = append(values, proofs.NewPuzzleValues(big.NewInt(x), r))
+		}
+
+		proof, _ := proofs.GenerateSignedRangeProof(bits, params, puzzles, q, values)
+		isValid, _ := proofs.VerifySignedRangeProof(proof, bits, params, puzzles, q)
+
+		if isValid != true {
+			t.Error("Signed Range proof verification failed")
+		}
+	})
+
+	t.Run("Error when q or a witness are missing", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+		q := big.NewInt(1000)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1))
+		p, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params, big.NewInt(42))
+		puzzles := []*puzzle.Puzzle{p}
+
+		_, err := proofs.GenerateSignedRangeProof(bits, params, puzzles, nil, []*proofs.PuzzleValues{proofs.NewPuzzleValues(big.NewInt(42), r)})
+		if !errors.Is(err, proofs.ErrInvalidQ) {
+			t.Errorf("want error %v, got %v", proofs.ErrInvalidQ, err)
+		}
+
+		_, err = proofs.VerifySignedRangeProof(&proofs.RangeProof{}, bits, params, puzzles, nil)
+		if !errors.Is(err, proofs.ErrInvalidQ) {
+			t.Errorf("want error %v, got %v", proofs.ErrInvalidQ, err)
+		}
+
+		for _, wit := range []*proofs.PuzzleValues{nil, proofs.NewPuzzleValues(nil, r), proofs.NewPuzzleValues(big.NewInt(42), nil)} {
+			_, err := proofs.GenerateSignedRangeProof(bits, params, puzzles, q, []*proofs.PuzzleValues{wit})
+			if !errors.Is(err, proofs.ErrInvalidWitness) {
+				t.Errorf("want error %v, got %v", proofs.ErrInvalidWitness, err)
+			}
+		}
+	})
+}
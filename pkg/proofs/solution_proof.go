@@ -0,0 +1,194 @@
+package proofs
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// SolutionProof is an instance of a Solution proof.
+type SolutionProof struct {
+	// W is the value w = u^(2^t) mod n.
+	W *big.Int
+	// Pi is the Wesolowski proof pi = u^(2^t / l) mod n.
+	Pi *big.Int
+}
+
+// NewSolutionProof creates a new instance of a Solution proof.
+func NewSolutionProof(w, pi *big.Int) *SolutionProof {
+	return &SolutionProof{
+		W:  w,
+		Pi: pi,
+	}
+}
+
+// SolvePuzzleWithProof solves the puzzle and returns the plaintext that was
+// hidden inside of it alongside a Solution proof which can be used by others
+// to verify the plaintext without doing the sequential computation.
+// The context and the options (which can be nil) are handled like in
+// puzzle.SolvePuzzleContext.
+// Returns an error if the puzzle can't be solved or if the proof generation
+// fails.
+func SolvePuzzleWithProof(ctx context.Context, bits int, params *params.Params, z *puzzle.Puzzle, opts *puzzle.SolveOptions) (*big.Int, *SolutionProof, error) {
+	plaintext, w, err := puzzle.SolvePuzzleAndReturnW(ctx, params, z, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proof, err := GenerateSolutionProof(ctx, bits, params, z, w)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return plaintext, proof, nil
+}
+
+// GenerateSolutionProof generates a Solution proof which proves that
+// w = u^(2^t) mod n via the proof of exponentiation described in section
+// "4.1 The Construction" of the paper https://eprint.iacr.org/2018/623.pdf.
+// The bits determine the length of the challenge prime l.
+// Note: Computing pi takes another t sequential squarings.
+// Returns an error if the protocol parameters or the puzzle are invalid, if
+// the context is canceled or if the proof generation fails.
+func GenerateSolutionProof(ctx context.Context, bits int, params *params.Params, z *puzzle.Puzzle, w *big.Int) (*SolutionProof, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := z.Validate(params); err != nil {
+		return nil, err
+	}
+
+	// Check if w is an element of {1, ..., n - 1}.
+	if w == nil || w.Sign() <= 0 || w.Cmp(params.N) >= 0 {
+		return nil, puzzle.ErrInvalidW
+	}
+
+	// Generate challenge prime l via Fiat-Shamir transform.
+	l, err := solutionProofDataToPrime(bits, params, z, w)
+	if err != nil {
+		return nil, ErrGenerateRandomness
+	}
+
+	// Compute pi = u^(2^t / l) mod n via long division which is done one bit at
+	// a time (see section "4.1 The Construction").
+	done := ctx.Done()
+	two := big.NewInt(2)
+	b := new(big.Int)
+	r := big.NewInt(1)
+	pi := big.NewInt(1)
+	for i := big.NewInt(0); i.Cmp(params.T) < 0; i.Add(i, big.NewInt(1)) {
+		select {
+		case <-done:
+			return nil, ctx.Err()
+		default:
+		}
+
+		in1 := new(big.Int).Mul(r, two) // 2 * r
+		b.QuoRem(in1, l, r)             // b = 2 * r / l, r = 2 * r mod l
+
+		pi.Mul(pi, pi).Mod(pi, params.N) // pi^2 mod n
+		if b.Sign() != 0 {
+			pi.Mul(pi, z.U).Mod(pi, params.N) // pi^2 * u mod n
+		}
+	}
+
+	proof := NewSolutionProof(new(big.Int).Set(w), pi)
+
+	return proof, nil
+}
+
+// VerifySolutionProof verifies a Solution proof which proves that the plaintext
+// is hidden inside of the puzzle by checking that w = u^(2^t) mod n and that
+// the plaintext can be recovered from the puzzle via w.
+// Returns an error if the protocol parameters or the puzzle are invalid or if
+// the proof verification fails.
+func VerifySolutionProof(proof *SolutionProof, bits int, params *params.Params, z *puzzle.Puzzle, plaintext *big.Int) (bool, error) {
+	if err := params.Validate(); err != nil {
+		return false, err
+	}
+
+	if err := z.Validate(params); err != nil {
+		return false, err
+	}
+
+	w := proof.W
+	pi := proof.Pi
+
+	// Check if w and pi are elements of {1, ..., n - 1}.
+	for _, x := range []*big.Int{w, pi} {
+		if x == nil || x.Sign() <= 0 || x.Cmp(params.N) >= 0 {
+			return false, nil
+		}
+	}
+
+	// (Re)Generate challenge prime l via Fiat-Shamir transform.
+	l, err := solutionProofDataToPrime(bits, params, z, w)
+	if err != nil {
+		return false, ErrGenerateRandomness
+	}
+
+	// Check if pi^l * u^r = w mod n with r = 2^t mod l.
+	r := new(big.Int).Exp(big.NewInt(2), params.T, l) // 2^t mod l
+	in1 := new(big.Int).Exp(pi, l, params.N)          // pi^l mod n
+	in2 := new(big.Int).Exp(z.U, r, params.N)         // u^r mod n
+	in3 := new(big.Int).Mul(in1, in2)                 // pi^l * u^r
+	wPrime := new(big.Int).Mod(in3, params.N)         // pi^l * u^r mod n
+
+	if wPrime.Cmp(w) != 0 {
+		return false, nil
+	}
+
+	// Check if the plaintext can be recovered from the puzzle via w. This also
+	// rejects -w which passes the check above (with -pi) given that l is odd.
+	plaintextPrime, err := puzzle.RecoverPlaintext(params, z, w)
+	if err != nil {
+		return false, nil
+	}
+
+	if plaintextPrime.Cmp(plaintext) != 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// solutionProofDataToPrime implements the Fiat-Shamir transform to derive a
+// prime with the desired number of bits.
+// Returns an error if the prime can't be derived from the proof data.
+func solutionProofDataToPrime(k int, params *params.Params, z *puzzle.Puzzle, w *big.Int) (*big.Int, error) {
+	if k < 2 {
+		return nil, ErrInvalidBits
+	}
+
+	var seed []byte
+
+	// Params.
+	seed = utils.AppendUint64(seed, uint64(params.Y))
+	seed = utils.AppendBigInt(seed, params.T)
+	seed = utils.AppendBigInt(seed, params.N)
+	seed = utils.AppendBigInt(seed, params.G)
+	seed = utils.AppendBigInt(seed, params.H)
+	// Puzzle (Z).
+	seed = utils.AppendBigInt(seed, z.U)
+	seed = utils.AppendBigInt(seed, z.V)
+	// Solution (W).
+	seed = utils.AppendBigInt(seed, w)
+
+	randBytes, err := utils.GenerateRandomBytesSeeded(seed, k)
+	if err != nil {
+		return nil, ErrGenerateRandomBytes
+	}
+
+	// Set the most significant bit and search for the next prime.
+	l := new(big.Int).SetBytes(randBytes)
+	l.SetBit(l, k-1, 1)
+	for !l.ProbablyPrime(20) {
+		l.Add(l, big.NewInt(1))
+	}
+
+	return l, nil
+}
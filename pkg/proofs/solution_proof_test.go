@@ -0,0 +1,176 @@
+package proofs_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/homomorphic"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestSolutionProof(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Solve / Prove / Verify - Valid", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1_000))
+		p, _ := puzzle.GeneratePuzzle(params, m)
+
+		plaintext, proof, err := proofs.SolvePuzzleWithProof(context.Background(), bits, params, p, nil)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if plaintext.Cmp(m) != 0 {
+			t.Errorf("want %v, got %v", m, plaintext)
+		}
+
+		isValid, _ := proofs.VerifySolutionProof(proof, bits, params, p, plaintext)
+
+		if isValid != true {
+			t.Error("Solution proof verification failed")
+		}
+	})
+
+	t.Run("Solve / Prove / Verify - Valid (Combined Puzzles)", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m1 := big.NewInt(24)
+		m2 := big.NewInt(42)
+		expected := big.NewInt(66)
+
+		params, _ := params.GenerateParams(bits, 3, big.NewInt(100))
+		p1, _ := puzzle.GeneratePuzzle(params, m1)
+		p2, _ := puzzle.GeneratePuzzle(params, m2)
+		p3, _ := homomorphic.AddPlaintextValues(params, p1, p2)
+
+		_, proof, _ := proofs.SolvePuzzleWithProof(context.Background(), bits, params, p3, nil)
+		isValid, _ := proofs.VerifySolutionProof(proof, bits, params, p3, expected)
+
+		if isValid != true {
+			t.Error("Solution proof verification failed")
+		}
+	})
+
+	t.Run("Solve / Prove / Verify - Invalid (Wrong Plaintext)", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1_000))
+		p, _ := puzzle.GeneratePuzzle(params, m)
+
+		_, proof, _ := proofs.SolvePuzzleWithProof(context.Background(), bits, params, p, nil)
+		isValid, _ := proofs.VerifySolutionProof(proof, bits, params, p, big.NewInt(24))
+
+		if isValid != false {
+			t.Error("Solution proof verification failed")
+		}
+	})
+
+	t.Run("Solve / Prove / Verify - Invalid (Wrong W)", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1_000))
+		p, _ := puzzle.GeneratePuzzle(params, m)
+
+		_, w, _ := puzzle.SolvePuzzleAndReturnW(context.Background(), params, p, nil)
+		wPrime := new(big.Int).Add(w, big.NewInt(1)) // w + 1
+
+		proof, _ := proofs.GenerateSolutionProof(context.Background(), bits, params, p, wPrime)
+		isValid, _ := proofs.VerifySolutionProof(proof, bits, params, p, m)
+
+		if isValid != false {
+			t.Error("Solution proof verification failed")
+		}
+	})
+
+	t.Run("Solve / Prove / Verify - Invalid (Negated W)", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1_000))
+		p, _ := puzzle.GeneratePuzzle(params, m)
+
+		_, w, _ := puzzle.SolvePuzzleAndReturnW(context.Background(), params, p, nil)
+		wPrime := new(big.Int).Sub(params.N, w) // -w mod n
+
+		// pi is computed for the challenge prime that's derived from -w which is
+		// why -pi passes the check pi^l * u^r = -w mod n.
+		proof, _ := proofs.GenerateSolutionProof(context.Background(), bits, params, p, wPrime)
+		proof.Pi = new(big.Int).Sub(params.N, proof.Pi) // -pi mod n
+
+		isValid, _ := proofs.VerifySolutionProof(proof, bits, params, p, m)
+
+		if isValid != false {
+			t.Error("Solution proof verification failed")
+		}
+	})
+
+	t.Run("Solve / Prove / Marshal / Unmarshal / Verify", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1_000))
+		p, _ := puzzle.GeneratePuzzle(params, m)
+
+		_, proof1, _ := proofs.SolvePuzzleWithProof(context.Background(), bits, params, p, nil)
+
+		data, _ := proof1.MarshalText()
+		var proof2 proofs.SolutionProof
+		if err := proof2.UnmarshalText(data); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		isValid, _ := proofs.VerifySolutionProof(&proof2, bits, params, p, m)
+
+		if isValid != true {
+			t.Error("Solution proof verification failed")
+		}
+	})
+
+	t.Run("Error when context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+
+		m := big.NewInt(42)
+
+		params, _ := params.GenerateParams(bits, 2, big.NewInt(1_000))
+		p, _ := puzzle.GeneratePuzzle(params, m)
+
+		_, w, _ := puzzle.SolvePuzzleAndReturnW(context.Background(), params, p, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := proofs.GenerateSolutionProof(ctx, bits, params, p, w)
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want error %v, got %v", context.Canceled, err)
+		}
+	})
+}
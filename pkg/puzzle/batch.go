@@ -0,0 +1,106 @@
+package puzzle
+
+import (
+	"context"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+// SolvePuzzles solves the puzzles and returns the plaintexts that were hidden
+// inside of them (in the order of the puzzles).
+// Puzzles that share the same u value only differ in their v value which is
+// why a single squaring chain is computed per distinct u value. The chains are
+// computed by a pool of workers (defaults to the number of CPUs if <= 0).
+// Returns an error if the protocol parameters or a puzzle are invalid or if the
+// context is canceled before all puzzles are solved.
+func SolvePuzzles(ctx context.Context, params *params.Params, puzzles []*Puzzle, workers int) ([]*big.Int, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, puzzle := range puzzles {
+		if err := puzzle.Validate(params); err != nil {
+			return nil, err
+		}
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// Group the indexes of the puzzles by their u value.
+	var chains [][]int
+	indexes := make(map[string]int)
+	for i, puzzle := range puzzles {
+		key := string(puzzle.U.Bytes())
+
+		j, ok := indexes[key]
+		if !ok {
+			j = len(chains)
+			indexes[key] = j
+			chains = append(chains, nil)
+		}
+
+		chains[j] = append(chains[j], i)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*big.Int, len(puzzles))
+	jobs := make(chan []int)
+
+	var once sync.Once
+	var firstErr error
+
+	var wg sync.WaitGroup
+	for range min(workers, len(chains)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for chain := range jobs {
+				// Compute w once for all puzzles that share the same u value.
+				first := puzzles[chain[0]]
+				w, err := computeW(ctx, params, first, big.NewInt(0), first.U, nil)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+
+				for _, i := range chain {
+					results[i] = recoverPlaintext(params, puzzles[i], w)
+				}
+			}
+		}()
+	}
+
+	for _, chain := range chains {
+		select {
+		case jobs <- chain:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	// The parent context might've been canceled before all chains were handed
+	// out to the workers.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
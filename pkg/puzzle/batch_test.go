@@ -0,0 +1,100 @@
+package puzzle_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestSolvePuzzles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate Puzzles / Solve Puzzles", func(t *testing.T) {
+		t.Parallel()
+
+		nonce := big.NewInt(11)
+		messages := []*big.Int{
+			big.NewInt(1),
+			big.NewInt(2),
+			big.NewInt(3),
+			big.NewInt(4),
+			big.NewInt(5),
+		}
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+
+		// Puzzles 1, 3 and 5 share the same u value.
+		puzzle1, _ := puzzle.GeneratePuzzleWithCustomNonce(params, nonce, messages[0])
+		puzzle2, _ := puzzle.GeneratePuzzle(params, messages[1])
+		puzzle3, _ := puzzle.GeneratePuzzleWithCustomNonce(params, nonce, messages[2])
+		puzzle4, _ := puzzle.GeneratePuzzle(params, messages[3])
+		puzzle5, _ := puzzle.GeneratePuzzleWithCustomNonce(params, nonce, messages[4])
+
+		puzzles := []*puzzle.Puzzle{puzzle1, puzzle2, puzzle3, puzzle4, puzzle5}
+
+		results, err := puzzle.SolvePuzzles(context.Background(), params, puzzles, 2)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		for i, want := range messages {
+			if results[i].Cmp(want) != 0 {
+				t.Errorf("want %v, got %v", want, results[i])
+			}
+		}
+	})
+
+	t.Run("Generate Puzzles / Solve Puzzles - No Puzzles", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		results, err := puzzle.SolvePuzzles(context.Background(), params, nil, 0)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if len(results) != 0 {
+			t.Errorf("want %v results, got %v", 0, len(results))
+		}
+	})
+
+	t.Run("Error when context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+		difficulty := new(big.Int).Lsh(big.NewInt(1), 64) // 2^64
+
+		params, _ := params.GenerateParams(128, 2, difficulty)
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+		puzzle2, _ := puzzle.GeneratePuzzle(params, message)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		puzzles := []*puzzle.Puzzle{puzzle1, puzzle2}
+		_, err := puzzle.SolvePuzzles(ctx, params, puzzles, 1)
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want error %v, got %v", context.Canceled, err)
+		}
+	})
+
+	t.Run("Error when a puzzle is missing", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		puzzles := []*puzzle.Puzzle{puzzle1, nil}
+		_, err := puzzle.SolvePuzzles(context.Background(), params, puzzles, 2)
+
+		if !errors.Is(err, puzzle.ErrMissingPuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrMissingPuzzle, err)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package puzzle
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// Checkpoint is an instance of a puzzle solving run's state which can be
+// persisted and used to resume the run later on.
+type Checkpoint struct {
+	// Iteration is the number of squarings that were already computed.
+	Iteration *big.Int
+	// W is the intermediate value w = u^(2^i) mod n.
+	W *big.Int
+	// Fingerprint is the hash of the protocol parameters and the puzzle.
+	Fingerprint []byte
+}
+
+// NewCheckpoint creates a new instance of a checkpoint.
+func NewCheckpoint(iteration, w *big.Int, fingerprint []byte) *Checkpoint {
+	return &Checkpoint{
+		Iteration:   iteration,
+		W:           w,
+		Fingerprint: fingerprint,
+	}
+}
+
+// MarshalBinary encodes the checkpoint into its binary form.
+// Returns an error if the iteration or w is missing.
+func (c *Checkpoint) MarshalBinary() ([]byte, error) {
+	if c.Iteration == nil || c.W == nil {
+		return nil, ErrInvalidCheckpoint
+	}
+
+	b := utils.AppendHeader(nil, utils.TypeCheckpoint)
+	b = utils.AppendBigInt(b, c.Iteration)
+	b = utils.AppendBigInt(b, c.W)
+	b = utils.AppendBytes(b, c.Fingerprint)
+
+	return b, nil
+}
+
+// UnmarshalBinary decodes the checkpoint from its binary form.
+// Returns an error if the data isn't a valid checkpoint encoding.
+func (c *Checkpoint) UnmarshalBinary(data []byte) error {
+	d := utils.NewDecoder(data)
+
+	if err := d.ReadHeader(utils.TypeCheckpoint); err != nil {
+		if errors.Is(err, utils.ErrUnsupportedVersion) {
+			return ErrUnsupportedCheckpointVersion
+		}
+		return ErrInvalidCheckpoint
+	}
+
+	iteration, err := d.ReadBigInt()
+	if err != nil {
+		return ErrInvalidCheckpoint
+	}
+
+	w, err := d.ReadBigInt()
+	if err != nil {
+		return ErrInvalidCheckpoint
+	}
+
+	fingerprint, err := d.ReadBytes()
+	if err != nil {
+		return ErrInvalidCheckpoint
+	}
+
+	if err := d.Finish(); err != nil {
+		return ErrInvalidCheckpoint
+	}
+
+	c.Iteration = iteration
+	c.W = w
+	c.Fingerprint = fingerprint
+
+	return nil
+}
+
+// Fingerprint computes the hash of the protocol parameters and the puzzle which
+// binds a checkpoint to the puzzle solving run it belongs to.
+func Fingerprint(params *params.Params, puzzle *Puzzle) []byte {
+	b := utils.AppendUint64(nil, uint64(params.Y))
+	b = utils.AppendBigInt(b, params.T)
+	b = utils.AppendBigInt(b, params.N)
+	b = utils.AppendBigInt(b, params.G)
+	b = utils.AppendBigInt(b, params.H)
+	b = utils.AppendBigInt(b, params.NExpY)
+	b = utils.AppendBigInt(b, params.NExpYMinusOne)
+	b = utils.AppendBigInt(b, puzzle.U)
+	b = utils.AppendBigInt(b, puzzle.V)
+
+	hash := sha256.Sum256(b)
+
+	return hash[:]
+}
+
+// verifyCheckpoint checks if the checkpoint belongs to the protocol parameters
+// and the puzzle.
+// Returns an error if the checkpoint is missing, if it doesn't belong to the
+// puzzle or if its state is invalid.
+func verifyCheckpoint(params *params.Params, puzzle *Puzzle, checkpoint *Checkpoint) error {
+	if checkpoint == nil || checkpoint.Iteration == nil || checkpoint.W == nil {
+		return ErrInvalidCheckpoint
+	}
+
+	fingerprint := Fingerprint(params, puzzle)
+	if subtle.ConstantTimeCompare(fingerprint, checkpoint.Fingerprint) != 1 {
+		return ErrCheckpointMismatch
+	}
+
+	// Check if i is an element of {0, ..., t}.
+	if checkpoint.Iteration.Sign() < 0 || checkpoint.Iteration.Cmp(params.T) > 0 {
+		return ErrInvalidCheckpoint
+	}
+
+	// Check if w is an element of {1, ..., n - 1} and gcd(w, n) = 1 given that
+	// w = u^(2^i) mod n for a unit u.
+	if !utils.IsUnit(checkpoint.W, params.N) {
+		return ErrInvalidCheckpoint
+	}
+
+	return nil
+}
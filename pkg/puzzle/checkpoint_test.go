@@ -0,0 +1,203 @@
+package puzzle_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate Puzzle / Solve Puzzle / Persist Checkpoint / Resume", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var persisted []byte
+		opts := &puzzle.SolveOptions{
+			CheckpointInterval: 100,
+			OnCheckpoint: func(c *puzzle.Checkpoint) error {
+				persisted, _ = c.MarshalBinary()
+				if c.Iteration.Cmp(big.NewInt(300)) == 0 {
+					cancel()
+				}
+				return nil
+			},
+		}
+
+		_, err := puzzle.SolvePuzzleContext(ctx, params, puzzle1, opts)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("want error %v, got %v", context.Canceled, err)
+		}
+
+		var checkpoint puzzle.Checkpoint
+		if err := checkpoint.UnmarshalBinary(persisted); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if checkpoint.Iteration.Cmp(big.NewInt(300)) != 0 {
+			t.Errorf("want iteration %v, got %v", 300, checkpoint.Iteration)
+		}
+
+		mPrime, err := puzzle.ResumePuzzleContext(context.Background(), params, puzzle1, &checkpoint, nil)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+	})
+
+	t.Run("Error when persisting checkpoint fails", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+		errPersist := fmt.Errorf("unable to persist")
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		opts := &puzzle.SolveOptions{
+			CheckpointInterval: 100,
+			OnCheckpoint: func(c *puzzle.Checkpoint) error {
+				return errPersist
+			},
+		}
+
+		_, err := puzzle.SolvePuzzleContext(context.Background(), params, puzzle1, opts)
+
+		if !errors.Is(err, errPersist) {
+			t.Errorf("want error %v, got %v", errPersist, err)
+		}
+	})
+
+	t.Run("Error when checkpoint belongs to different puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+		puzzle2, _ := puzzle.GeneratePuzzle(params, message)
+
+		var checkpoint *puzzle.Checkpoint
+		opts := &puzzle.SolveOptions{
+			CheckpointInterval: 500,
+			OnCheckpoint: func(c *puzzle.Checkpoint) error {
+				checkpoint = c
+				return nil
+			},
+		}
+
+		_, _ = puzzle.SolvePuzzleContext(context.Background(), params, puzzle1, opts)
+		_, err := puzzle.ResumePuzzleContext(context.Background(), params, puzzle2, checkpoint, nil)
+
+		if !errors.Is(err, puzzle.ErrCheckpointMismatch) {
+			t.Errorf("want error %v, got %v", puzzle.ErrCheckpointMismatch, err)
+		}
+	})
+
+	t.Run("Error when checkpoint belongs to different params", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params1, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		params2 := params.NewParams(params1.Y, big.NewInt(2_000), params1.N, params1.G,
+			params1.H, params1.NExpY, params1.NExpYMinusOne)
+		puzzle1, _ := puzzle.GeneratePuzzle(params1, message)
+
+		var checkpoint *puzzle.Checkpoint
+		opts := &puzzle.SolveOptions{
+			CheckpointInterval: 500,
+			OnCheckpoint: func(c *puzzle.Checkpoint) error {
+				checkpoint = c
+				return nil
+			},
+		}
+
+		_, _ = puzzle.SolvePuzzleContext(context.Background(), params1, puzzle1, opts)
+		_, err := puzzle.ResumePuzzleContext(context.Background(), params2, puzzle1, checkpoint, nil)
+
+		if !errors.Is(err, puzzle.ErrCheckpointMismatch) {
+			t.Errorf("want error %v, got %v", puzzle.ErrCheckpointMismatch, err)
+		}
+	})
+
+	t.Run("Error when checkpoint encoding contains trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		checkpoint1 := puzzle.NewCheckpoint(big.NewInt(1), big.NewInt(2), []byte{3})
+		data, _ := checkpoint1.MarshalBinary()
+		data = append(data, 0x00)
+
+		var checkpoint2 puzzle.Checkpoint
+		err := checkpoint2.UnmarshalBinary(data)
+
+		if !errors.Is(err, puzzle.ErrInvalidCheckpoint) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidCheckpoint, err)
+		}
+	})
+
+	t.Run("Error when checkpoint is missing", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		_, err := puzzle.ResumePuzzleContext(context.Background(), params, puzzle1, nil, nil)
+
+		if !errors.Is(err, puzzle.ErrInvalidCheckpoint) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidCheckpoint, err)
+		}
+	})
+
+	t.Run("Error when checkpoint value w isn't a unit", func(t *testing.T) {
+		t.Parallel()
+
+		params, secret, _ := params.GenerateParamsWithTrapdoor(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+		fingerprint := puzzle.Fingerprint(params, puzzle1)
+
+		for _, w := range []*big.Int{big.NewInt(0), secret.P} {
+			checkpoint := puzzle.NewCheckpoint(big.NewInt(1), w, fingerprint)
+			_, err := puzzle.ResumePuzzleContext(context.Background(), params, puzzle1, checkpoint, nil)
+
+			if !errors.Is(err, puzzle.ErrInvalidCheckpoint) {
+				t.Errorf("want error %v, got %v", puzzle.ErrInvalidCheckpoint, err)
+			}
+		}
+	})
+
+	t.Run("Error when checkpoint encoding has unsupported version or wrong type", func(t *testing.T) {
+		t.Parallel()
+
+		checkpoint1 := puzzle.NewCheckpoint(big.NewInt(1), big.NewInt(2), []byte{3})
+		data, _ := checkpoint1.MarshalBinary()
+
+		var checkpoint2 puzzle.Checkpoint
+
+		data[0] = 0x02
+		if err := checkpoint2.UnmarshalBinary(data); !errors.Is(err, puzzle.ErrUnsupportedCheckpointVersion) {
+			t.Errorf("want error %v, got %v", puzzle.ErrUnsupportedCheckpointVersion, err)
+		}
+
+		data[0], data[1] = 0x01, 0x02
+		if err := checkpoint2.UnmarshalBinary(data); !errors.Is(err, puzzle.ErrInvalidCheckpoint) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidCheckpoint, err)
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package puzzle
+
+import (
+	"encoding/json"
+
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// armorType is the type of the PEM block that contains the puzzle.
+const armorType = "LHTLP PUZZLE"
+
+// puzzleJSON is the JSON representation of the puzzle.
+type puzzleJSON struct {
+	Version int    `json:"version"`
+	U       string `json:"u"`
+	V       string `json:"v"`
+}
+
+// AppendBinary appends the binary encoding of the puzzle to b.
+// The encoding consists of the header followed by the big integers u and v.
+// Returns an error if a puzzle value is missing.
+func (p *Puzzle) AppendBinary(b []byte) ([]byte, error) {
+	if p.U == nil || p.V == nil {
+		return nil, ErrEncodePuzzle
+	}
+
+	b = utils.AppendHeader(b, utils.TypePuzzle)
+	b = utils.AppendBigInt(b, p.U)
+	b = utils.AppendBigInt(b, p.V)
+
+	return b, nil
+}
+
+// MarshalBinary encodes the puzzle into its binary form.
+// Returns an error if a puzzle value is missing.
+func (p *Puzzle) MarshalBinary() ([]byte, error) {
+	return p.AppendBinary(nil)
+}
+
+// UnmarshalBinary decodes the puzzle from its binary form.
+// Returns an error if the data isn't a canonical encoding of a puzzle.
+func (p *Puzzle) UnmarshalBinary(data []byte) error {
+	d := utils.NewDecoder(data)
+
+	if err := d.ReadHeader(utils.TypePuzzle); err != nil {
+		return ErrDecodePuzzle
+	}
+
+	u, err := d.ReadBigInt()
+	if err != nil {
+		return ErrDecodePuzzle
+	}
+
+	v, err := d.ReadBigInt()
+	if err != nil {
+		return ErrDecodePuzzle
+	}
+
+	if err := d.Finish(); err != nil {
+		return ErrDecodePuzzle
+	}
+
+	*p = *NewPuzzle(u, v)
+
+	return nil
+}
+
+// MarshalJSON encodes the puzzle into its JSON form.
+// The big integers are encoded as canonical hex strings.
+// Returns an error if a puzzle value is missing.
+func (p *Puzzle) MarshalJSON() ([]byte, error) {
+	if p.U == nil || p.V == nil {
+		return nil, ErrEncodePuzzle
+	}
+
+	return json.Marshal(puzzleJSON{
+		Version: int(utils.EncodingVersion),
+		U:       utils.BigIntToHex(p.U),
+		V:       utils.BigIntToHex(p.V),
+	})
+}
+
+// UnmarshalJSON decodes the puzzle from its JSON form.
+// Returns an error if the data isn't a canonical JSON encoding of a puzzle.
+func (p *Puzzle) UnmarshalJSON(data []byte) error {
+	var v puzzleJSON
+	if err := utils.DecodeJSON(data, &v); err != nil {
+		return ErrDecodePuzzle
+	}
+
+	if v.Version != int(utils.EncodingVersion) {
+		return ErrDecodePuzzle
+	}
+
+	u, err := utils.HexToBigInt(v.U)
+	if err != nil {
+		return ErrDecodePuzzle
+	}
+
+	w, err := utils.HexToBigInt(v.V)
+	if err != nil {
+		return ErrDecodePuzzle
+	}
+
+	*p = *NewPuzzle(u, w)
+
+	return nil
+}
+
+// MarshalText encodes the puzzle into its armored text form which is a PEM
+// block that contains the binary encoding.
+// Returns an error if a puzzle value is missing.
+func (p *Puzzle) MarshalText() ([]byte, error) {
+	data, err := p.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.Armor(armorType, data), nil
+}
+
+// UnmarshalText decodes the puzzle from its armored text form.
+// Returns an error if the text isn't a valid armored encoding of a puzzle.
+func (p *Puzzle) UnmarshalText(text []byte) error {
+	data, err := utils.Dearmor(armorType, text)
+	if err != nil {
+		return ErrDecodePuzzle
+	}
+
+	return p.UnmarshalBinary(data)
+}
@@ -0,0 +1,184 @@
+package puzzle_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestPuzzleBinaryEncoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Marshal / Unmarshal", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		data, err := puzzle1.MarshalBinary()
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		var puzzle2 puzzle.Puzzle
+		if err := puzzle2.UnmarshalBinary(data); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if !puzzle1.Equal(&puzzle2) {
+			t.Errorf("puzzles are not equal %v %v", puzzle1, &puzzle2)
+		}
+
+		mPrime := puzzle.SolvePuzzle(params, &puzzle2)
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+	})
+
+	t.Run("Error when encoding contains trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		puzzle1 := puzzle.NewPuzzle(big.NewInt(1), big.NewInt(2))
+
+		data, _ := puzzle1.MarshalBinary()
+		data = append(data, 0x00)
+
+		var puzzle2 puzzle.Puzzle
+		err := puzzle2.UnmarshalBinary(data)
+
+		if !errors.Is(err, puzzle.ErrDecodePuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrDecodePuzzle, err)
+		}
+	})
+
+	t.Run("Error when encoding is not canonical", func(t *testing.T) {
+		t.Parallel()
+
+		// Header followed by u = 1 (with a leading zero byte) and v = 2.
+		data := []byte{
+			0x01, 0x02,
+			0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01,
+			0x00, 0x00, 0x00, 0x00, 0x01, 0x02,
+		}
+
+		var puzzle1 puzzle.Puzzle
+		err := puzzle1.UnmarshalBinary(data)
+
+		if !errors.Is(err, puzzle.ErrDecodePuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrDecodePuzzle, err)
+		}
+	})
+
+	t.Run("Error when encoding has unexpected type", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		data, _ := params.MarshalBinary()
+
+		var puzzle1 puzzle.Puzzle
+		err := puzzle1.UnmarshalBinary(data)
+
+		if !errors.Is(err, puzzle.ErrDecodePuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrDecodePuzzle, err)
+		}
+	})
+}
+
+func TestPuzzleTextEncoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Unmarshal / Marshal - Golden JSON", func(t *testing.T) {
+		t.Parallel()
+
+		golden, _ := os.ReadFile("../../testdata/puzzle.json")
+
+		var puzzle1 puzzle.Puzzle
+		if err := json.Unmarshal(golden, &puzzle1); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		got, _ := json.Marshal(&puzzle1)
+
+		var want bytes.Buffer
+		_ = json.Compact(&want, golden)
+
+		if !bytes.Equal(got, want.Bytes()) {
+			t.Errorf("want %s, got %s", want.Bytes(), got)
+		}
+	})
+
+	t.Run("Unmarshal / Marshal - Golden Armored Text", func(t *testing.T) {
+		t.Parallel()
+
+		golden, _ := os.ReadFile("../../testdata/puzzle.pem")
+
+		var puzzle1 puzzle.Puzzle
+		if err := puzzle1.UnmarshalText(golden); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		got, _ := puzzle1.MarshalText()
+
+		if !bytes.Equal(got, golden) {
+			t.Errorf("want %s, got %s", golden, got)
+		}
+	})
+
+	t.Run("Unmarshal Golden Files / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		goldenParams, _ := os.ReadFile("../../testdata/params.json")
+		goldenPuzzle, _ := os.ReadFile("../../testdata/puzzle.json")
+
+		var params1 params.Params
+		_ = json.Unmarshal(goldenParams, &params1)
+
+		var puzzle1 puzzle.Puzzle
+		_ = json.Unmarshal(goldenPuzzle, &puzzle1)
+
+		mPrime := puzzle.SolvePuzzle(&params1, &puzzle1)
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+	})
+
+	t.Run("Error when JSON is missing fields", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"version":1,"u":"1"}`)
+
+		var puzzle1 puzzle.Puzzle
+		err := json.Unmarshal(data, &puzzle1)
+
+		if !errors.Is(err, puzzle.ErrDecodePuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrDecodePuzzle, err)
+		}
+	})
+
+	t.Run("Error when armored text contains trailing data", func(t *testing.T) {
+		t.Parallel()
+
+		golden, _ := os.ReadFile("../../testdata/puzzle.pem")
+		text := append(golden, golden...)
+
+		var puzzle1 puzzle.Puzzle
+		err := puzzle1.UnmarshalText(text)
+
+		if !errors.Is(err, puzzle.ErrDecodePuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrDecodePuzzle, err)
+		}
+	})
+}
@@ -2,5 +2,27 @@ package puzzle
 
 import "fmt"
 
-// ErrSampleNonceR is returned if the random nonce r can't be sampled.
-var ErrSampleNonceR = fmt.Errorf("unable to sample random nonce r")
+var (
+	// ErrSampleNonceR is returned if the random nonce r can't be sampled.
+	ErrSampleNonceR = fmt.Errorf("unable to sample random nonce r")
+	// ErrMissingPuzzle is returned if the puzzle is missing.
+	ErrMissingPuzzle = fmt.Errorf("missing puzzle")
+	// ErrInvalidU is returned if the puzzle's u value is missing, out of range or not coprime with n.
+	ErrInvalidU = fmt.Errorf("invalid puzzle value u")
+	// ErrInvalidV is returned if the puzzle's v value is missing, out of range or not coprime with n.
+	ErrInvalidV = fmt.Errorf("invalid puzzle value v")
+	// ErrInvalidW is returned if w is missing, out of range or not coprime with n.
+	ErrInvalidW = fmt.Errorf("invalid w")
+	// ErrInvalidSecretParams is returned if the secret protocol parameters are missing or phi(n) isn't positive.
+	ErrInvalidSecretParams = fmt.Errorf("invalid secret params")
+	// ErrEncodePuzzle is returned if the puzzle can't be encoded.
+	ErrEncodePuzzle = fmt.Errorf("unable to encode puzzle")
+	// ErrDecodePuzzle is returned if the puzzle can't be decoded.
+	ErrDecodePuzzle = fmt.Errorf("unable to decode puzzle")
+	// ErrInvalidCheckpoint is returned if the checkpoint is malformed.
+	ErrInvalidCheckpoint = fmt.Errorf("invalid checkpoint")
+	// ErrUnsupportedCheckpointVersion is returned if the checkpoint's encoding version is not supported.
+	ErrUnsupportedCheckpointVersion = fmt.Errorf("unsupported checkpoint version")
+	// ErrCheckpointMismatch is returned if the checkpoint belongs to different protocol parameters or a different puzzle.
+	ErrCheckpointMismatch = fmt.Errorf("checkpoint belongs to different params or puzzle")
+)
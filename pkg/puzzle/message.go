@@ -0,0 +1,55 @@
+package puzzle
+
+import (
+	"context"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/plaintext"
+)
+
+// GenerateMessagePuzzles generates puzzles that hide the message of arbitrary
+// length. The message is split into chunks that fit into the message space
+// (see plaintext.EncodeBytes) and every chunk is hidden in its own puzzle.
+// Note: Every puzzle uses its own nonce which is why solving the puzzles takes
+// one squaring chain per puzzle (see SolveMessagePuzzles).
+// Returns an error if the protocol parameters are invalid or if the generation
+// of a puzzle fails.
+func GenerateMessagePuzzles(params *params.Params, message []byte) ([]*Puzzle, error) {
+	return GenerateMessagePuzzlesWithOptions(params, message, nil)
+}
+
+// GenerateMessagePuzzlesWithOptions generates puzzles that hide the message
+// like GenerateMessagePuzzles while using the options (which can be nil).
+// Returns an error if the protocol parameters are invalid or if the generation
+// of a puzzle fails.
+func GenerateMessagePuzzlesWithOptions(params *params.Params, message []byte, opts *GenerateOptions) ([]*Puzzle, error) {
+	plaintexts, err := plaintext.EncodeBytes(params, message)
+	if err != nil {
+		return nil, err
+	}
+
+	puzzles := make([]*Puzzle, len(plaintexts))
+	for i, m := range plaintexts {
+		puzzles[i], _, err = GeneratePuzzleAndReturnNonceWithOptions(params, m, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return puzzles, nil
+}
+
+// SolveMessagePuzzles solves the puzzles that were generated via
+// GenerateMessagePuzzles and returns the message that was hidden inside of
+// them. The puzzles are solved in parallel like in SolvePuzzles.
+// Returns an error if the protocol parameters or a puzzle are invalid, if the
+// context is canceled or if the solved plaintext values aren't an encoding of a
+// message.
+func SolveMessagePuzzles(ctx context.Context, params *params.Params, puzzles []*Puzzle, workers int) ([]byte, error) {
+	plaintexts, err := SolvePuzzles(ctx, params, puzzles, workers)
+	if err != nil {
+		return nil, err
+	}
+
+	return plaintext.DecodeBytes(params, plaintexts)
+}
@@ -0,0 +1,62 @@
+package puzzle_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/plaintext"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestMessagePuzzles(t *testing.T) {
+	t.Parallel()
+
+	params, _ := params.GenerateParams(128, 2, big.NewInt(100))
+
+	tests := []struct {
+		name    string
+		message []byte
+	}{
+		{"Empty Message", []byte{}},
+		{"Short Message", []byte("hello")},
+		{"Trailing Zero Bytes", []byte{1, 2, 3, 0, 0, 0}},
+		{"Only Zero Bytes", make([]byte, 20)},
+		{"Long Message", bytes.Repeat([]byte("Linearly Homomorphic Time-Lock Puzzles"), 10)},
+	}
+
+	for _, tc := range tests {
+		t.Run("Generate Puzzles / Solve Puzzles - "+tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			puzzles, err := puzzle.GenerateMessagePuzzles(params, tc.message)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			message, err := puzzle.SolveMessagePuzzles(context.Background(), params, puzzles, 0)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if !bytes.Equal(message, tc.message) {
+				t.Errorf("want %v, got %v", tc.message, message)
+			}
+		})
+	}
+
+	t.Run("Error when puzzles are missing", func(t *testing.T) {
+		t.Parallel()
+
+		puzzles, _ := puzzle.GenerateMessagePuzzles(params, make([]byte, 100))
+
+		_, err := puzzle.SolveMessagePuzzles(context.Background(), params, puzzles[:len(puzzles)-1], 0)
+
+		if !errors.Is(err, plaintext.ErrInvalidEncoding) {
+			t.Errorf("want error %v, got %v", plaintext.ErrInvalidEncoding, err)
+		}
+	})
+}
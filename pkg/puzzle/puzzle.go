@@ -1,9 +1,10 @@
 package puzzle
 
 import (
-	"crypto/rand"
+	"context"
 	"math/big"
 
+	"github.com/primefactor-io/lhtlp/pkg/internal/scheme"
 	"github.com/primefactor-io/lhtlp/pkg/params"
 	"github.com/primefactor-io/lhtlp/pkg/utils"
 )
@@ -29,8 +30,35 @@ func (p *Puzzle) Equal(other *Puzzle) bool {
 	return p.U.Cmp(other.U) == 0 && p.V.Cmp(other.V) == 0
 }
 
+// Validate checks if the puzzle is well-formed with respect to the protocol
+// parameters.
+// Note: The caller needs to ensure that the protocol parameters are valid.
+// Returns an error if the puzzle or a puzzle value is missing or invalid.
+func (p *Puzzle) Validate(params *params.Params) error {
+	if p == nil {
+		return ErrMissingPuzzle
+	}
+
+	// Check if u is an element of {1, ..., n - 1} and gcd(u, n) = 1.
+	if !utils.IsUnit(p.U, params.N) {
+		return ErrInvalidU
+	}
+
+	// Check if v is an element of {1, ..., n^y - 1} and gcd(v, n) = 1.
+	if !utils.IsUnit(p.V, params.NExpY) {
+		return ErrInvalidV
+	}
+
+	return nil
+}
+
+// GenerateOptions configures the generation of puzzles (only its source of
+// randomness applies).
+type GenerateOptions = params.GenerateOptions
+
 // GeneratePuzzle generates a puzzle that hides the plaintext.
-// Returns an error if the generation of the puzzle fails.
+// Returns an error if the protocol parameters are invalid or if the generation
+// of the puzzle fails.
 func GeneratePuzzle(params *params.Params, plaintext *big.Int) (*Puzzle, error) {
 	puzzle, _, err := GeneratePuzzleAndReturnNonce(params, plaintext)
 	if err != nil {
@@ -42,67 +70,133 @@ func GeneratePuzzle(params *params.Params, plaintext *big.Int) (*Puzzle, error)
 
 // GeneratePuzzleAndReturnNonce generates a puzzle that hides the plaintext while
 // also returning the nonce that was used for randomness.
-// Returns an error if the generation of the puzzle fails.
+// Returns an error if the protocol parameters are invalid or if the generation
+// of the puzzle fails.
 func GeneratePuzzleAndReturnNonce(params *params.Params, plaintext *big.Int) (*Puzzle, *big.Int, error) {
-	nExpMinusOne := new(big.Int).Sub(params.NExpY, big.NewInt(1)) // n^y - 1
+	return GeneratePuzzleAndReturnNonceWithOptions(params, plaintext, nil)
+}
+
+// GeneratePuzzleAndReturnNonceWithOptions generates a puzzle that hides the
+// plaintext while also returning the nonce that was used for randomness like
+// GeneratePuzzleAndReturnNonce while using the options (which can be nil).
+// Returns an error if the protocol parameters are invalid or if the generation
+// of the puzzle fails.
+func GeneratePuzzleAndReturnNonceWithOptions(params *params.Params, plaintext *big.Int, opts *GenerateOptions) (*Puzzle, *big.Int, error) {
+	if err := params.Validate(); err != nil {
+		return nil, nil, err
+	}
 
 	// Sample a random nonce r.
-	nonce, err := rand.Int(rand.Reader, nExpMinusOne)
+	nonce, err := scheme.SampleNonce(params, opts.Reader())
 	if err != nil {
 		return nil, nil, ErrSampleNonceR
 	}
 
-	puzzle, err := GeneratePuzzleWithCustomNonce(params, nonce, plaintext)
-	if err != nil {
-		return nil, nil, err
-	}
+	puzzle := generatePuzzle(params, nonce, plaintext)
 
-	return puzzle, nonce, err
+	return puzzle, nonce, nil
 }
 
 // GeneratePuzzleWithCustomNonce generates a puzzle that hides the plaintext
 // using the passed-in nonce for randomness.
-// Returns an error if the generation of the puzzle fails.
+// Returns an error if the protocol parameters are invalid.
 func GeneratePuzzleWithCustomNonce(params *params.Params, nonce, plaintext *big.Int) (*Puzzle, error) {
-	r := nonce
-	s := plaintext
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
 
-	// Compute u.
-	u := new(big.Int).Exp(params.G, r, params.N) // g^r mod n
+	puzzle := generatePuzzle(params, nonce, plaintext)
 
-	// Compute v.
-	in1 := new(big.Int).Mul(r, params.NExpYMinusOne)     // r * n^(y - 1)
-	in2 := new(big.Int).Exp(params.H, in1, params.NExpY) // h^(r * n^(y - 1)) mod n^y
-	in3 := new(big.Int).Add(big.NewInt(1), params.N)     // 1 + n
-	in4 := new(big.Int).Exp(in3, s, params.NExpY)        // (1 + n)^s mod n^y
-	in5 := new(big.Int).Mul(in2, in4)                    // h^(r * n^(y - 1)) * (1 + n)^s
-	v := new(big.Int).Mod(in5, params.NExpY)             // h^(r * n^(y - 1)) * (1 + n)^s mod n^y
+	return puzzle, nil
+}
 
-	puzzle := NewPuzzle(u, v)
+// generatePuzzle generates a puzzle that hides the plaintext using the nonce
+// for randomness.
+// Note: The caller needs to ensure that the protocol parameters are valid.
+func generatePuzzle(params *params.Params, nonce, plaintext *big.Int) *Puzzle {
+	u, v := scheme.GeneratePuzzle(params, nonce, plaintext)
 
-	return puzzle, nil
+	return NewPuzzle(u, v)
 }
 
 // SolvePuzzle solves the puzzle and returns the plaintext that was hidden inside
 // of it.
 func SolvePuzzle(params *params.Params, puzzle *Puzzle) *big.Int {
-	// Compute w = u^(2^t) mod n by repeated squaring.
-	i := big.NewInt(0)
-	w := new(big.Int).Set(puzzle.U) // w = u
-	for {
-		w = new(big.Int).Exp(w, big.NewInt(2), params.N) // w^2 mod n
-		i = new(big.Int).Add(i, big.NewInt(1))           // i + 1
-		if i.Cmp(params.T) == 0 {
-			break
-		}
+	// The background context is never canceled which is why no error can occur.
+	w, _ := computeW(context.Background(), params, puzzle, big.NewInt(0), puzzle.U, nil)
+
+	return recoverPlaintext(params, puzzle, w)
+}
+
+// SolvePuzzleWithTrapdoor solves the puzzle via the secret protocol parameters
+// and returns the plaintext that was hidden inside of it.
+// Note: The sequential computation is skipped given that 2^t can be reduced
+// modulo phi(n) / 2 when phi(n) is known.
+// Returns an error if the protocol parameters or the puzzle are invalid or if
+// the secret protocol parameters are missing.
+func SolvePuzzleWithTrapdoor(params *params.Params, secret *params.SecretParams, puzzle *Puzzle) (*big.Int, error) {
+	if err := validate(params, puzzle); err != nil {
+		return nil, err
+	}
+
+	if secret == nil || secret.PhiN == nil || secret.PhiN.Sign() <= 0 {
+		return nil, ErrInvalidSecretParams
+	}
+
+	phiNHalf := new(big.Int).Div(secret.PhiN, big.NewInt(2)) // phiN / 2
+
+	// Compute w = u^(2^t) mod n via the trapdoor.
+	e := new(big.Int).Exp(big.NewInt(2), params.T, phiNHalf) // 2^t mod (phiN / 2)
+	w := new(big.Int).Exp(puzzle.U, e, params.N)             // u^(2^t mod (phiN / 2)) mod n
+
+	return recoverPlaintext(params, puzzle, w), nil
+}
+
+// RecoverPlaintext recovers the plaintext that was hidden inside of the puzzle
+// given w = u^(2^t) mod n without doing the sequential computation.
+// Returns an error if the protocol parameters or the puzzle are invalid or if
+// w isn't an element of {1, ..., n - 1} that's coprime with n.
+func RecoverPlaintext(params *params.Params, puzzle *Puzzle, w *big.Int) (*big.Int, error) {
+	if err := validate(params, puzzle); err != nil {
+		return nil, err
 	}
 
-	// Compute a = (1 + n)^s mod n^y.
+	if !utils.IsUnit(w, params.N) {
+		return nil, ErrInvalidW
+	}
+
+	a := computeA(params, puzzle, w)
+
+	// Check if a = 1 mod n given that a = (1 + n)^s mod n^y for the correct w.
+	// This rules out e.g. -w which would yield -a.
+	if new(big.Int).Mod(a, params.N).Cmp(big.NewInt(1)) != 0 {
+		return nil, ErrInvalidW
+	}
+
+	return discreteLog(params, a), nil
+}
+
+// recoverPlaintext recovers the plaintext that was hidden inside of the puzzle
+// given w = u^(2^t) mod n.
+func recoverPlaintext(params *params.Params, puzzle *Puzzle, w *big.Int) *big.Int {
+	a := computeA(params, puzzle, w)
+
+	return discreteLog(params, a)
+}
+
+// computeA computes a = v * w^-(n^(y - 1)) mod n^y which is a = (1 + n)^s mod
+// n^y given w = u^(2^t) mod n.
+func computeA(params *params.Params, puzzle *Puzzle, w *big.Int) *big.Int {
 	in1 := new(big.Int).Exp(w, params.NExpYMinusOne, params.NExpY) // w^(n^(y - 1)) mod n^y
 	in2 := new(big.Int).ModInverse(in1, params.NExpY)              // w^-(n^(y - 1)) mod n^y
 	in3 := new(big.Int).Mul(puzzle.V, in2)                         // v * w^-(n^(y - 1))
 	a := new(big.Int).Mod(in3, params.NExpY)                       // v * w^-(n^(y - 1)) mod n^y
 
+	return a
+}
+
+// discreteLog computes s given a = (1 + n)^s mod n^y.
+func discreteLog(params *params.Params, a *big.Int) *big.Int {
 	// Compute s via the polynomial-time discrete-logarithm algorithm described in
 	// section "3 A Generalisation of Paillier’s Probabilistic Encryption Scheme"
 	// of the paper https://www.brics.dk/RS/00/45/BRICS-RS-00-45.pdf.
@@ -1,9 +1,14 @@
 package puzzle_test
 
 import (
+	"context"
+	"errors"
 	"math/big"
+	mrand "math/rand/v2"
 	"testing"
+	"testing/iotest"
 
+	"github.com/primefactor-io/lhtlp/pkg/homomorphic"
 	"github.com/primefactor-io/lhtlp/pkg/params"
 	"github.com/primefactor-io/lhtlp/pkg/puzzle"
 )
@@ -72,6 +77,97 @@ func TestPuzzle(t *testing.T) {
 		}
 	})
 
+	t.Run("Generate Puzzle / Solve Puzzle - Custom Randomness", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		opts1 := &puzzle.GenerateOptions{Rand: mrand.NewChaCha8([32]byte{1})}
+		opts2 := &puzzle.GenerateOptions{Rand: mrand.NewChaCha8([32]byte{1})}
+		puzzle1, r1, _ := puzzle.GeneratePuzzleAndReturnNonceWithOptions(params, message, opts1)
+		puzzle2, r2, _ := puzzle.GeneratePuzzleAndReturnNonceWithOptions(params, message, opts2)
+
+		if !puzzle1.Equal(puzzle2) || r1.Cmp(r2) != 0 {
+			t.Error("want equal puzzles for the same randomness")
+		}
+
+		mPrime := puzzle.SolvePuzzle(params, puzzle1)
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+	})
+
+	t.Run("Generate Puzzle / Solve Puzzle - Trapdoor", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, secret, _ := params.GenerateParamsWithTrapdoor(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		mPrime1, _ := puzzle.SolvePuzzleWithTrapdoor(params, secret, puzzle1)
+		mPrime2 := puzzle.SolvePuzzle(params, puzzle1)
+
+		if mPrime1.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime1)
+		}
+		if mPrime2.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime2)
+		}
+	})
+
+	t.Run("Generate Puzzle / Solve Puzzle - Trapdoor - Large Difficulty", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+		difficulty := new(big.Int).Lsh(big.NewInt(1), 64) // 2^64
+
+		params, secret, _ := params.GenerateParamsWithTrapdoor(128, 3, difficulty)
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		mPrime, _ := puzzle.SolvePuzzleWithTrapdoor(params, secret, puzzle1)
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+	})
+
+	t.Run("Generate Puzzles / Add Message Values / Solve Puzzle - Trapdoor", func(t *testing.T) {
+		t.Parallel()
+
+		message1 := big.NewInt(24)
+		message2 := big.NewInt(42)
+		expected := big.NewInt(66)
+
+		params, secret, _ := params.GenerateParamsWithTrapdoor(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message1)
+		puzzle2, _ := puzzle.GeneratePuzzle(params, message2)
+
+		puzzle3, _ := homomorphic.AddPlaintextValues(params, puzzle1, puzzle2)
+
+		mPrime, _ := puzzle.SolvePuzzleWithTrapdoor(params, secret, puzzle3)
+
+		if mPrime.Cmp(expected) != 0 {
+			t.Errorf("want %v, got %v", expected, mPrime)
+		}
+	})
+
+	t.Run("Error when secret params are missing", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		_, err := puzzle.SolvePuzzleWithTrapdoor(params, nil, puzzle1)
+
+		if !errors.Is(err, puzzle.ErrInvalidSecretParams) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidSecretParams, err)
+		}
+	})
+
 	t.Run("Puzzle Equality", func(t *testing.T) {
 		t.Parallel()
 
@@ -86,4 +182,98 @@ func TestPuzzle(t *testing.T) {
 			t.Errorf("puzzles are not equal %v %v", p1, p2)
 		}
 	})
+	t.Run("Error when randomness fails", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+
+		opts := &puzzle.GenerateOptions{Rand: iotest.ErrReader(errors.New("failure"))}
+		_, _, err := puzzle.GeneratePuzzleAndReturnNonceWithOptions(params, big.NewInt(42), opts)
+
+		if !errors.Is(err, puzzle.ErrSampleNonceR) {
+			t.Errorf("want error %v, got %v", puzzle.ErrSampleNonceR, err)
+		}
+	})
+
+	t.Run("Error when params are invalid", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		p, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		params1 := params.NewParams(p.Y, p.T, p.N, p.G, p.H, p.N, p.NExpYMinusOne)
+
+		_, err1 := puzzle.GeneratePuzzle(params1, message)
+		_, err2 := puzzle.GeneratePuzzleWithCustomNonce(params1, big.NewInt(11), message)
+
+		if !errors.Is(err1, params.ErrInvalidNExpY) {
+			t.Errorf("want error %v, got %v", params.ErrInvalidNExpY, err1)
+		}
+		if !errors.Is(err2, params.ErrInvalidNExpY) {
+			t.Errorf("want error %v, got %v", params.ErrInvalidNExpY, err2)
+		}
+	})
+}
+
+func TestPuzzleValidation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		if err := puzzle1.Validate(params); err != nil {
+			t.Errorf("want no error, got %v", err)
+		}
+
+		mPrime, err := puzzle.SolvePuzzleContext(context.Background(), params, puzzle1, nil)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+	})
+
+	t.Run("Error when puzzle is malformed", func(t *testing.T) {
+		t.Parallel()
+
+		params, secret, _ := params.GenerateParamsWithTrapdoor(128, 2, big.NewInt(1))
+		p, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		tests := []struct {
+			name   string
+			puzzle *puzzle.Puzzle
+			want   error
+		}{
+			{"u = 0", puzzle.NewPuzzle(big.NewInt(0), p.V), puzzle.ErrInvalidU},
+			{"u missing", puzzle.NewPuzzle(nil, p.V), puzzle.ErrInvalidU},
+			{"u >= n", puzzle.NewPuzzle(params.N, p.V), puzzle.ErrInvalidU},
+			{"gcd(u, n) != 1", puzzle.NewPuzzle(secret.P, p.V), puzzle.ErrInvalidU},
+			{"v = 0", puzzle.NewPuzzle(p.U, big.NewInt(0)), puzzle.ErrInvalidV},
+			{"v >= n^y", puzzle.NewPuzzle(p.U, params.NExpY), puzzle.ErrInvalidV},
+			{"gcd(v, n) != 1", puzzle.NewPuzzle(p.U, secret.Q), puzzle.ErrInvalidV},
+		}
+
+		for _, tt := range tests {
+			err1 := tt.puzzle.Validate(params)
+			_, err2 := puzzle.SolvePuzzleContext(context.Background(), params, tt.puzzle, nil)
+			_, err3 := puzzle.SolvePuzzleWithTrapdoor(params, secret, tt.puzzle)
+
+			if !errors.Is(err1, tt.want) {
+				t.Errorf("%v: want error %v, got %v", tt.name, tt.want, err1)
+			}
+			if !errors.Is(err2, tt.want) {
+				t.Errorf("%v: want error %v, got %v", tt.name, tt.want, err2)
+			}
+			if !errors.Is(err3, tt.want) {
+				t.Errorf("%v: want error %v, got %v", tt.name, tt.want, err3)
+			}
+		}
+	})
 }
@@ -0,0 +1,191 @@
+package puzzle
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+// DefaultProgressInterval is the number of squarings between two progress
+// reports if no interval is configured.
+const DefaultProgressInterval = 1 << 16
+
+// Progress is an instance of a progress report of a puzzle solving run.
+type Progress struct {
+	// Squarings is the number of squarings that were already computed.
+	Squarings *big.Int
+	// Total is the total number of squarings (the difficulty t).
+	Total *big.Int
+	// Elapsed is the time that has elapsed since the solving run was started.
+	Elapsed time.Duration
+	// Remaining is the estimated time it takes to compute the outstanding
+	// squarings.
+	Remaining time.Duration
+}
+
+// SolveOptions is an instance of options for a puzzle solving run.
+type SolveOptions struct {
+	// ProgressInterval is the number of squarings between two progress reports.
+	// Defaults to DefaultProgressInterval if not set.
+	ProgressInterval uint64
+	// OnProgress is called with a progress report every ProgressInterval
+	// squarings. It's not called if nil.
+	OnProgress func(Progress)
+	// CheckpointInterval is the number of squarings between two checkpoints.
+	// No checkpoints are created if not set.
+	CheckpointInterval uint64
+	// OnCheckpoint is called with a checkpoint every CheckpointInterval
+	// squarings so that it can be persisted. The puzzle solving run is aborted
+	// if it returns an error.
+	OnCheckpoint func(*Checkpoint) error
+}
+
+// SolvePuzzleContext solves the puzzle and returns the plaintext that was hidden
+// inside of it while honoring the cancellation of the context and reporting
+// the progress via the options (which can be nil).
+// Returns an error if the protocol parameters or the puzzle are invalid, if
+// the context is canceled or if a checkpoint can't be persisted before the
+// puzzle is solved.
+func SolvePuzzleContext(ctx context.Context, params *params.Params, puzzle *Puzzle, opts *SolveOptions) (*big.Int, error) {
+	plaintext, _, err := SolvePuzzleAndReturnW(ctx, params, puzzle, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return plaintext, nil
+}
+
+// SolvePuzzleAndReturnW solves the puzzle like SolvePuzzleContext while also
+// returning w = u^(2^t) mod n which can be used by others to recover the
+// plaintext without doing the sequential computation.
+// Returns an error if the protocol parameters or the puzzle are invalid, if
+// the context is canceled or if a checkpoint can't be persisted before the
+// puzzle is solved.
+func SolvePuzzleAndReturnW(ctx context.Context, params *params.Params, puzzle *Puzzle, opts *SolveOptions) (*big.Int, *big.Int, error) {
+	if err := validate(params, puzzle); err != nil {
+		return nil, nil, err
+	}
+
+	w, err := computeW(ctx, params, puzzle, big.NewInt(0), puzzle.U, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return recoverPlaintext(params, puzzle, w), w, nil
+}
+
+// ResumePuzzleContext resumes the puzzle solving run from the checkpoint and
+// returns the plaintext that was hidden inside of the puzzle. The context and
+// the options (which can be nil) are handled like in SolvePuzzleContext.
+// Returns an error if the protocol parameters or the puzzle are invalid, if the
+// checkpoint doesn't belong to the protocol parameters and the puzzle, if the
+// context is canceled or if a checkpoint can't be persisted before the puzzle
+// is solved.
+func ResumePuzzleContext(ctx context.Context, params *params.Params, puzzle *Puzzle, checkpoint *Checkpoint, opts *SolveOptions) (*big.Int, error) {
+	if err := validate(params, puzzle); err != nil {
+		return nil, err
+	}
+
+	if err := verifyCheckpoint(params, puzzle, checkpoint); err != nil {
+		return nil, err
+	}
+
+	w, err := computeW(ctx, params, puzzle, checkpoint.Iteration, checkpoint.W, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return recoverPlaintext(params, puzzle, w), nil
+}
+
+// computeW computes w = u^(2^t) mod n by repeated squaring starting with the
+// intermediate value w = u^(2^i) mod n.
+// Returns an error if the context is canceled or if a checkpoint can't be
+// persisted before w is computed.
+func computeW(ctx context.Context, params *params.Params, puzzle *Puzzle, iteration, intermediate *big.Int, opts *SolveOptions) (*big.Int, error) {
+	if opts == nil {
+		opts = &SolveOptions{}
+	}
+
+	interval := opts.ProgressInterval
+	if interval == 0 {
+		interval = DefaultProgressInterval
+	}
+
+	var fingerprint []byte
+	if opts.OnCheckpoint != nil && opts.CheckpointInterval > 0 {
+		fingerprint = Fingerprint(params, puzzle)
+	}
+
+	start := time.Now()
+	done := ctx.Done()
+
+	var counter uint64
+	first := new(big.Int).Set(iteration)
+	i := new(big.Int).Set(iteration)
+	w := new(big.Int).Set(intermediate) // w = u^(2^i)
+	for i.Cmp(params.T) < 0 {
+		select {
+		case <-done:
+			return nil, ctx.Err()
+		default:
+		}
+
+		w.Mul(w, w).Mod(w, params.N) // w^2 mod n
+		i.Add(i, big.NewInt(1))      // i + 1
+
+		counter++
+		if opts.OnProgress != nil && counter%interval == 0 {
+			opts.OnProgress(newProgress(first, i, params.T, time.Since(start)))
+		}
+
+		if fingerprint != nil && counter%opts.CheckpointInterval == 0 {
+			checkpoint := NewCheckpoint(new(big.Int).Set(i), new(big.Int).Set(w), fingerprint)
+			if err := opts.OnCheckpoint(checkpoint); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// newProgress creates a new progress report and estimates the remaining time
+// based on the average time per squaring since the run was (re)started at
+// iteration first.
+func newProgress(first, squarings, total *big.Int, elapsed time.Duration) Progress {
+	remaining := time.Duration(0)
+
+	computed := new(big.Int).Sub(squarings, first) // i - first
+	if computed.Sign() > 0 {
+		in1 := new(big.Int).Sub(total, squarings)                // t - i
+		in2 := new(big.Int).Mul(in1, big.NewInt(int64(elapsed))) // (t - i) * elapsed
+		in3 := new(big.Int).Div(in2, computed)                   // (t - i) * elapsed / (i - first)
+
+		remaining = time.Duration(math.MaxInt64)
+		if in3.IsInt64() {
+			remaining = time.Duration(in3.Int64())
+		}
+	}
+
+	return Progress{
+		Squarings: new(big.Int).Set(squarings),
+		Total:     new(big.Int).Set(total),
+		Elapsed:   elapsed,
+		Remaining: remaining,
+	}
+}
+
+// validate checks if the protocol parameters are valid and if the puzzle is
+// well-formed with respect to them.
+// Returns an error if the protocol parameters or the puzzle are invalid.
+func validate(params *params.Params, puzzle *Puzzle) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	return puzzle.Validate(params)
+}
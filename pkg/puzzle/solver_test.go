@@ -0,0 +1,157 @@
+package puzzle_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+func TestSolvePuzzleContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate Puzzle / Solve Puzzle", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		mPrime, err := puzzle.SolvePuzzleContext(context.Background(), params, puzzle1, nil)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+	})
+
+	t.Run("Generate Puzzle / Solve Puzzle - Progress Reports", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		var reports []puzzle.Progress
+		opts := &puzzle.SolveOptions{
+			ProgressInterval: 100,
+			OnProgress: func(p puzzle.Progress) {
+				reports = append(reports, p)
+			},
+		}
+
+		mPrime, _ := puzzle.SolvePuzzleContext(context.Background(), params, puzzle1, opts)
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+
+		if len(reports) != 10 {
+			t.Fatalf("want %v progress reports, got %v", 10, len(reports))
+		}
+
+		for i, report := range reports {
+			want := big.NewInt(int64((i + 1) * 100))
+			if report.Squarings.Cmp(want) != 0 {
+				t.Errorf("want %v squarings, got %v", want, report.Squarings)
+			}
+			if report.Total.Cmp(params.T) != 0 {
+				t.Errorf("want %v total, got %v", params.T, report.Total)
+			}
+		}
+
+		last := reports[len(reports)-1]
+		if last.Remaining != 0 {
+			t.Errorf("want remaining time of 0, got %v", last.Remaining)
+		}
+	})
+
+	t.Run("Error when context is canceled", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+		difficulty := new(big.Int).Lsh(big.NewInt(1), 64) // 2^64
+
+		params, _ := params.GenerateParams(128, 2, difficulty)
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := &puzzle.SolveOptions{
+			ProgressInterval: 100,
+			OnProgress: func(p puzzle.Progress) {
+				cancel()
+			},
+		}
+
+		_, err := puzzle.SolvePuzzleContext(ctx, params, puzzle1, opts)
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want error %v, got %v", context.Canceled, err)
+		}
+	})
+
+	t.Run("Error when puzzle is missing", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+
+		_, err := puzzle.SolvePuzzleContext(context.Background(), params, nil, nil)
+
+		if !errors.Is(err, puzzle.ErrMissingPuzzle) {
+			t.Errorf("want error %v, got %v", puzzle.ErrMissingPuzzle, err)
+		}
+	})
+}
+
+func TestRecoverPlaintext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Generate Puzzle / Solve Puzzle / Recover Plaintext", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 3, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		mPrime1, w, _ := puzzle.SolvePuzzleAndReturnW(context.Background(), params, puzzle1, nil)
+		mPrime2, err := puzzle.RecoverPlaintext(params, puzzle1, w)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if mPrime1.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime1)
+		}
+		if mPrime2.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime2)
+		}
+	})
+
+	t.Run("Error when w is negated", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		_, w, _ := puzzle.SolvePuzzleAndReturnW(context.Background(), params, puzzle1, nil)
+		wPrime := new(big.Int).Sub(params.N, w) // -w mod n
+
+		_, err := puzzle.RecoverPlaintext(params, puzzle1, wPrime)
+
+		if !errors.Is(err, puzzle.ErrInvalidW) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidW, err)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package queue
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// jobJSON is the JSON representation of the job.
+type jobJSON struct {
+	Version     int            `json:"version"`
+	ID          string         `json:"id"`
+	Puzzle      *puzzle.Puzzle `json:"puzzle"`
+	State       JobState       `json:"state"`
+	Attempts    int            `json:"attempts"`
+	Checkpoint  string         `json:"checkpoint,omitempty"`
+	Plaintext   string         `json:"plaintext,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	Worker      string         `json:"worker,omitempty"`
+	LeaseExpiry time.Time      `json:"lease_expiry,omitzero"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// MarshalJSON encodes the job into its JSON form.
+// The plaintext is encoded as a canonical hex string and the checkpoint as the
+// hex encoding of its binary form.
+// Returns an error if the puzzle is missing or can't be encoded.
+func (j *Job) MarshalJSON() ([]byte, error) {
+	if j.Puzzle == nil {
+		return nil, ErrEncodeJob
+	}
+
+	v := jobJSON{
+		Version:     int(utils.EncodingVersion),
+		ID:          j.ID,
+		Puzzle:      j.Puzzle,
+		State:       j.State,
+		Attempts:    j.Attempts,
+		Error:       j.Error,
+		Worker:      j.Worker,
+		LeaseExpiry: j.LeaseExpiry,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.UpdatedAt,
+	}
+
+	if j.Checkpoint != nil {
+		checkpoint, err := j.Checkpoint.MarshalBinary()
+		if err != nil {
+			return nil, ErrEncodeJob
+		}
+		v.Checkpoint = hex.EncodeToString(checkpoint)
+	}
+
+	if j.Plaintext != nil {
+		v.Plaintext = utils.BigIntToHex(j.Plaintext)
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes the job from its JSON form.
+// Returns an error if the data isn't a canonical JSON encoding of a job.
+func (j *Job) UnmarshalJSON(data []byte) error {
+	var v jobJSON
+	if err := utils.DecodeJSON(data, &v); err != nil {
+		return ErrDecodeJob
+	}
+
+	if v.Version != int(utils.EncodingVersion) || v.Puzzle == nil || !isValidState(v.State) {
+		return ErrDecodeJob
+	}
+
+	job := Job{
+		ID:          v.ID,
+		Puzzle:      v.Puzzle,
+		State:       v.State,
+		Attempts:    v.Attempts,
+		Error:       v.Error,
+		Worker:      v.Worker,
+		LeaseExpiry: v.LeaseExpiry,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+	}
+
+	if v.Checkpoint != "" {
+		data, err := hex.DecodeString(v.Checkpoint)
+		if err != nil {
+			return ErrDecodeJob
+		}
+
+		var checkpoint puzzle.Checkpoint
+		if err := checkpoint.UnmarshalBinary(data); err != nil {
+			return ErrDecodeJob
+		}
+		job.Checkpoint = &checkpoint
+	}
+
+	if v.Plaintext != "" {
+		plaintext, err := utils.HexToBigInt(v.Plaintext)
+		if err != nil {
+			return ErrDecodeJob
+		}
+		job.Plaintext = plaintext
+	}
+
+	*j = job
+
+	return nil
+}
+
+// isValidState reports whether the state is one of the job states.
+func isValidState(state JobState) bool {
+	switch state {
+	case JobQueued, JobRunning, JobCheckpointed, JobDone, JobFailed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,34 @@
+package queue
+
+import "fmt"
+
+var (
+	// ErrMissingParams is returned if the protocol parameters are missing.
+	ErrMissingParams = fmt.Errorf("missing params")
+	// ErrMissingStorage is returned if the storage is missing.
+	ErrMissingStorage = fmt.Errorf("missing storage")
+	// ErrMissingPuzzle is returned if a puzzle is missing.
+	ErrMissingPuzzle = fmt.Errorf("missing puzzle")
+	// ErrInvalidJobID is returned if a job id is empty, too long or contains characters other than lowercase letters, digits, "-" and "_".
+	ErrInvalidJobID = fmt.Errorf("invalid job id")
+	// ErrInvalidWorkerID is returned if a worker id is empty.
+	ErrInvalidWorkerID = fmt.Errorf("invalid worker id")
+	// ErrSampleJobID is returned if the random job id can't be sampled.
+	ErrSampleJobID = fmt.Errorf("unable to sample random job id")
+	// ErrJobNotFound is returned if a job doesn't exist.
+	ErrJobNotFound = fmt.Errorf("job not found")
+	// ErrJobExists is returned if a job with the same id already exists.
+	ErrJobExists = fmt.Errorf("job already exists")
+	// ErrJobNotDone is returned if the result of a job that isn't done yet is requested.
+	ErrJobNotDone = fmt.Errorf("job not done")
+	// ErrJobFailed is returned if the result of a job that failed is requested.
+	ErrJobFailed = fmt.Errorf("job failed")
+	// ErrLeaseLost is returned if a worker's lease of a job expired and the job was leased by another worker.
+	ErrLeaseLost = fmt.Errorf("lease lost")
+	// ErrLeaseExpired is recorded as the error of a job whose last lease expired while no attempts were left.
+	ErrLeaseExpired = fmt.Errorf("lease expired")
+	// ErrEncodeJob is returned if the job can't be encoded.
+	ErrEncodeJob = fmt.Errorf("unable to encode job")
+	// ErrDecodeJob is returned if the job can't be decoded.
+	ErrDecodeJob = fmt.Errorf("unable to decode job")
+)
@@ -0,0 +1,337 @@
+package queue
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lockFile is the name of the file that guards the modifications of the jobs
+// in a storage directory.
+const lockFile = "lock"
+
+// jobFileExt is the extension of the files that contain the jobs.
+const jobFileExt = ".json"
+
+// lockRetryInterval is the time to wait before trying to acquire a lock that's
+// held by someone else again.
+const lockRetryInterval = 10 * time.Millisecond
+
+// staleLockAge is the age after which a lock is considered to be left behind
+// by a crashed process. Locks are only held for the short time it takes to
+// write a single job.
+// Note: The age is derived from the lock file's modification time (which is
+// set by the file server on a network file system) which is why the clocks of
+// all machines need to roughly agree.
+const staleLockAge = 30 * time.Second
+
+// FileStorage is an embedded storage that persists every job as a JSON file in
+// a directory which can be shared by several machines (e.g. via a network file
+// system).
+//
+// Jobs are written atomically by renaming temporary files. Modifications are
+// serialized via a lock file that's created exclusively and contains a random
+// token of its holder which is why the directory must not be modified by
+// anything else.
+type FileStorage struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStorage creates a new storage that persists the jobs in the directory
+// while creating it if it doesn't exist yet.
+// Returns an error if the directory can't be created.
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileStorage{dir: dir}, nil
+}
+
+// Create persists the new job.
+// Returns an error if the job id is invalid, if a job with the same id already
+// exists or if the job can't be persisted.
+func (s *FileStorage) Create(ctx context.Context, job *Job) error {
+	if !isValidJobID(job.ID) {
+		return ErrInvalidJobID
+	}
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(s.path(job.ID)); err == nil {
+		return ErrJobExists
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return s.write(job)
+}
+
+// Get returns the job with the id.
+// Returns an error if the job doesn't exist or can't be loaded.
+func (s *FileStorage) Get(ctx context.Context, id string) (*Job, error) {
+	if !isValidJobID(id) {
+		return nil, ErrJobNotFound
+	}
+
+	return s.read(id)
+}
+
+// List returns all jobs ordered by the time they were created at.
+// Returns an error if the directory or a job can't be read.
+func (s *FileStorage) List(ctx context.Context) ([]*Job, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*Job
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), jobFileExt)
+		if !ok || !entry.Type().IsRegular() || !isValidJobID(id) {
+			continue
+		}
+
+		job, err := s.read(id)
+		if errors.Is(err, ErrJobNotFound) {
+			// The job was deleted after the directory was read.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	sortJobs(jobs)
+
+	return jobs, nil
+}
+
+// Update atomically applies fn to the job with the id and persists the result.
+// Returns an error if the job doesn't exist, if fn returns an error or if the
+// job can't be loaded or persisted.
+func (s *FileStorage) Update(ctx context.Context, id string, fn func(*Job) error) (*Job, error) {
+	if !isValidJobID(id) {
+		return nil, ErrJobNotFound
+	}
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	job, err := s.read(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fn(job); err != nil {
+		return nil, err
+	}
+	job.ID = id
+
+	if err := s.write(job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Delete removes the job with the id.
+// Returns an error if the job doesn't exist or can't be removed.
+func (s *FileStorage) Delete(ctx context.Context, id string) error {
+	if !isValidJobID(id) {
+		return ErrJobNotFound
+	}
+
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	err = os.Remove(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrJobNotFound
+	}
+
+	return err
+}
+
+// path returns the path of the file that contains the job with the id.
+func (s *FileStorage) path(id string) string {
+	return filepath.Join(s.dir, id+jobFileExt)
+}
+
+// read loads the job with the id.
+// Returns an error if the job doesn't exist or can't be read or decoded.
+func (s *FileStorage) read(id string) (*Job, error) {
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, ErrDecodeJob
+	}
+
+	if job.ID != id {
+		return nil, ErrDecodeJob
+	}
+
+	return &job, nil
+}
+
+// write persists the job by writing it to a temporary file which then replaces
+// the job's file so that readers never see partially written jobs.
+// Returns an error if the job can't be encoded or written.
+func (s *FileStorage) write(job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path(job.ID))
+}
+
+// lock acquires the lock of the storage directory and returns the function
+// that releases it. Locks that are older than staleLockAge are broken.
+// Returns an error if the lock token can't be sampled, if the lock file can't be
+// created or if the context is canceled while waiting for the lock.
+func (s *FileStorage) lock(ctx context.Context) (func(), error) {
+	s.mu.Lock()
+
+	token, err := newLockToken()
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	path := filepath.Join(s.dir, lockFile)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, err := f.WriteString(token)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+
+			if err != nil {
+				os.Remove(path)
+				s.mu.Unlock()
+				return nil, err
+			}
+
+			// The lock is only removed if it's still held (it might have been
+			// broken and acquired by someone else in the meantime).
+			unlock := func() {
+				s.takeLock(token, func(aside string) bool {
+					data, err := os.ReadFile(aside)
+					return err == nil && bytes.Equal(data, []byte(token))
+				})
+				s.mu.Unlock()
+			}
+
+			return unlock, nil
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			s.mu.Unlock()
+			return nil, err
+		}
+
+		if info, err := os.Stat(path); err == nil && isStaleLock(info) {
+			// The lock is only broken if it's still stale (it might have been
+			// released and acquired by someone else in the meantime).
+			s.takeLock(token, func(aside string) bool {
+				info, err := os.Stat(aside)
+				return err == nil && isStaleLock(info)
+			})
+			continue
+		}
+
+		select {
+		case <-time.After(lockRetryInterval):
+		case <-ctx.Done():
+			s.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// takeLock moves the lock file aside (which only succeeds for a single
+// process) and removes it if remove reports true for the moved file. Otherwise
+// the lock file is moved back unless the lock was acquired again in the
+// meantime. The token makes the name of the moved file unique.
+func (s *FileStorage) takeLock(token string, remove func(aside string) bool) {
+	path := filepath.Join(s.dir, lockFile)
+	aside := path + "." + token
+
+	if err := os.Rename(path, aside); err != nil {
+		return
+	}
+
+	if !remove(aside) {
+		// Linking fails if the lock file exists again.
+		os.Link(aside, path)
+	}
+
+	os.Remove(aside)
+}
+
+// isStaleLock reports whether the lock file is older than staleLockAge.
+func isStaleLock(info fs.FileInfo) bool {
+	return time.Since(info.ModTime()) > staleLockAge
+}
+
+// newLockToken returns a random token that identifies the holder of a lock.
+// Returns an error if the token can't be sampled.
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
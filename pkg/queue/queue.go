@@ -0,0 +1,300 @@
+// Package queue implements a persistent job queue for solving puzzles on a
+// farm of workers which may run on several machines that share the storage.
+//
+// Jobs are leased by workers for a limited time and the leases are renewed
+// while the puzzles are being solved. The solving runs are checkpointed
+// regularly so that a job whose worker is shut down or crashes can be resumed
+// by another worker once the lease is released or has expired. Jobs whose
+// attempts fail are retried until the maximum number of attempts is reached.
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// DefaultLeaseDuration is the default duration of a job's lease.
+const DefaultLeaseDuration = time.Minute
+
+// DefaultMaxAttempts is the default maximum number of attempts per job.
+const DefaultMaxAttempts = 3
+
+// DefaultCheckpointInterval is the default number of squarings between two
+// checkpoints.
+const DefaultCheckpointInterval = 1 << 20
+
+// DefaultPollInterval is the default time an idle worker waits before it
+// looks for queued jobs again.
+const DefaultPollInterval = time.Second
+
+// JobState is the state of a job.
+type JobState string
+
+const (
+	// JobQueued is the state of a job that waits for a worker.
+	JobQueued JobState = "queued"
+	// JobRunning is the state of a job whose puzzle is being solved by the
+	// worker that holds its lease.
+	JobRunning JobState = "running"
+	// JobCheckpointed is the state of a job that waits for a worker which
+	// resumes its solving run from the last checkpoint.
+	JobCheckpointed JobState = "checkpointed"
+	// JobDone is the state of a job whose puzzle was solved.
+	JobDone JobState = "done"
+	// JobFailed is the state of a job whose attempts all failed.
+	JobFailed JobState = "failed"
+)
+
+// Job is an instance of a job that solves a puzzle.
+type Job struct {
+	// ID is the unique id of the job.
+	ID string
+	// Puzzle is the puzzle that's solved.
+	Puzzle *puzzle.Puzzle
+	// State is the current state of the job.
+	State JobState
+	// Attempts is the number of attempts that failed.
+	Attempts int
+	// Checkpoint is the last checkpoint of the solving run (if any).
+	Checkpoint *puzzle.Checkpoint
+	// Plaintext is the plaintext that was hidden inside of the puzzle once the
+	// job is done.
+	Plaintext *big.Int
+	// Error is the error of the last failed attempt.
+	Error string
+	// Worker is the id of the worker that holds the lease of a running job.
+	Worker string
+	// LeaseExpiry is the time the lease of a running job expires at.
+	LeaseExpiry time.Time
+	// CreatedAt is the time the job was submitted at.
+	CreatedAt time.Time
+	// UpdatedAt is the time the job was updated at.
+	UpdatedAt time.Time
+}
+
+// leasable reports whether a worker can lease the job at the time now.
+func (j *Job) leasable(now time.Time) bool {
+	switch j.State {
+	case JobQueued, JobCheckpointed:
+		return true
+	case JobRunning:
+		return !now.Before(j.LeaseExpiry)
+	default:
+		return false
+	}
+}
+
+// release clears the job's lease and puts it back into the queue.
+func (j *Job) release() {
+	j.State = JobQueued
+	if j.Checkpoint != nil {
+		j.State = JobCheckpointed
+	}
+	j.Worker = ""
+	j.LeaseExpiry = time.Time{}
+}
+
+// Options configures a queue.
+type Options struct {
+	// LeaseDuration is the duration of a job's lease which is renewed while
+	// the job is running. Defaults to DefaultLeaseDuration if not set.
+	LeaseDuration time.Duration
+	// MaxAttempts is the maximum number of attempts per job. Defaults to
+	// DefaultMaxAttempts if not set.
+	MaxAttempts int
+	// CheckpointInterval is the number of squarings between two checkpoints.
+	// Defaults to DefaultCheckpointInterval if not set.
+	CheckpointInterval uint64
+	// PollInterval is the time an idle worker waits before it looks for queued
+	// jobs again. Defaults to DefaultPollInterval if not set.
+	PollInterval time.Duration
+}
+
+// leaseDuration returns the configured duration of a lease.
+func (o *Options) leaseDuration() time.Duration {
+	if o == nil || o.LeaseDuration <= 0 {
+		return DefaultLeaseDuration
+	}
+
+	return o.LeaseDuration
+}
+
+// maxAttempts returns the configured maximum number of attempts per job.
+func (o *Options) maxAttempts() int {
+	if o == nil || o.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
+	}
+
+	return o.MaxAttempts
+}
+
+// checkpointInterval returns the configured number of squarings between two
+// checkpoints.
+func (o *Options) checkpointInterval() uint64 {
+	if o == nil || o.CheckpointInterval == 0 {
+		return DefaultCheckpointInterval
+	}
+
+	return o.CheckpointInterval
+}
+
+// pollInterval returns the configured time an idle worker waits.
+func (o *Options) pollInterval() time.Duration {
+	if o == nil || o.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+
+	return o.PollInterval
+}
+
+// Queue is a queue of jobs that solve puzzles which were generated with the
+// same protocol parameters.
+type Queue struct {
+	params  *params.Params
+	storage Storage
+	opts    *Options
+}
+
+// NewQueue creates a new queue whose jobs are persisted in the storage. The
+// options can be nil.
+// Returns an error if the protocol parameters are missing or invalid or if
+// the storage is missing.
+func NewQueue(params *params.Params, storage Storage, opts *Options) (*Queue, error) {
+	if params == nil {
+		return nil, ErrMissingParams
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	if storage == nil {
+		return nil, ErrMissingStorage
+	}
+
+	q := &Queue{
+		params:  params,
+		storage: storage,
+		opts:    opts,
+	}
+
+	return q, nil
+}
+
+// Submit adds a job that solves the puzzle to the queue.
+// Returns an error if the puzzle is missing or isn't well-formed with respect
+// to the protocol parameters or if the job can't be persisted.
+func (q *Queue) Submit(ctx context.Context, z *puzzle.Puzzle) (*Job, error) {
+	if z == nil {
+		return nil, ErrMissingPuzzle
+	}
+
+	if err := z.Validate(q.params); err != nil {
+		return nil, err
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	job := &Job{
+		ID:        id,
+		Puzzle:    z,
+		State:     JobQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := q.storage.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Job returns the job with the id.
+// Returns an error if the job doesn't exist or can't be loaded.
+func (q *Queue) Job(ctx context.Context, id string) (*Job, error) {
+	return q.storage.Get(ctx, id)
+}
+
+// Jobs returns all jobs of the queue.
+// Returns an error if the jobs can't be loaded.
+func (q *Queue) Jobs(ctx context.Context) ([]*Job, error) {
+	return q.storage.List(ctx)
+}
+
+// Remove removes the job with the id. A worker that's currently solving the
+// job's puzzle gives up once it tries to renew its lease.
+// Returns an error if the job doesn't exist or can't be removed.
+func (q *Queue) Remove(ctx context.Context, id string) error {
+	return q.storage.Delete(ctx, id)
+}
+
+// Result returns the plaintext that was hidden inside of the puzzle of the job
+// with the id.
+// Returns an error if the job doesn't exist or can't be loaded, if it isn't
+// done yet or if it failed (the job's Error contains the reason).
+func (q *Queue) Result(ctx context.Context, id string) (*big.Int, error) {
+	job, err := q.storage.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return result(job)
+}
+
+// Wait waits until the job with the id is done or failed and returns its
+// result like Result. The job is polled every PollInterval.
+// Returns an error if the job doesn't exist or can't be loaded, if it failed
+// or if the context is canceled before the job is done.
+func (q *Queue) Wait(ctx context.Context, id string) (*big.Int, error) {
+	ticker := time.NewTicker(q.opts.pollInterval())
+	defer ticker.Stop()
+
+	for {
+		plaintext, err := q.Result(ctx, id)
+		if !errors.Is(err, ErrJobNotDone) {
+			return plaintext, err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// result returns the plaintext of the job.
+// Returns an error if the job isn't done yet or if it failed.
+func result(job *Job) (*big.Int, error) {
+	switch job.State {
+	case JobDone:
+		return job.Plaintext, nil
+	case JobFailed:
+		return nil, ErrJobFailed
+	default:
+		return nil, ErrJobNotDone
+	}
+}
+
+// newJobID returns a random job id.
+// Returns an error if the id can't be sampled.
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", ErrSampleJobID
+	}
+
+	return hex.EncodeToString(b), nil
+}
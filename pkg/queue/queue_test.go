@@ -0,0 +1,419 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/queue"
+)
+
+// hookStorage is a storage that calls a hook after every successful update.
+// The hook can replace the update's error.
+type hookStorage struct {
+	queue.Storage
+	onUpdate func(before, after *queue.Job) error
+}
+
+// Update updates the job and calls the hook.
+func (s *hookStorage) Update(ctx context.Context, id string, fn func(*queue.Job) error) (*queue.Job, error) {
+	return s.Storage.Update(ctx, id, func(job *queue.Job) error {
+		before := *job
+		if err := fn(job); err != nil {
+			return err
+		}
+		return s.onUpdate(&before, job)
+	})
+}
+
+func TestQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Submit / Run Worker / Result", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		storage, _ := queue.NewFileStorage(t.TempDir())
+		q, _ := queue.NewQueue(params, storage, &queue.Options{CheckpointInterval: 100})
+
+		job, err := q.Submit(ctx, puzzle1)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if _, err := q.Result(ctx, job.ID); !errors.Is(err, queue.ErrJobNotDone) {
+			t.Errorf("want error %v, got %v", queue.ErrJobNotDone, err)
+		}
+
+		worker, _ := q.NewWorker("worker-1")
+		ok, err := worker.RunOnce(ctx)
+		if !ok || err != nil {
+			t.Fatalf("want a processed job and no error, got %v and %v", ok, err)
+		}
+
+		mPrime, err := q.Result(ctx, job.ID)
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+
+		job, _ = q.Job(ctx, job.ID)
+		if job.State != queue.JobDone || job.Checkpoint != nil || job.Worker != "" {
+			t.Errorf("want state %v without checkpoint and lease, got %v", queue.JobDone, job)
+		}
+
+		ok, err = worker.RunOnce(ctx)
+		if ok || err != nil {
+			t.Errorf("want no processed job and no error, got %v and %v", ok, err)
+		}
+	})
+
+	t.Run("Submit / Wait while Workers Run", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		q, _ := queue.NewQueue(params, queue.NewMemoryStorage(), &queue.Options{PollInterval: time.Millisecond})
+
+		var jobs []*queue.Job
+		for i := range 5 {
+			puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(int64(i)))
+			job, _ := q.Submit(ctx, puzzle1)
+			jobs = append(jobs, job)
+		}
+
+		errs := make(chan error, 2)
+		for i := range 2 {
+			worker, _ := q.NewWorker(fmt.Sprintf("worker-%d", i))
+			go func() {
+				errs <- worker.Run(ctx)
+			}()
+		}
+
+		for i, job := range jobs {
+			mPrime, err := q.Wait(ctx, job.ID)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if mPrime.Cmp(big.NewInt(int64(i))) != 0 {
+				t.Errorf("want %v, got %v", i, mPrime)
+			}
+		}
+
+		cancel()
+		for range 2 {
+			if err := <-errs; !errors.Is(err, context.Canceled) {
+				t.Errorf("want error %v, got %v", context.Canceled, err)
+			}
+		}
+	})
+
+	t.Run("Resume checkpointed Job", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		// Solve the puzzle partially to obtain a checkpoint.
+		solveCtx, cancel := context.WithCancel(ctx)
+		var checkpoint *puzzle.Checkpoint
+		opts := &puzzle.SolveOptions{
+			CheckpointInterval: 300,
+			OnCheckpoint: func(c *puzzle.Checkpoint) error {
+				checkpoint = c
+				cancel()
+				return nil
+			},
+		}
+		_, _ = puzzle.SolvePuzzleContext(solveCtx, params, puzzle1, opts)
+
+		storage := queue.NewMemoryStorage()
+		job := &queue.Job{
+			ID:         "a",
+			Puzzle:     puzzle1,
+			State:      queue.JobCheckpointed,
+			Checkpoint: checkpoint,
+		}
+		_ = storage.Create(ctx, job)
+
+		q, _ := queue.NewQueue(params, storage, nil)
+		worker, _ := q.NewWorker("worker-1")
+		_, _ = worker.RunOnce(ctx)
+
+		mPrime, err := q.Result(ctx, "a")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if mPrime.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, mPrime)
+		}
+	})
+
+	t.Run("Release Job with Checkpoint when Worker is stopped", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		// Stop the worker once the first checkpoint was persisted.
+		storage := &hookStorage{
+			Storage: queue.NewMemoryStorage(),
+			onUpdate: func(before, after *queue.Job) error {
+				if before.Checkpoint == nil && after.Checkpoint != nil {
+					cancel()
+				}
+				return nil
+			},
+		}
+
+		q, _ := queue.NewQueue(params, storage, &queue.Options{CheckpointInterval: 100})
+		job, _ := q.Submit(ctx, puzzle1)
+
+		worker, _ := q.NewWorker("worker-1")
+		_, err := worker.RunOnce(ctx)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("want error %v, got %v", context.Canceled, err)
+		}
+
+		job, _ = q.Job(context.Background(), job.ID)
+		if job.State != queue.JobCheckpointed || job.Worker != "" || job.Attempts != 0 {
+			t.Errorf("want state %v without lease and attempts, got %v", queue.JobCheckpointed, job)
+		}
+
+		if job.Checkpoint.Iteration.Cmp(big.NewInt(100)) != 0 {
+			t.Errorf("want iteration %v, got %v", 100, job.Checkpoint.Iteration)
+		}
+	})
+
+	t.Run("Retry Job whose Lease expired", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		message := big.NewInt(42)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		storage := queue.NewMemoryStorage()
+		job := &queue.Job{
+			ID:          "a",
+			Puzzle:      puzzle1,
+			State:       queue.JobRunning,
+			Worker:      "crashed",
+			LeaseExpiry: time.Now().Add(-time.Second),
+		}
+		_ = storage.Create(ctx, job)
+
+		q, _ := queue.NewQueue(params, storage, nil)
+		worker, _ := q.NewWorker("worker-1")
+		_, _ = worker.RunOnce(ctx)
+
+		job, _ = q.Job(ctx, "a")
+		if job.State != queue.JobDone || job.Attempts != 1 {
+			t.Errorf("want state %v after %v failed attempt, got %v", queue.JobDone, 1, job)
+		}
+
+		if job.Plaintext.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, job.Plaintext)
+		}
+	})
+
+	t.Run("Don't lease Job whose Lease is valid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		storage := queue.NewMemoryStorage()
+		job := &queue.Job{
+			ID:          "a",
+			Puzzle:      puzzle1,
+			State:       queue.JobRunning,
+			Worker:      "worker-2",
+			LeaseExpiry: time.Now().Add(time.Minute),
+		}
+		_ = storage.Create(ctx, job)
+
+		q, _ := queue.NewQueue(params, storage, nil)
+		worker, _ := q.NewWorker("worker-1")
+
+		ok, err := worker.RunOnce(ctx)
+		if ok || err != nil {
+			t.Errorf("want no processed job and no error, got %v and %v", ok, err)
+		}
+	})
+
+	t.Run("Fail Job whose Lease expired without Attempts left", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		storage := queue.NewMemoryStorage()
+		job := &queue.Job{
+			ID:          "a",
+			Puzzle:      puzzle1,
+			State:       queue.JobRunning,
+			Attempts:    queue.DefaultMaxAttempts - 1,
+			Worker:      "crashed",
+			LeaseExpiry: time.Now().Add(-time.Second),
+		}
+		_ = storage.Create(ctx, job)
+
+		q, _ := queue.NewQueue(params, storage, nil)
+		worker, _ := q.NewWorker("worker-1")
+
+		ok, err := worker.RunOnce(ctx)
+		if ok || err != nil {
+			t.Errorf("want no processed job and no error, got %v and %v", ok, err)
+		}
+
+		if _, err := q.Result(ctx, "a"); !errors.Is(err, queue.ErrJobFailed) {
+			t.Errorf("want error %v, got %v", queue.ErrJobFailed, err)
+		}
+
+		job, _ = q.Job(ctx, "a")
+		if job.Error != queue.ErrLeaseExpired.Error() {
+			t.Errorf("want error %v, got %v", queue.ErrLeaseExpired, job.Error)
+		}
+	})
+
+	t.Run("Retry failed Attempts until no Attempts are left", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		errPersist := fmt.Errorf("unable to persist")
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		// Fail every attempt to persist a checkpoint.
+		storage := &hookStorage{
+			Storage: queue.NewMemoryStorage(),
+			onUpdate: func(before, after *queue.Job) error {
+				if after.Checkpoint != before.Checkpoint && after.State == queue.JobRunning {
+					return errPersist
+				}
+				return nil
+			},
+		}
+
+		q, _ := queue.NewQueue(params, storage, &queue.Options{MaxAttempts: 2, CheckpointInterval: 100})
+		job, _ := q.Submit(ctx, puzzle1)
+		worker, _ := q.NewWorker("worker-1")
+
+		_, _ = worker.RunOnce(ctx)
+
+		job, _ = q.Job(ctx, job.ID)
+		if job.State != queue.JobQueued || job.Attempts != 1 || job.Error != errPersist.Error() {
+			t.Errorf("want state %v after %v failed attempt, got %v", queue.JobQueued, 1, job)
+		}
+
+		_, _ = worker.RunOnce(ctx)
+
+		job, _ = q.Job(ctx, job.ID)
+		if job.State != queue.JobFailed || job.Attempts != 2 {
+			t.Errorf("want state %v after %v failed attempts, got %v", queue.JobFailed, 2, job)
+		}
+
+		ok, err := worker.RunOnce(ctx)
+		if ok || err != nil {
+			t.Errorf("want no processed job and no error, got %v and %v", ok, err)
+		}
+	})
+
+	t.Run("Error when params are missing or invalid", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := queue.NewQueue(nil, queue.NewMemoryStorage(), nil); !errors.Is(err, queue.ErrMissingParams) {
+			t.Errorf("want error %v, got %v", queue.ErrMissingParams, err)
+		}
+
+		if _, err := queue.NewQueue(&params.Params{}, queue.NewMemoryStorage(), nil); err == nil {
+			t.Errorf("want error, got %v", err)
+		}
+	})
+
+	t.Run("Error when storage is missing", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+
+		if _, err := queue.NewQueue(params, nil, nil); !errors.Is(err, queue.ErrMissingStorage) {
+			t.Errorf("want error %v, got %v", queue.ErrMissingStorage, err)
+		}
+	})
+
+	t.Run("Error when puzzle is missing or invalid", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		q, _ := queue.NewQueue(params, queue.NewMemoryStorage(), nil)
+
+		if _, err := q.Submit(ctx, nil); !errors.Is(err, queue.ErrMissingPuzzle) {
+			t.Errorf("want error %v, got %v", queue.ErrMissingPuzzle, err)
+		}
+
+		puzzle1 := puzzle.NewPuzzle(big.NewInt(0), big.NewInt(1))
+		if _, err := q.Submit(ctx, puzzle1); !errors.Is(err, puzzle.ErrInvalidU) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidU, err)
+		}
+	})
+
+	t.Run("Error when worker id is empty", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		q, _ := queue.NewQueue(params, queue.NewMemoryStorage(), nil)
+
+		if _, err := q.NewWorker(""); !errors.Is(err, queue.ErrInvalidWorkerID) {
+			t.Errorf("want error %v, got %v", queue.ErrInvalidWorkerID, err)
+		}
+	})
+
+	t.Run("Error when job doesn't exist", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		q, _ := queue.NewQueue(params, queue.NewMemoryStorage(), nil)
+
+		if _, err := q.Result(ctx, "a"); !errors.Is(err, queue.ErrJobNotFound) {
+			t.Errorf("want error %v, got %v", queue.ErrJobNotFound, err)
+		}
+
+		if err := q.Remove(ctx, "a"); !errors.Is(err, queue.ErrJobNotFound) {
+			t.Errorf("want error %v, got %v", queue.ErrJobNotFound, err)
+		}
+	})
+}
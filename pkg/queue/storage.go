@@ -0,0 +1,179 @@
+package queue
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxJobIDLength is the maximum length of a job id.
+const maxJobIDLength = 64
+
+// Storage persists the jobs of a queue. Implementations must be safe for
+// concurrent use (by all workers that share the storage) and must return
+// copies of the jobs so that callers can modify them.
+type Storage interface {
+	// Create persists the new job.
+	// Returns an error if the job id is invalid, if a job with the same id
+	// already exists or if the job can't be persisted.
+	Create(ctx context.Context, job *Job) error
+	// Get returns the job with the id.
+	// Returns an error if the job doesn't exist or can't be loaded.
+	Get(ctx context.Context, id string) (*Job, error)
+	// List returns all jobs ordered by the time they were created at.
+	// Returns an error if the jobs can't be loaded.
+	List(ctx context.Context) ([]*Job, error)
+	// Update atomically applies fn to the job with the id and persists the
+	// result. The job is left unchanged if fn returns an error.
+	// Returns an error if the job doesn't exist, if fn returns an error or if
+	// the job can't be loaded or persisted.
+	Update(ctx context.Context, id string, fn func(*Job) error) (*Job, error)
+	// Delete removes the job with the id.
+	// Returns an error if the job doesn't exist or can't be removed.
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStorage is a storage that keeps the jobs in memory which is useful for
+// tests and for workers that run in a single process.
+type MemoryStorage struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewMemoryStorage creates a new (empty) in-memory storage.
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		jobs: make(map[string]*Job),
+	}
+}
+
+// Create persists the new job.
+// Returns an error if the job id is invalid or if a job with the same id
+// already exists.
+func (s *MemoryStorage) Create(ctx context.Context, job *Job) error {
+	if !isValidJobID(job.ID) {
+		return ErrInvalidJobID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[job.ID]; ok {
+		return ErrJobExists
+	}
+
+	s.jobs[job.ID] = cloneJob(job)
+
+	return nil
+}
+
+// Get returns the job with the id.
+// Returns an error if the job doesn't exist.
+func (s *MemoryStorage) Get(ctx context.Context, id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	return cloneJob(job), nil
+}
+
+// List returns all jobs ordered by the time they were created at.
+func (s *MemoryStorage) List(ctx context.Context) ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, cloneJob(job))
+	}
+
+	sortJobs(jobs)
+
+	return jobs, nil
+}
+
+// Update atomically applies fn to the job with the id.
+// Returns an error if the job doesn't exist or if fn returns an error.
+func (s *MemoryStorage) Update(ctx context.Context, id string, fn func(*Job) error) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	updated := cloneJob(job)
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+	updated.ID = id
+
+	s.jobs[id] = cloneJob(updated)
+
+	return updated, nil
+}
+
+// Delete removes the job with the id.
+// Returns an error if the job doesn't exist.
+func (s *MemoryStorage) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[id]; !ok {
+		return ErrJobNotFound
+	}
+
+	delete(s.jobs, id)
+
+	return nil
+}
+
+// cloneJob returns a copy of the job. The big integers are shared since they
+// are never modified in place.
+func cloneJob(job *Job) *Job {
+	clone := *job
+
+	if job.Checkpoint != nil {
+		checkpoint := *job.Checkpoint
+		checkpoint.Fingerprint = slices.Clone(job.Checkpoint.Fingerprint)
+		clone.Checkpoint = &checkpoint
+	}
+
+	return &clone
+}
+
+// sortJobs sorts the jobs by the time they were created at (and by their ids
+// if they were created at the same time).
+func sortJobs(jobs []*Job) {
+	slices.SortFunc(jobs, func(a, b *Job) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+// isValidJobID reports whether the id is a non-empty string of at most
+// maxJobIDLength lowercase letters, digits, "-" and "_" (which can be used as a
+// file name on all platforms).
+func isValidJobID(id string) bool {
+	if len(id) == 0 || len(id) > maxJobIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		isDigit := c >= '0' && c <= '9'
+		isLetter := c >= 'a' && c <= 'z'
+		if !isDigit && !isLetter && c != '-' && c != '_' {
+			return false
+		}
+	}
+
+	return true
+}
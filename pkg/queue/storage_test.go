@@ -0,0 +1,306 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/queue"
+)
+
+// newJob creates a new queued job with the id that was created at the time.
+func newJob(id string, createdAt time.Time) *queue.Job {
+	return &queue.Job{
+		ID:        id,
+		Puzzle:    puzzle.NewPuzzle(big.NewInt(2), big.NewInt(3)),
+		State:     queue.JobQueued,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func TestStorage(t *testing.T) {
+	t.Parallel()
+
+	storages := map[string]func(t *testing.T) queue.Storage{
+		"Memory": func(t *testing.T) queue.Storage {
+			return queue.NewMemoryStorage()
+		},
+		"File": func(t *testing.T) queue.Storage {
+			storage, err := queue.NewFileStorage(t.TempDir())
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+			return storage
+		},
+	}
+
+	for name, newStorage := range storages {
+		t.Run(name+" / Create / Get / List / Update / Delete", func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			storage := newStorage(t)
+			now := time.Now().UTC()
+
+			_ = storage.Create(ctx, newJob("b", now))
+			_ = storage.Create(ctx, newJob("a", now.Add(time.Second)))
+			_ = storage.Create(ctx, newJob("c", now))
+
+			jobs, err := storage.List(ctx)
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			var ids []string
+			for _, job := range jobs {
+				ids = append(ids, job.ID)
+			}
+
+			if fmt.Sprint(ids) != "[b c a]" {
+				t.Errorf("want %v, got %v", "[b c a]", ids)
+			}
+
+			updated, err := storage.Update(ctx, "a", func(job *queue.Job) error {
+				job.State = queue.JobDone
+				job.Plaintext = big.NewInt(42)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if updated.State != queue.JobDone {
+				t.Errorf("want state %v, got %v", queue.JobDone, updated.State)
+			}
+
+			job, err := storage.Get(ctx, "a")
+			if err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if job.State != queue.JobDone || job.Plaintext.Cmp(big.NewInt(42)) != 0 {
+				t.Errorf("want state %v and plaintext %v, got %v and %v", queue.JobDone, 42, job.State, job.Plaintext)
+			}
+
+			if err := storage.Delete(ctx, "a"); err != nil {
+				t.Fatalf("want no error, got %v", err)
+			}
+
+			if _, err := storage.Get(ctx, "a"); !errors.Is(err, queue.ErrJobNotFound) {
+				t.Errorf("want error %v, got %v", queue.ErrJobNotFound, err)
+			}
+		})
+
+		t.Run(name+" / Update is left unchanged if fn fails", func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			storage := newStorage(t)
+			errUpdate := fmt.Errorf("unable to update")
+
+			_ = storage.Create(ctx, newJob("a", time.Now()))
+
+			_, err := storage.Update(ctx, "a", func(job *queue.Job) error {
+				job.State = queue.JobFailed
+				return errUpdate
+			})
+			if !errors.Is(err, errUpdate) {
+				t.Fatalf("want error %v, got %v", errUpdate, err)
+			}
+
+			job, _ := storage.Get(ctx, "a")
+			if job.State != queue.JobQueued {
+				t.Errorf("want state %v, got %v", queue.JobQueued, job.State)
+			}
+		})
+
+		t.Run(name+" / Returned jobs are copies", func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			storage := newStorage(t)
+
+			job := newJob("a", time.Now())
+			_ = storage.Create(ctx, job)
+			job.State = queue.JobFailed
+
+			loaded, _ := storage.Get(ctx, "a")
+			loaded.State = queue.JobDone
+
+			loaded, _ = storage.Get(ctx, "a")
+			if loaded.State != queue.JobQueued {
+				t.Errorf("want state %v, got %v", queue.JobQueued, loaded.State)
+			}
+		})
+
+		t.Run(name+" / Concurrent updates", func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			storage := newStorage(t)
+
+			_ = storage.Create(ctx, newJob("a", time.Now()))
+
+			var wg sync.WaitGroup
+			for range 20 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, _ = storage.Update(ctx, "a", func(job *queue.Job) error {
+						job.Attempts++
+						return nil
+					})
+				}()
+			}
+			wg.Wait()
+
+			job, _ := storage.Get(ctx, "a")
+			if job.Attempts != 20 {
+				t.Errorf("want %v attempts, got %v", 20, job.Attempts)
+			}
+		})
+
+		t.Run(name+" / Error when job exists", func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			storage := newStorage(t)
+
+			_ = storage.Create(ctx, newJob("a", time.Now()))
+
+			err := storage.Create(ctx, newJob("a", time.Now()))
+			if !errors.Is(err, queue.ErrJobExists) {
+				t.Errorf("want error %v, got %v", queue.ErrJobExists, err)
+			}
+		})
+
+		t.Run(name+" / Error when job doesn't exist", func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			storage := newStorage(t)
+
+			if _, err := storage.Get(ctx, "a"); !errors.Is(err, queue.ErrJobNotFound) {
+				t.Errorf("want error %v, got %v", queue.ErrJobNotFound, err)
+			}
+
+			_, err := storage.Update(ctx, "a", func(job *queue.Job) error { return nil })
+			if !errors.Is(err, queue.ErrJobNotFound) {
+				t.Errorf("want error %v, got %v", queue.ErrJobNotFound, err)
+			}
+
+			if err := storage.Delete(ctx, "a"); !errors.Is(err, queue.ErrJobNotFound) {
+				t.Errorf("want error %v, got %v", queue.ErrJobNotFound, err)
+			}
+		})
+
+		t.Run(name+" / Error when job id is invalid", func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			storage := newStorage(t)
+
+			for _, id := range []string{"", "../a", "A", "a.json"} {
+				err := storage.Create(ctx, newJob(id, time.Now()))
+				if !errors.Is(err, queue.ErrInvalidJobID) {
+					t.Errorf("want error %v, got %v", queue.ErrInvalidJobID, err)
+				}
+			}
+		})
+	}
+
+	t.Run("File / Jobs are persisted", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		dir := t.TempDir()
+		now := time.Now().UTC().Truncate(time.Second)
+
+		job := newJob("a", now)
+		job.State = queue.JobRunning
+		job.Attempts = 1
+		job.Checkpoint = puzzle.NewCheckpoint(big.NewInt(300), big.NewInt(5), []byte{1, 2, 3})
+		job.Error = "lease expired"
+		job.Worker = "worker-1"
+		job.LeaseExpiry = now.Add(time.Minute)
+
+		storage1, _ := queue.NewFileStorage(dir)
+		_ = storage1.Create(ctx, job)
+
+		storage2, _ := queue.NewFileStorage(dir)
+		loaded, err := storage2.Get(ctx, "a")
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if loaded.State != job.State || loaded.Attempts != job.Attempts || loaded.Error != job.Error || loaded.Worker != job.Worker {
+			t.Errorf("want %v, got %v", job, loaded)
+		}
+
+		if !loaded.Puzzle.Equal(job.Puzzle) {
+			t.Errorf("want puzzle %v, got %v", job.Puzzle, loaded.Puzzle)
+		}
+
+		if loaded.Checkpoint.Iteration.Cmp(big.NewInt(300)) != 0 || loaded.Checkpoint.W.Cmp(big.NewInt(5)) != 0 {
+			t.Errorf("want checkpoint %v, got %v", job.Checkpoint, loaded.Checkpoint)
+		}
+
+		if !loaded.LeaseExpiry.Equal(job.LeaseExpiry) || !loaded.CreatedAt.Equal(job.CreatedAt) {
+			t.Errorf("want lease expiry %v and creation time %v, got %v and %v", job.LeaseExpiry, job.CreatedAt, loaded.LeaseExpiry, loaded.CreatedAt)
+		}
+	})
+	t.Run("File / Stale lock is broken", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		lock := filepath.Join(dir, "lock")
+
+		// The lock was left behind by a crashed process an hour ago.
+		_ = os.WriteFile(lock, []byte("crashed"), 0o600)
+		_ = os.Chtimes(lock, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		storage, _ := queue.NewFileStorage(dir)
+		if err := storage.Create(ctx, newJob("a", time.Now())); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		// Only the job remains once the lock was released.
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 || entries[0].Name() != "a.json" {
+			t.Errorf("want %v, got %v", "a.json", entries)
+		}
+	})
+
+	t.Run("File / Lock of someone else is kept", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		lock := filepath.Join(dir, "lock")
+		_ = os.WriteFile(lock, []byte("other"), 0o600)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		storage, _ := queue.NewFileStorage(dir)
+		err := storage.Create(ctx, newJob("a", time.Now()))
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("want error %v, got %v", context.DeadlineExceeded, err)
+		}
+
+		if data, _ := os.ReadFile(lock); string(data) != "other" {
+			t.Errorf("want lock %v, got %v", "other", string(data))
+		}
+	})
+}
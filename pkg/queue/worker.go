@@ -0,0 +1,338 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// errNotLeasable is returned by the update that leases a job if another
+// worker leased the job first.
+var errNotLeasable = fmt.Errorf("job not leasable")
+
+// Worker solves the puzzles of the queue's jobs one after another. Several
+// workers (with different ids) can share the same storage.
+type Worker struct {
+	queue *Queue
+	id    string
+}
+
+// NewWorker creates a new worker with the id which identifies the worker in
+// the leases it holds (e.g. the host name and the process id).
+// Returns an error if the id is empty.
+func (q *Queue) NewWorker(id string) (*Worker, error) {
+	if id == "" {
+		return nil, ErrInvalidWorkerID
+	}
+
+	w := &Worker{
+		queue: q,
+		id:    id,
+	}
+
+	return w, nil
+}
+
+// Run leases and processes jobs until the context is canceled. The job that's
+// being processed when the context is canceled is released so that another
+// worker can resume it from its last checkpoint.
+// Returns an error if the storage fails or the context's error once the
+// context is canceled.
+func (w *Worker) Run(ctx context.Context) error {
+	for {
+		ok, err := w.RunOnce(ctx)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			continue
+		}
+
+		select {
+		case <-time.After(w.queue.opts.pollInterval()):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RunOnce leases the oldest job that's queued, checkpointed or whose lease has
+// expired and processes it. Failed attempts are recorded in the job which is
+// either retried later on or marked as failed once it has no attempts left.
+// Returns false if there's no such job.
+// Returns an error if the storage fails or if the context is canceled.
+func (w *Worker) RunOnce(ctx context.Context) (bool, error) {
+	job, err := w.acquire(ctx)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	return true, w.process(ctx, job)
+}
+
+// acquire leases the oldest leasable job.
+// Returns nil if there's no such job.
+// Returns an error if the storage fails.
+func (w *Worker) acquire(ctx context.Context) (*Job, error) {
+	jobs, err := w.queue.storage.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, candidate := range jobs {
+		if !candidate.leasable(time.Now()) {
+			continue
+		}
+
+		job, err := w.queue.storage.Update(ctx, candidate.ID, w.lease)
+		if errors.Is(err, errNotLeasable) || errors.Is(err, ErrJobNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if job.State == JobRunning && job.Worker == w.id {
+			return job, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// lease leases the job to the worker. A job whose previous lease expired
+// counts as a failed attempt and is marked as failed instead if it has no
+// attempts left.
+// Returns an error if the job isn't leasable (anymore).
+func (w *Worker) lease(job *Job) error {
+	now := time.Now().UTC()
+	if !job.leasable(now) {
+		return errNotLeasable
+	}
+
+	job.UpdatedAt = now
+
+	if job.State == JobRunning {
+		job.Attempts++
+		job.Error = ErrLeaseExpired.Error()
+
+		if job.Attempts >= w.queue.opts.maxAttempts() {
+			job.State = JobFailed
+			job.Worker = ""
+			job.LeaseExpiry = time.Time{}
+			return nil
+		}
+	}
+
+	job.State = JobRunning
+	job.Worker = w.id
+	job.LeaseExpiry = now.Add(w.queue.opts.leaseDuration())
+
+	return nil
+}
+
+// process solves the puzzle of the leased job (while resuming from its last
+// checkpoint if there's one) and records the outcome.
+// Returns an error if the outcome can't be recorded or if the context is
+// canceled.
+func (w *Worker) process(ctx context.Context, job *Job) error {
+	solveCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		w.renewLease(solveCtx, job.ID, cancel)
+	}()
+
+	opts := &puzzle.SolveOptions{
+		CheckpointInterval: w.queue.opts.checkpointInterval(),
+		OnCheckpoint: func(c *puzzle.Checkpoint) error {
+			return w.checkpoint(solveCtx, job.ID, c)
+		},
+	}
+
+	plaintext, err := w.solve(solveCtx, job, opts)
+
+	cancel(nil)
+	<-renewed
+
+	// The job is released or updated even if the context is canceled.
+	uctx := context.WithoutCancel(ctx)
+
+	switch {
+	case err == nil:
+		return w.complete(uctx, job.ID, plaintext)
+	case ctx.Err() != nil:
+		if err := w.release(uctx, job.ID); err != nil {
+			return err
+		}
+		return ctx.Err()
+	case errors.Is(err, ErrLeaseLost) || errors.Is(context.Cause(solveCtx), ErrLeaseLost):
+		// The job was removed or leased by another worker.
+		return nil
+	default:
+		return w.fail(uctx, job.ID, err)
+	}
+}
+
+// solve solves the puzzle of the job while resuming from the job's checkpoint
+// if there's one. Solving starts from scratch if the checkpoint is invalid.
+// Returns an error if the context is canceled or a checkpoint can't be
+// persisted.
+func (w *Worker) solve(ctx context.Context, job *Job, opts *puzzle.SolveOptions) (*big.Int, error) {
+	params := w.queue.params
+
+	if job.Checkpoint != nil {
+		plaintext, err := puzzle.ResumePuzzleContext(ctx, params, job.Puzzle, job.Checkpoint, opts)
+		if !errors.Is(err, puzzle.ErrInvalidCheckpoint) && !errors.Is(err, puzzle.ErrCheckpointMismatch) {
+			return plaintext, err
+		}
+	}
+
+	return puzzle.SolvePuzzleContext(ctx, params, job.Puzzle, opts)
+}
+
+// renewLease renews the lease of the job every third of the lease duration
+// until the context is canceled. The solving run is canceled via cancel if the
+// lease is lost.
+func (w *Worker) renewLease(ctx context.Context, id string, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(w.queue.opts.leaseDuration() / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		_, err := w.queue.storage.Update(ctx, id, func(job *Job) error {
+			if err := w.checkLease(job); err != nil {
+				return err
+			}
+
+			job.LeaseExpiry = time.Now().UTC().Add(w.queue.opts.leaseDuration())
+
+			return nil
+		})
+		if errors.Is(err, ErrLeaseLost) || errors.Is(err, ErrJobNotFound) {
+			cancel(ErrLeaseLost)
+			return
+		}
+	}
+}
+
+// checkpoint persists the checkpoint in the job.
+// Returns an error if the worker doesn't hold the job's lease anymore or if
+// the checkpoint can't be persisted.
+func (w *Worker) checkpoint(ctx context.Context, id string, checkpoint *puzzle.Checkpoint) error {
+	_, err := w.queue.storage.Update(ctx, id, func(job *Job) error {
+		if err := w.checkLease(job); err != nil {
+			return err
+		}
+
+		job.Checkpoint = checkpoint
+		job.UpdatedAt = time.Now().UTC()
+
+		return nil
+	})
+	if errors.Is(err, ErrJobNotFound) {
+		return ErrLeaseLost
+	}
+
+	return err
+}
+
+// complete records the plaintext of the job and marks it as done. The result
+// is recorded even if the lease was lost in the meantime since every solving
+// run produces the same plaintext.
+// Returns an error if the job can't be updated.
+func (w *Worker) complete(ctx context.Context, id string, plaintext *big.Int) error {
+	_, err := w.queue.storage.Update(ctx, id, func(job *Job) error {
+		if job.State == JobDone {
+			return nil
+		}
+
+		job.State = JobDone
+		job.Plaintext = plaintext
+		job.Checkpoint = nil
+		job.Error = ""
+		job.Worker = ""
+		job.LeaseExpiry = time.Time{}
+		job.UpdatedAt = time.Now().UTC()
+
+		return nil
+	})
+	if errors.Is(err, ErrJobNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// release releases the lease of the job so that another worker can resume it.
+// Returns an error if the job can't be updated.
+func (w *Worker) release(ctx context.Context, id string) error {
+	_, err := w.queue.storage.Update(ctx, id, func(job *Job) error {
+		if err := w.checkLease(job); err != nil {
+			return err
+		}
+
+		job.release()
+		job.UpdatedAt = time.Now().UTC()
+
+		return nil
+	})
+	if errors.Is(err, ErrLeaseLost) || errors.Is(err, ErrJobNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// fail records the failed attempt. The job is released for a retry or marked
+// as failed if it has no attempts left.
+// Returns an error if the job can't be updated.
+func (w *Worker) fail(ctx context.Context, id string, cause error) error {
+	_, err := w.queue.storage.Update(ctx, id, func(job *Job) error {
+		if err := w.checkLease(job); err != nil {
+			return err
+		}
+
+		job.Attempts++
+		job.Error = cause.Error()
+		job.UpdatedAt = time.Now().UTC()
+
+		if job.Attempts >= w.queue.opts.maxAttempts() {
+			job.State = JobFailed
+			job.Worker = ""
+			job.LeaseExpiry = time.Time{}
+			return nil
+		}
+
+		job.release()
+
+		return nil
+	})
+	if errors.Is(err, ErrLeaseLost) || errors.Is(err, ErrJobNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+// checkLease checks if the worker still holds the lease of the job.
+// Returns an error if the lease was lost.
+func (w *Worker) checkLease(job *Job) error {
+	if job.State != JobRunning || job.Worker != w.id {
+		return ErrLeaseLost
+	}
+
+	return nil
+}
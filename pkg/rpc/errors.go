@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"fmt"
+)
+
+// Error codes of JSON-RPC 2.0 error objects. The codes from -32768 to -32000
+// are reserved by the specification, the codes from -32099 to -32000 are
+// reserved for implementation-defined server errors.
+const (
+	// CodeParseError is the code of messages that aren't valid JSON.
+	CodeParseError = -32700
+	// CodeInvalidRequest is the code of messages that aren't valid requests.
+	CodeInvalidRequest = -32600
+	// CodeMethodNotFound is the code of requests for unknown methods.
+	CodeMethodNotFound = -32601
+	// CodeInvalidParams is the code of requests with invalid parameters.
+	CodeInvalidParams = -32602
+	// CodeInternalError is the code of internal errors.
+	CodeInternalError = -32603
+	// CodeOperationFailed is the code of requests whose operation failed (e.g.
+	// because a puzzle isn't well-formed with respect to the protocol
+	// parameters).
+	CodeOperationFailed = -32000
+	// CodeRequestCanceled is the code of requests that were canceled.
+	CodeRequestCanceled = -32001
+)
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	// Code is the error code.
+	Code int `json:"code"`
+	// Message is a short description of the error.
+	Message string `json:"message"`
+}
+
+// Error returns the message of the error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// newError creates a new error object with the code and the message of err.
+func newError(code int, err error) *Error {
+	return &Error{
+		Code:    code,
+		Message: err.Error(),
+	}
+}
+
+var (
+	// ErrParse is returned if a message isn't valid JSON.
+	ErrParse = fmt.Errorf("parse error")
+	// ErrInvalidRequest is returned if a message isn't a valid request.
+	ErrInvalidRequest = fmt.Errorf("invalid request")
+	// ErrMessageTooLarge is returned if a message exceeds the maximum size.
+	ErrMessageTooLarge = fmt.Errorf("message too large")
+	// ErrMethodNotFound is returned if a method doesn't exist.
+	ErrMethodNotFound = fmt.Errorf("method not found")
+	// ErrInvalidParams is returned if the parameters of a request can't be decoded.
+	ErrInvalidParams = fmt.Errorf("invalid params")
+	// ErrMissingParams is returned if the protocol parameters are missing.
+	ErrMissingParams = fmt.Errorf("missing protocol params")
+	// ErrMissingPuzzle is returned if a puzzle is missing.
+	ErrMissingPuzzle = fmt.Errorf("missing puzzle")
+	// ErrMissingProof is returned if a proof is missing.
+	ErrMissingProof = fmt.Errorf("missing proof")
+	// ErrInvalidValue is returned if a value isn't a canonical hex string.
+	ErrInvalidValue = fmt.Errorf("invalid value")
+	// ErrValueOutOfRange is returned if a plaintext value isn't an element of {0, ..., n^(y - 1) - 1}.
+	ErrValueOutOfRange = fmt.Errorf("value out of range")
+	// ErrInvalidParamsBits is returned if the bit length of a modulus is out of range.
+	ErrInvalidParamsBits = fmt.Errorf("invalid modulus bit length")
+	// ErrInvalidY is returned if the exponent y is smaller than 2.
+	ErrInvalidY = fmt.Errorf("invalid exponent y")
+	// ErrInvalidDifficulty is returned if the difficulty isn't positive.
+	ErrInvalidDifficulty = fmt.Errorf("invalid difficulty")
+	// ErrInvalidProofBits is returned if the security parameter of a proof is out of range.
+	ErrInvalidProofBits = fmt.Errorf("invalid proof security parameter")
+	// ErrInvalidID is returned if a request id isn't a string, a number or null.
+	ErrInvalidID = fmt.Errorf("invalid request id")
+	// ErrDuplicateID is returned if a request id is already used by a pending request.
+	ErrDuplicateID = fmt.Errorf("duplicate request id")
+	// ErrRequestCanceled is returned if a request was canceled.
+	ErrRequestCanceled = fmt.Errorf("request canceled")
+	// ErrInternal is returned if a method panics.
+	ErrInternal = fmt.Errorf("internal error")
+)
@@ -0,0 +1,522 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/primefactor-io/lhtlp/pkg/homomorphic"
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// MaxProofBits is the maximum security parameter of Range proofs that are
+// verified (which bounds the work of a verification).
+const MaxProofBits = 256
+
+// MaxParamsBits is the maximum bit length of moduli that are generated (which
+// bounds the work of a generation).
+const MaxParamsBits = 8192
+
+// ProgressMethod is the method of the notifications that report the progress
+// of "puzzle.solvePuzzle" requests.
+const ProgressMethod = "puzzle.progress"
+
+// call is an instance of a method call.
+type call struct {
+	conn *conn
+	// id is the id of the request (which is nil for notifications).
+	id     json.RawMessage
+	params json.RawMessage
+}
+
+// method is the implementation of a JSON-RPC method.
+type method func(ctx context.Context, c *call) (any, error)
+
+// methods contains all JSON-RPC methods. Puzzles, proofs and protocol
+// parameters use their JSON form and big integers are canonical hex strings.
+var methods = map[string]method{
+	// {"bits", "y", "t", "safe_primes" (optional)} -> params
+	"params.generateParams": generateParams,
+	// {"params", "value"} -> {"puzzle"}
+	"puzzle.generatePuzzle": generatePuzzle,
+	// {"params", "puzzle", "progress_interval" (optional)} -> {"plaintext"}
+	"puzzle.solvePuzzle": solvePuzzle,
+	// {"params", "puzzles"} -> {"puzzle"}
+	"homomorphic.addPlaintextValues": addPlaintextValues,
+	// {"params", "puzzle", "value"} -> {"puzzle"}
+	"homomorphic.addPlaintextValue": addPlaintextValue,
+	// {"params", "minuend", "subtrahend"} -> {"puzzle"}
+	"homomorphic.subtractPlaintextValues": subtractPlaintextValues,
+	// {"params", "puzzle", "value"} -> {"puzzle"}
+	"homomorphic.subtractPlaintextValue": subtractPlaintextValue,
+	// {"params", "puzzle", "value"} -> {"puzzle"}
+	"homomorphic.multiplyPlaintextValue": multiplyPlaintextValue,
+	// {"params", "puzzle"} -> {"puzzle"}
+	"homomorphic.negate": negate,
+	// {"params", "puzzles", "coefficients"} -> {"puzzle"}
+	"homomorphic.linearCombination": linearCombination,
+	// {"params", "bits", "q", "puzzles", "proof"} -> {"valid"}
+	"proofs.verifyRangeProof": verifyRangeProof,
+	// {"id"} -> {"canceled"}
+	"request.cancel": cancelRequest,
+}
+
+// generateParamsParams are the parameters of "params.generateParams".
+type generateParamsParams struct {
+	Bits       int    `json:"bits"`
+	Y          int    `json:"y"`
+	T          string `json:"t"`
+	SafePrimes bool   `json:"safe_primes"`
+}
+
+// generatePuzzleParams are the parameters of "puzzle.generatePuzzle".
+type generatePuzzleParams struct {
+	Params *params.Params `json:"params"`
+	Value  string         `json:"value"`
+}
+
+// puzzleValueParams are the parameters of methods that take a puzzle and a
+// value.
+type puzzleValueParams struct {
+	Params *params.Params `json:"params"`
+	Puzzle *puzzle.Puzzle `json:"puzzle"`
+	Value  string         `json:"value"`
+}
+
+// puzzleParams are the parameters of methods that take a single puzzle.
+type puzzleParams struct {
+	Params *params.Params `json:"params"`
+	Puzzle *puzzle.Puzzle `json:"puzzle"`
+}
+
+// solvePuzzleParams are the parameters of "puzzle.solvePuzzle".
+type solvePuzzleParams struct {
+	Params           *params.Params `json:"params"`
+	Puzzle           *puzzle.Puzzle `json:"puzzle"`
+	ProgressInterval uint64         `json:"progress_interval,omitempty"`
+}
+
+// puzzlesParams are the parameters of "homomorphic.addPlaintextValues".
+type puzzlesParams struct {
+	Params  *params.Params   `json:"params"`
+	Puzzles []*puzzle.Puzzle `json:"puzzles"`
+}
+
+// subtractPlaintextValuesParams are the parameters of
+// "homomorphic.subtractPlaintextValues".
+type subtractPlaintextValuesParams struct {
+	Params     *params.Params `json:"params"`
+	Minuend    *puzzle.Puzzle `json:"minuend"`
+	Subtrahend *puzzle.Puzzle `json:"subtrahend"`
+}
+
+// linearCombinationParams are the parameters of
+// "homomorphic.linearCombination".
+type linearCombinationParams struct {
+	Params       *params.Params   `json:"params"`
+	Puzzles      []*puzzle.Puzzle `json:"puzzles"`
+	Coefficients []string         `json:"coefficients"`
+}
+
+// verifyRangeProofParams are the parameters of "proofs.verifyRangeProof".
+type verifyRangeProofParams struct {
+	Params  *params.Params     `json:"params"`
+	Bits    int                `json:"bits"`
+	Q       string             `json:"q"`
+	Puzzles []*puzzle.Puzzle   `json:"puzzles"`
+	Proof   *proofs.RangeProof `json:"proof"`
+}
+
+// cancelRequestParams are the parameters of "request.cancel".
+type cancelRequestParams struct {
+	ID json.RawMessage `json:"id"`
+}
+
+// puzzleResult is the result of methods that return a puzzle.
+type puzzleResult struct {
+	Puzzle *puzzle.Puzzle `json:"puzzle"`
+}
+
+// plaintextResult is the result of "puzzle.solvePuzzle".
+type plaintextResult struct {
+	Plaintext string `json:"plaintext"`
+}
+
+// validResult is the result of "proofs.verifyRangeProof".
+type validResult struct {
+	Valid bool `json:"valid"`
+}
+
+// canceledResult is the result of "request.cancel".
+type canceledResult struct {
+	Canceled bool `json:"canceled"`
+}
+
+// progressParams are the parameters of progress notifications.
+type progressParams struct {
+	ID          json.RawMessage `json:"id"`
+	Squarings   string          `json:"squarings"`
+	Total       string          `json:"total"`
+	ElapsedMs   int64           `json:"elapsed_ms"`
+	RemainingMs int64           `json:"remaining_ms"`
+}
+
+// generateParams generates protocol parameters.
+func generateParams(ctx context.Context, c *call) (any, error) {
+	var p generateParamsParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Bits <= 0 || p.Bits > MaxParamsBits {
+		return nil, invalidParams(ErrInvalidParamsBits)
+	}
+
+	if p.Y < 2 {
+		return nil, invalidParams(ErrInvalidY)
+	}
+
+	t, err := parseHex(p.T)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Sign() <= 0 {
+		return nil, invalidParams(ErrInvalidDifficulty)
+	}
+
+	opts := &params.GenerateOptions{SafePrimes: p.SafePrimes}
+
+	return params.GenerateParamsContext(ctx, p.Bits, p.Y, t, opts)
+}
+
+// generatePuzzle generates a puzzle that hides the value.
+func generatePuzzle(ctx context.Context, c *call) (any, error) {
+	var p generatePuzzleParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := checkParams(p.Params); err != nil {
+		return nil, err
+	}
+
+	value, err := parsePlaintext(p.Params, p.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := puzzle.GeneratePuzzle(p.Params, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &puzzleResult{Puzzle: z}, nil
+}
+
+// solvePuzzle solves the puzzle while sending progress notifications (unless
+// the request is a notification).
+func solvePuzzle(ctx context.Context, c *call) (any, error) {
+	var p solvePuzzleParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := checkParams(p.Params); err != nil {
+		return nil, err
+	}
+
+	if err := checkPuzzles(p.Puzzle); err != nil {
+		return nil, err
+	}
+
+	opts := &puzzle.SolveOptions{ProgressInterval: p.ProgressInterval}
+	if c.id != nil {
+		opts.OnProgress = func(progress puzzle.Progress) {
+			c.conn.notify(ProgressMethod, &progressParams{
+				ID:          c.id,
+				Squarings:   utils.BigIntToHex(progress.Squarings),
+				Total:       utils.BigIntToHex(progress.Total),
+				ElapsedMs:   progress.Elapsed.Milliseconds(),
+				RemainingMs: progress.Remaining.Milliseconds(),
+			})
+		}
+	}
+
+	plaintext, err := puzzle.SolvePuzzleContext(ctx, p.Params, p.Puzzle, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plaintextResult{Plaintext: utils.BigIntToHex(plaintext)}, nil
+}
+
+// addPlaintextValues adds the plaintext values of the puzzles.
+func addPlaintextValues(ctx context.Context, c *call) (any, error) {
+	var p puzzlesParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := checkParams(p.Params); err != nil {
+		return nil, err
+	}
+
+	if err := checkPuzzles(p.Puzzles...); err != nil {
+		return nil, err
+	}
+
+	return puzzleOrError(homomorphic.AddPlaintextValues(p.Params, p.Puzzles...))
+}
+
+// addPlaintextValue adds the value to the plaintext value of the puzzle.
+func addPlaintextValue(ctx context.Context, c *call) (any, error) {
+	return withPuzzleAndValue(c, homomorphic.AddPlaintextValue)
+}
+
+// subtractPlaintextValues subtracts the plaintext value of the subtrahend from
+// the plaintext value of the minuend.
+func subtractPlaintextValues(ctx context.Context, c *call) (any, error) {
+	var p subtractPlaintextValuesParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := checkParams(p.Params); err != nil {
+		return nil, err
+	}
+
+	if err := checkPuzzles(p.Minuend, p.Subtrahend); err != nil {
+		return nil, err
+	}
+
+	return puzzleOrError(homomorphic.SubtractPlaintextValues(p.Params, p.Minuend, p.Subtrahend))
+}
+
+// subtractPlaintextValue subtracts the value from the plaintext value of the
+// puzzle.
+func subtractPlaintextValue(ctx context.Context, c *call) (any, error) {
+	return withPuzzleAndValue(c, homomorphic.SubtractPlaintextValue)
+}
+
+// multiplyPlaintextValue multiplies the plaintext value of the puzzle by the
+// value.
+func multiplyPlaintextValue(ctx context.Context, c *call) (any, error) {
+	return withPuzzleAndValue(c, homomorphic.MultiplyPlaintextValue)
+}
+
+// negate negates the plaintext value of the puzzle.
+func negate(ctx context.Context, c *call) (any, error) {
+	var p puzzleParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := checkParams(p.Params); err != nil {
+		return nil, err
+	}
+
+	if err := checkPuzzles(p.Puzzle); err != nil {
+		return nil, err
+	}
+
+	return puzzleOrError(homomorphic.Negate(p.Params, p.Puzzle))
+}
+
+// linearCombination computes the linear combination of the plaintext values of
+// the puzzles with the (possibly negative) coefficients.
+func linearCombination(ctx context.Context, c *call) (any, error) {
+	var p linearCombinationParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := checkParams(p.Params); err != nil {
+		return nil, err
+	}
+
+	if err := checkPuzzles(p.Puzzles...); err != nil {
+		return nil, err
+	}
+
+	coeffs := make([]*big.Int, len(p.Coefficients))
+	for i, s := range p.Coefficients {
+		coeff, err := parseHex(s)
+		if err != nil {
+			return nil, err
+		}
+		coeffs[i] = coeff
+	}
+
+	return puzzleOrError(homomorphic.LinearCombination(p.Params, p.Puzzles, coeffs))
+}
+
+// verifyRangeProof verifies the Range proof for the puzzles.
+func verifyRangeProof(ctx context.Context, c *call) (any, error) {
+	var p verifyRangeProofParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := checkParams(p.Params); err != nil {
+		return nil, err
+	}
+
+	if p.Bits <= 0 || p.Bits > MaxProofBits {
+		return nil, invalidParams(ErrInvalidProofBits)
+	}
+
+	if p.Proof == nil {
+		return nil, invalidParams(ErrMissingProof)
+	}
+
+	if err := checkPuzzles(p.Puzzles...); err != nil {
+		return nil, err
+	}
+
+	q, err := parseHex(p.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := proofs.VerifyRangePoof(p.Proof, p.Bits, p.Params, p.Puzzles, q)
+	if errors.Is(err, proofs.ErrInvalidProof) {
+		return nil, invalidParams(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &validResult{Valid: valid}, nil
+}
+
+// cancelRequest cancels the pending request with the id.
+func cancelRequest(ctx context.Context, c *call) (any, error) {
+	var p cancelRequestParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if p.ID == nil || !isValidID(p.ID) {
+		return nil, invalidParams(ErrInvalidID)
+	}
+
+	return &canceledResult{Canceled: c.conn.cancel(p.ID)}, nil
+}
+
+// withPuzzleAndValue decodes the puzzle and the plaintext value and applies
+// the operation to them.
+func withPuzzleAndValue(c *call, op func(*params.Params, *puzzle.Puzzle, *big.Int) (*puzzle.Puzzle, error)) (any, error) {
+	var p puzzleValueParams
+	if err := decodeParams(c.params, &p); err != nil {
+		return nil, err
+	}
+
+	if err := checkParams(p.Params); err != nil {
+		return nil, err
+	}
+
+	if err := checkPuzzles(p.Puzzle); err != nil {
+		return nil, err
+	}
+
+	value, err := parsePlaintext(p.Params, p.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	return puzzleOrError(op(p.Params, p.Puzzle, value))
+}
+
+// puzzleOrError wraps the puzzle into a result unless the operation failed.
+func puzzleOrError(z *puzzle.Puzzle, err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return &puzzleResult{Puzzle: z}, nil
+}
+
+// decodeParams decodes the parameters (which need to be an object) into v
+// while rejecting unknown fields.
+// Returns an error if the parameters can't be decoded.
+func decodeParams(data json.RawMessage, v any) error {
+	if len(data) == 0 || data[0] != '{' {
+		return invalidParams(fmt.Errorf("%w: params need to be an object", ErrInvalidParams))
+	}
+
+	if err := utils.DecodeJSON(data, v); err != nil {
+		return invalidParams(fmt.Errorf("%w: %v", ErrInvalidParams, err))
+	}
+
+	return nil
+}
+
+// checkParams checks that the protocol parameters are present and valid.
+// Returns an error if they are missing or invalid.
+func checkParams(p *params.Params) error {
+	if p == nil {
+		return invalidParams(ErrMissingParams)
+	}
+
+	if err := p.Validate(); err != nil {
+		return invalidParams(err)
+	}
+
+	return nil
+}
+
+// checkPuzzles checks that there's at least one puzzle and that none of them
+// is missing.
+// Returns an error if a puzzle is missing.
+func checkPuzzles(puzzles ...*puzzle.Puzzle) error {
+	if len(puzzles) == 0 {
+		return invalidParams(ErrMissingPuzzle)
+	}
+
+	for _, z := range puzzles {
+		if z == nil {
+			return invalidParams(ErrMissingPuzzle)
+		}
+	}
+
+	return nil
+}
+
+// parseHex parses a big integer that's encoded as a canonical hex string.
+// Returns an error if the string isn't a canonical hex string.
+func parseHex(s string) (*big.Int, error) {
+	x, err := utils.HexToBigInt(s)
+	if err != nil {
+		return nil, invalidParams(ErrInvalidValue)
+	}
+
+	return x, nil
+}
+
+// parsePlaintext parses a plaintext value that's encoded as a canonical hex
+// string.
+// Returns an error if the string isn't a canonical hex string or if the value
+// isn't an element of {0, ..., n^(y - 1) - 1}.
+func parsePlaintext(p *params.Params, s string) (*big.Int, error) {
+	x, err := parseHex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.Sign() < 0 || x.Cmp(p.NExpYMinusOne) >= 0 {
+		return nil, invalidParams(ErrValueOutOfRange)
+	}
+
+	return x, nil
+}
+
+// invalidParams wraps the error into an invalid params error object.
+func invalidParams(err error) *Error {
+	return newError(CodeInvalidParams, err)
+}
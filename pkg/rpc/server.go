@@ -0,0 +1,372 @@
+// Package rpc exposes the scheme via JSON-RPC 2.0 (see
+// https://www.jsonrpc.org/specification) so that it can be driven as a
+// subprocess over stdin and stdout or as a local daemon over TCP.
+//
+// Messages are framed as single lines of JSON that are separated by newlines.
+// Requests (including batches) are processed concurrently, which is why
+// responses can arrive out of order and long-running requests can be canceled
+// via the "request.cancel" method.
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+)
+
+// Version is the JSON-RPC version.
+const Version = "2.0"
+
+// DefaultMaxMessageSize is the default maximum size (in bytes) of a message.
+const DefaultMaxMessageSize = 16 * 1024 * 1024
+
+// Options configures a server.
+type Options struct {
+	// MaxMessageSize is the maximum size (in bytes) of a message. Defaults to
+	// DefaultMaxMessageSize if not set.
+	MaxMessageSize int
+}
+
+// maxMessageSize returns the configured maximum size of a message.
+func (o *Options) maxMessageSize() int {
+	if o == nil || o.MaxMessageSize <= 0 {
+		return DefaultMaxMessageSize
+	}
+
+	return o.MaxMessageSize
+}
+
+// request is a JSON-RPC 2.0 request or notification.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	// ID is nil for notifications.
+	ID json.RawMessage `json:"id,omitempty"`
+}
+
+// response is a JSON-RPC 2.0 response.
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+// notification is a JSON-RPC 2.0 notification that's sent by the server.
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// null is the JSON encoding of null.
+var null = json.RawMessage("null")
+
+// Server serves the JSON-RPC methods.
+type Server struct {
+	opts *Options
+}
+
+// NewServer creates a new server. The options can be nil.
+func NewServer(opts *Options) *Server {
+	return &Server{
+		opts: opts,
+	}
+}
+
+// Serve accepts connections on the listener and serves each of them via
+// ServeConn until the context is canceled, which closes the listener and all
+// connections.
+// Returns an error if a connection can't be accepted or the context's error
+// once the context is canceled.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	stop := context.AfterFunc(ctx, func() {
+		ln.Close()
+	})
+	defer stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		c, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer c.Close()
+
+			stop := context.AfterFunc(ctx, func() {
+				c.Close()
+			})
+			defer stop()
+
+			s.ServeConn(ctx, c, c)
+		}()
+	}
+}
+
+// ServeConn reads messages from r and writes responses and notifications to w
+// until r is exhausted. Pending requests are answered before ServeConn
+// returns. Canceling the context cancels all pending requests and makes
+// ServeConn return without waiting for r (which the caller should close).
+// Returns an error if a message can't be read (e.g. because it's too large) or
+// the context's error once the context is canceled.
+func (s *Server) ServeConn(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c := &conn{
+		server:  s,
+		w:       w,
+		pending: make(map[string]context.CancelFunc),
+	}
+
+	messages := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		maxSize := s.opts.maxMessageSize()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, min(maxSize, 64*1024)), maxSize)
+
+		for scanner.Scan() {
+			message := bytes.TrimSpace(scanner.Bytes())
+			if len(message) == 0 {
+				continue
+			}
+
+			select {
+			case messages <- bytes.Clone(message):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		readErr <- scanner.Err()
+	}()
+
+	var wg sync.WaitGroup
+	var err error
+loop:
+	for {
+		select {
+		case message := <-messages:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c.handleMessage(ctx, message)
+			}()
+		case err = <-readErr:
+			if errors.Is(err, bufio.ErrTooLong) {
+				err = ErrMessageTooLarge
+				c.write(&response{JSONRPC: Version, Error: newError(CodeInvalidRequest, err), ID: null})
+			}
+			break loop
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		}
+	}
+
+	wg.Wait()
+
+	return err
+}
+
+// conn is the state of a connection.
+type conn struct {
+	server *Server
+
+	mu sync.Mutex
+	w  io.Writer
+
+	pendingMu sync.Mutex
+	pending   map[string]context.CancelFunc
+}
+
+// write writes the JSON encoding of v as a single line.
+func (c *conn) write(v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.w.Write(append(data, '\n'))
+}
+
+// notify sends a notification to the client.
+func (c *conn) notify(method string, params any) {
+	c.write(&notification{
+		JSONRPC: Version,
+		Method:  method,
+		Params:  params,
+	})
+}
+
+// handleMessage handles a single request or a batch of requests.
+func (c *conn) handleMessage(ctx context.Context, message []byte) {
+	if !json.Valid(message) {
+		c.write(&response{JSONRPC: Version, Error: newError(CodeParseError, ErrParse), ID: null})
+		return
+	}
+
+	if message[0] != '[' {
+		if res := c.handleRequest(ctx, message); res != nil {
+			c.write(res)
+		}
+		return
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(message, &batch); err != nil || len(batch) == 0 {
+		c.write(&response{JSONRPC: Version, Error: newError(CodeInvalidRequest, ErrInvalidRequest), ID: null})
+		return
+	}
+
+	responses := make([]*response, len(batch))
+	var wg sync.WaitGroup
+	for i, message := range batch {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i] = c.handleRequest(ctx, message)
+		}()
+	}
+	wg.Wait()
+
+	// Notifications aren't answered which is why nothing is written if the
+	// batch only consists of notifications.
+	var answered []*response
+	for _, res := range responses {
+		if res != nil {
+			answered = append(answered, res)
+		}
+	}
+
+	if len(answered) != 0 {
+		c.write(answered)
+	}
+}
+
+// handleRequest handles a single request and returns its response (which is
+// nil for notifications).
+func (c *conn) handleRequest(ctx context.Context, message []byte) *response {
+	var req request
+	if err := json.Unmarshal(message, &req); err != nil || req.JSONRPC != Version || req.Method == "" {
+		return &response{JSONRPC: Version, Error: newError(CodeInvalidRequest, ErrInvalidRequest), ID: null}
+	}
+
+	isNotification := req.ID == nil
+	if !isNotification && !isValidID(req.ID) {
+		return &response{JSONRPC: Version, Error: newError(CodeInvalidRequest, ErrInvalidID), ID: null}
+	}
+
+	m, ok := methods[req.Method]
+	if !ok {
+		if isNotification {
+			return nil
+		}
+		return &response{JSONRPC: Version, Error: newError(CodeMethodNotFound, ErrMethodNotFound), ID: req.ID}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if !isNotification {
+		// A pending id is rejected as its request couldn't be canceled anymore.
+		key := string(req.ID)
+		c.pendingMu.Lock()
+		if _, ok := c.pending[key]; ok {
+			c.pendingMu.Unlock()
+			return &response{JSONRPC: Version, Error: newError(CodeInvalidRequest, ErrDuplicateID), ID: req.ID}
+		}
+		c.pending[key] = cancel
+		c.pendingMu.Unlock()
+
+		defer func() {
+			c.pendingMu.Lock()
+			delete(c.pending, key)
+			c.pendingMu.Unlock()
+		}()
+	}
+
+	result, err := callMethod(ctx, m, &call{conn: c, id: req.ID, params: req.Params})
+	if isNotification {
+		return nil
+	}
+
+	if err != nil {
+		return &response{JSONRPC: Version, Error: toError(ctx, err), ID: req.ID}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &response{JSONRPC: Version, Error: newError(CodeInternalError, err), ID: req.ID}
+	}
+
+	return &response{JSONRPC: Version, Result: data, ID: req.ID}
+}
+
+// callMethod calls the method and converts a panic into an internal error so
+// that a single request can't crash the process.
+func callMethod(ctx context.Context, m method, c *call) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, newError(CodeInternalError, ErrInternal)
+		}
+	}()
+
+	return m(ctx, c)
+}
+
+// cancel cancels the pending request with the id.
+// Returns false if no request with the id is pending.
+func (c *conn) cancel(id json.RawMessage) bool {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	cancel, ok := c.pending[string(id)]
+	if ok {
+		cancel()
+	}
+
+	return ok
+}
+
+// toError converts the error of a method into an error object.
+func toError(ctx context.Context, err error) *Error {
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		return e
+	case ctx.Err() != nil && errors.Is(err, ctx.Err()):
+		return newError(CodeRequestCanceled, ErrRequestCanceled)
+	default:
+		return newError(CodeOperationFailed, err)
+	}
+}
+
+// isValidID reports whether the request id is a string, a number or null.
+func isValidID(id json.RawMessage) bool {
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,505 @@
+package rpc_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/rpc"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// message is a JSON-RPC 2.0 message that's received by the client.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Result  json.RawMessage `json:"result"`
+	Error   *rpc.Error      `json:"error"`
+}
+
+// client drives a server through in-memory pipes.
+type client struct {
+	t    *testing.T
+	w    *io.PipeWriter
+	r    *bufio.Scanner
+	done chan error
+}
+
+// newClient starts a server that's connected to the client via pipes.
+func newClient(t *testing.T, ctx context.Context, opts *rpc.Options) *client {
+	t.Helper()
+
+	serverR, clientW := io.Pipe()
+	clientR, serverW := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- rpc.NewServer(opts).ServeConn(ctx, serverR, serverW)
+		serverW.Close()
+	}()
+
+	c := &client{
+		t:    t,
+		w:    clientW,
+		r:    bufio.NewScanner(clientR),
+		done: done,
+	}
+	c.r.Buffer(nil, rpc.DefaultMaxMessageSize)
+
+	t.Cleanup(func() {
+		clientW.Close()
+		clientR.Close()
+	})
+
+	return c
+}
+
+// send sends the raw message.
+func (c *client) send(s string) {
+	c.t.Helper()
+
+	if _, err := io.WriteString(c.w, s+"\n"); err != nil {
+		c.t.Fatalf("want no error, got %v", err)
+	}
+}
+
+// request sends a request with the id, the method and the params.
+func (c *client) request(id int, method string, params any) {
+	c.t.Helper()
+
+	data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+	c.send(string(data))
+}
+
+// receive receives the next raw message.
+func (c *client) receive() []byte {
+	c.t.Helper()
+
+	if !c.r.Scan() {
+		c.t.Fatalf("want message, got %v", c.r.Err())
+	}
+
+	return c.r.Bytes()
+}
+
+// next receives the next message.
+func (c *client) next() *message {
+	c.t.Helper()
+
+	var m message
+	if err := json.Unmarshal(c.receive(), &m); err != nil {
+		c.t.Fatalf("want no error, got %v", err)
+	}
+
+	return &m
+}
+
+// call sends a request and decodes the result of its response into res.
+func (c *client) call(method string, params, res any) {
+	c.t.Helper()
+
+	c.request(1, method, params)
+
+	m := c.next()
+	if m.Error != nil {
+		c.t.Fatalf("want no error, got %v", m.Error)
+	}
+
+	if err := json.Unmarshal(m.Result, res); err != nil {
+		c.t.Fatalf("want no error, got %v", err)
+	}
+}
+
+// puzzleResult is the result of methods that return a puzzle.
+type puzzleResult struct {
+	Puzzle *puzzle.Puzzle `json:"puzzle"`
+}
+
+// hex returns the canonical hex encoding of x.
+func hex(x int64) string {
+	return utils.BigIntToHex(big.NewInt(x))
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	params1, _ := params.GenerateParams(256, 2, big.NewInt(1_000))
+
+	t.Run("Generate Params", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t, context.Background(), nil)
+
+		var p params.Params
+		c.call("params.generateParams", map[string]any{"bits": 256, "y": 2, "t": hex(1_000)}, &p)
+
+		if err := p.Validate(); err != nil || p.T.Cmp(big.NewInt(1_000)) != 0 {
+			t.Errorf("want valid params with t = %v, got %v (%v)", 1_000, p.T, err)
+		}
+	})
+
+	t.Run("Generate Params With Safe Primes", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t, context.Background(), nil)
+
+		var p params.Params
+		c.call("params.generateParams", map[string]any{"bits": 128, "y": 2, "t": hex(1_000), "safe_primes": true}, &p)
+
+		if p.ModulusType != params.ModulusSafePrimes {
+			t.Errorf("want modulus type %v, got %v", params.ModulusSafePrimes, p.ModulusType)
+		}
+	})
+
+	t.Run("Generate / Combine / Solve", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t, context.Background(), nil)
+
+		var z1, z2, z3 puzzleResult
+		c.call("puzzle.generatePuzzle", map[string]any{"params": params1, "value": hex(5)}, &z1)
+		c.call("puzzle.generatePuzzle", map[string]any{"params": params1, "value": hex(7)}, &z2)
+		c.call("puzzle.generatePuzzle", map[string]any{"params": params1, "value": hex(2)}, &z3)
+
+		// 2 * (5 + 7 + 3) - 2 = 28 and -(28) + 3 * 28 - 2 * 7 = 42.
+		var sum, withValue, product, difference, negated, combination puzzleResult
+		c.call("homomorphic.addPlaintextValues", map[string]any{"params": params1, "puzzles": []*puzzle.Puzzle{z1.Puzzle, z2.Puzzle}}, &sum)
+		c.call("homomorphic.addPlaintextValue", map[string]any{"params": params1, "puzzle": sum.Puzzle, "value": hex(3)}, &withValue)
+		c.call("homomorphic.multiplyPlaintextValue", map[string]any{"params": params1, "puzzle": withValue.Puzzle, "value": hex(2)}, &product)
+		c.call("homomorphic.subtractPlaintextValues", map[string]any{"params": params1, "minuend": product.Puzzle, "subtrahend": z3.Puzzle}, &difference)
+		c.call("homomorphic.negate", map[string]any{"params": params1, "puzzle": difference.Puzzle}, &negated)
+		c.call("homomorphic.linearCombination", map[string]any{
+			"params":       params1,
+			"puzzles":      []*puzzle.Puzzle{negated.Puzzle, difference.Puzzle, z2.Puzzle},
+			"coefficients": []string{hex(1), hex(3), hex(-2)},
+		}, &combination)
+
+		var subtracted puzzleResult
+		c.call("homomorphic.subtractPlaintextValue", map[string]any{"params": params1, "puzzle": combination.Puzzle, "value": hex(2)}, &subtracted)
+
+		var res struct {
+			Plaintext string `json:"plaintext"`
+		}
+		c.call("puzzle.solvePuzzle", map[string]any{"params": params1, "puzzle": subtracted.Puzzle}, &res)
+
+		if res.Plaintext != hex(40) {
+			t.Errorf("want %v, got %v", hex(40), res.Plaintext)
+		}
+	})
+
+	t.Run("Solve With Progress", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t, context.Background(), nil)
+
+		z, _ := puzzle.GeneratePuzzle(params1, big.NewInt(42))
+		c.request(7, "puzzle.solvePuzzle", map[string]any{"params": params1, "puzzle": z, "progress_interval": 100})
+
+		notifications := 0
+		for {
+			m := c.next()
+			if m.Method == "" {
+				if string(m.ID) != "7" || m.Error != nil || !strings.Contains(string(m.Result), hex(42)) {
+					t.Errorf("want result %v for id %v, got %s", hex(42), 7, m.Result)
+				}
+				break
+			}
+
+			if m.Method != rpc.ProgressMethod || !strings.Contains(string(m.Params), `"id":7`) {
+				t.Errorf("want progress notification for id %v, got %v %s", 7, m.Method, m.Params)
+			}
+			notifications++
+		}
+
+		if notifications != 10 {
+			t.Errorf("want %v notifications, got %v", 10, notifications)
+		}
+	})
+
+	t.Run("Verify Range Proof", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t, context.Background(), nil)
+
+		bits := 128
+		q := big.NewInt(100)
+		x := big.NewInt(42)
+		z, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params1, x)
+		wit := []*proofs.PuzzleValues{proofs.NewPuzzleValues(x, r)}
+		proof, _ := proofs.GenerateRangeProof(bits, params1, []*puzzle.Puzzle{z}, q, wit)
+		other, _ := puzzle.GeneratePuzzle(params1, x)
+
+		for _, tc := range []struct {
+			puzzle *puzzle.Puzzle
+			valid  bool
+		}{{z, true}, {other, false}} {
+			var res struct {
+				Valid bool `json:"valid"`
+			}
+			c.call("proofs.verifyRangeProof", map[string]any{"params": params1, "bits": bits, "q": utils.BigIntToHex(q), "puzzles": []*puzzle.Puzzle{tc.puzzle}, "proof": proof}, &res)
+
+			if res.Valid != tc.valid {
+				t.Errorf("want %v, got %v", tc.valid, res.Valid)
+			}
+		}
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t, context.Background(), nil)
+
+		c.send(fmt.Sprintf(`[%s,%s,%s,%s]`,
+			`{"jsonrpc":"2.0","id":"a","method":"puzzle.generatePuzzle","params":{"params":`+mustJSON(params1)+`,"value":"1"}}`,
+			`{"jsonrpc":"2.0","method":"puzzle.generatePuzzle","params":{"params":`+mustJSON(params1)+`,"value":"1"}}`,
+			`{"jsonrpc":"2.0","id":"b","method":"foo"}`,
+			`1`,
+		))
+
+		var responses []*message
+		if err := json.Unmarshal(c.receive(), &responses); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		if len(responses) != 3 {
+			t.Fatalf("want %v responses, got %v", 3, len(responses))
+		}
+
+		if string(responses[0].ID) != `"a"` || responses[0].Error != nil {
+			t.Errorf("want result for id %v, got %v", "a", responses[0].Error)
+		}
+
+		if string(responses[1].ID) != `"b"` || responses[1].Error.Code != rpc.CodeMethodNotFound {
+			t.Errorf("want error %v for id %v, got %v", rpc.CodeMethodNotFound, "b", responses[1].Error)
+		}
+
+		if string(responses[2].ID) != "null" || responses[2].Error.Code != rpc.CodeInvalidRequest {
+			t.Errorf("want error %v, got %v", rpc.CodeInvalidRequest, responses[2].Error)
+		}
+	})
+
+	t.Run("Notifications", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t, context.Background(), nil)
+
+		// Notifications aren't answered, even if they fail.
+		c.send(`{"jsonrpc":"2.0","method":"foo"}`)
+		c.send(`[{"jsonrpc":"2.0","method":"homomorphic.negate","params":{}}]`)
+		c.request(1, "request.cancel", map[string]any{"id": 2})
+
+		m := c.next()
+		if string(m.ID) != "1" || string(m.Result) != `{"canceled":false}` {
+			t.Errorf("want result for id %v, got %s (%v)", 1, m.Result, m.Error)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		params2, _ := params.GenerateParams(256, 2, new(big.Int).Lsh(big.NewInt(1), 40))
+		z, _ := puzzle.GeneratePuzzle(params2, big.NewInt(42))
+
+		c := newClient(t, context.Background(), nil)
+		c.request(1, "puzzle.solvePuzzle", map[string]any{"params": params2, "puzzle": z, "progress_interval": 1_000})
+
+		// The first progress notification shows that the solve is running.
+		if m := c.next(); m.Method != rpc.ProgressMethod {
+			t.Fatalf("want progress notification, got %v", m)
+		}
+
+		c.request(2, "request.cancel", map[string]any{"id": 1})
+
+		var canceled, solved *message
+		for canceled == nil || solved == nil {
+			switch m := c.next(); string(m.ID) {
+			case "1":
+				solved = m
+			case "2":
+				canceled = m
+			}
+		}
+
+		if string(canceled.Result) != `{"canceled":true}` {
+			t.Errorf("want %v, got %s", `{"canceled":true}`, canceled.Result)
+		}
+
+		if solved.Error == nil || solved.Error.Code != rpc.CodeRequestCanceled {
+			t.Errorf("want error %v, got %v", rpc.CodeRequestCanceled, solved.Error)
+		}
+	})
+
+	t.Run("Error when request id is pending", func(t *testing.T) {
+		t.Parallel()
+
+		params2, _ := params.GenerateParams(256, 2, new(big.Int).Lsh(big.NewInt(1), 40))
+		z, _ := puzzle.GeneratePuzzle(params2, big.NewInt(42))
+
+		c := newClient(t, context.Background(), nil)
+		c.request(1, "puzzle.solvePuzzle", map[string]any{"params": params2, "puzzle": z, "progress_interval": 1_000})
+
+		// The first progress notification shows that the solve is pending.
+		if m := c.next(); m.Method != rpc.ProgressMethod {
+			t.Fatalf("want progress notification, got %v", m)
+		}
+
+		c.request(1, "request.cancel", map[string]any{"id": 2})
+
+		// Progress notifications of the pending request are skipped.
+		m := c.next()
+		for m.Method == rpc.ProgressMethod {
+			m = c.next()
+		}
+
+		if string(m.ID) != "1" || m.Error == nil || m.Error.Code != rpc.CodeInvalidRequest {
+			t.Errorf("want error %v, got %v", rpc.CodeInvalidRequest, m.Error)
+		}
+
+		// The pending request can still be canceled.
+		c.request(2, "request.cancel", map[string]any{"id": 1})
+
+		for canceled := false; !canceled; {
+			m := c.next()
+			canceled = string(m.ID) == "2" && string(m.Result) == `{"canceled":true}`
+		}
+	})
+
+	t.Run("Context Canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c := newClient(t, ctx, nil)
+		cancel()
+
+		select {
+		case err := <-c.done:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("want error %v, got %v", context.Canceled, err)
+			}
+		case <-time.After(10 * time.Second):
+			t.Error("want server to stop")
+		}
+	})
+
+	t.Run("Error when message is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		z, _ := puzzle.GeneratePuzzle(params1, big.NewInt(1))
+
+		// The proof only covers 8 of the 128 bits.
+		x := big.NewInt(1)
+		zx, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params1, x)
+		truncated, _ := proofs.GenerateRangeProof(8, params1, []*puzzle.Puzzle{zx}, big.NewInt(100), []*proofs.PuzzleValues{proofs.NewPuzzleValues(x, r)})
+
+		tests := []struct {
+			name    string
+			message string
+			code    int
+		}{
+			{"Malformed JSON", `{"jsonrpc":"2.0",`, rpc.CodeParseError},
+			{"Empty Batch", `[]`, rpc.CodeInvalidRequest},
+			{"Wrong Version", `{"jsonrpc":"1.0","id":1,"method":"homomorphic.negate"}`, rpc.CodeInvalidRequest},
+			{"Missing Method", `{"jsonrpc":"2.0","id":1}`, rpc.CodeInvalidRequest},
+			{"Object ID", `{"jsonrpc":"2.0","id":{},"method":"homomorphic.negate"}`, rpc.CodeInvalidRequest},
+			{"Unknown Method", `{"jsonrpc":"2.0","id":1,"method":"foo"}`, rpc.CodeMethodNotFound},
+			{"Positional Params", `{"jsonrpc":"2.0","id":1,"method":"homomorphic.negate","params":[]}`, rpc.CodeInvalidParams},
+			{"Unknown Param", `{"jsonrpc":"2.0","id":1,"method":"homomorphic.negate","params":{"foo":1}}`, rpc.CodeInvalidParams},
+			{"Missing Params", `{"jsonrpc":"2.0","id":1,"method":"homomorphic.negate","params":{"puzzle":` + mustJSON(z) + `}}`, rpc.CodeInvalidParams},
+			{"Missing Puzzle", `{"jsonrpc":"2.0","id":1,"method":"homomorphic.negate","params":{"params":` + mustJSON(params1) + `}}`, rpc.CodeInvalidParams},
+			{"Value Out Of Range", `{"jsonrpc":"2.0","id":1,"method":"puzzle.generatePuzzle","params":{"params":` + mustJSON(params1) + `,"value":"-1"}}`, rpc.CodeInvalidParams},
+			{"Negative Difficulty", `{"jsonrpc":"2.0","id":1,"method":"params.generateParams","params":{"bits":64,"y":2,"t":"-1"}}`, rpc.CodeInvalidParams},
+			{"Zero Difficulty", `{"jsonrpc":"2.0","id":1,"method":"params.generateParams","params":{"bits":64,"y":2,"t":"0"}}`, rpc.CodeInvalidParams},
+			{"Exponent y < 2", `{"jsonrpc":"2.0","id":1,"method":"params.generateParams","params":{"bits":64,"y":1,"t":"1"}}`, rpc.CodeInvalidParams},
+			{"Invalid Params Bits", `{"jsonrpc":"2.0","id":1,"method":"params.generateParams","params":{"bits":1000000,"y":2,"t":"1"}}`, rpc.CodeInvalidParams},
+			{"Truncated Proof", `{"jsonrpc":"2.0","id":1,"method":"proofs.verifyRangeProof","params":{"params":` + mustJSON(params1) + `,"bits":128,"q":"64","puzzles":[` + mustJSON(zx) + `],"proof":` + mustJSON(truncated) + `}}`, rpc.CodeInvalidParams},
+			{"Invalid Proof Bits", `{"jsonrpc":"2.0","id":1,"method":"proofs.verifyRangeProof","params":{"params":` + mustJSON(params1) + `,"bits":0}}`, rpc.CodeInvalidParams},
+			{"Invalid Puzzle", `{"jsonrpc":"2.0","id":1,"method":"homomorphic.negate","params":{"params":` + mustJSON(params1) + `,"puzzle":{"version":1,"u":"0","v":"0"}}}`, rpc.CodeOperationFailed},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				c := newClient(t, context.Background(), nil)
+				c.send(tc.message)
+
+				m := c.next()
+				if m.Error == nil || m.Error.Code != tc.code {
+					t.Errorf("want error %v, got %v", tc.code, m.Error)
+				}
+			})
+		}
+	})
+
+	t.Run("Error when message is too large", func(t *testing.T) {
+		t.Parallel()
+
+		c := newClient(t, context.Background(), &rpc.Options{MaxMessageSize: 64})
+		// The message is sent in the background as the server stops reading once
+		// the message exceeds the maximum size.
+		go io.WriteString(c.w, `{"jsonrpc":"2.0","id":1,"method":"`+strings.Repeat("a", 100)+`"}`+"\n")
+
+		m := c.next()
+		if m.Error == nil || m.Error.Code != rpc.CodeInvalidRequest {
+			t.Errorf("want error %v, got %v", rpc.CodeInvalidRequest, m.Error)
+		}
+
+		if err := <-c.done; !errors.Is(err, rpc.ErrMessageTooLarge) {
+			t.Errorf("want error %v, got %v", rpc.ErrMessageTooLarge, err)
+		}
+	})
+}
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("unable to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- rpc.NewServer(nil).Serve(ctx, ln)
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	defer conn.Close()
+
+	io.WriteString(conn, `{"jsonrpc":"2.0","id":1,"method":"request.cancel","params":{"id":2}}`+"\n")
+
+	r := bufio.NewScanner(conn)
+	if !r.Scan() || !strings.Contains(r.Text(), `"result":{"canceled":false}`) {
+		t.Errorf("want result, got %v", r.Text())
+	}
+
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("want error %v, got %v", context.Canceled, err)
+	}
+}
+
+// mustJSON returns the JSON encoding of v.
+func mustJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
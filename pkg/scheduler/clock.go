@@ -0,0 +1,26 @@
+package scheduler
+
+import "time"
+
+// Clock is the source of the wall-clock time which can be replaced in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After returns a channel that receives the current time once the
+	// duration has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the clock of the operating system.
+type systemClock struct{}
+
+// Now returns the current time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After returns a channel that receives the current time once the duration
+// has elapsed.
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
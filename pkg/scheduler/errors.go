@@ -0,0 +1,12 @@
+package scheduler
+
+import "fmt"
+
+var (
+	// ErrMissingParams is returned if the protocol parameters are missing.
+	ErrMissingParams = fmt.Errorf("missing params")
+	// ErrMissingPuzzle is returned if a puzzle is missing.
+	ErrMissingPuzzle = fmt.Errorf("missing puzzle")
+	// ErrInvalidSafetyMargin is returned if the safety margin is negative.
+	ErrInvalidSafetyMargin = fmt.Errorf("invalid safety margin")
+)
@@ -0,0 +1,237 @@
+// Package scheduler reveals the plaintexts of puzzles at target wall-clock
+// times.
+//
+// The time it takes to solve a puzzle is estimated via a calibration profile
+// (see params.Calibrate) which is why the solving run can be started ahead of
+// the release time. The solved plaintext is held back and published no earlier
+// than the release time.
+package scheduler
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// DefaultSafetyMargin is the default fraction of the estimated solving time
+// that's added to it to account for hosts that are slower than the calibrated
+// one.
+const DefaultSafetyMargin = 0.1
+
+// Options configures a scheduler.
+type Options struct {
+	// SafetyMargin is the fraction of the estimated solving time that's added
+	// to it when the start of a solving run is computed. Defaults to
+	// DefaultSafetyMargin if nil (a pointer to 0 disables the margin).
+	SafetyMargin *float64
+	// Clock is the source of the wall-clock time. Defaults to the clock of the
+	// operating system if not set.
+	Clock Clock
+}
+
+// safetyMargin returns the configured safety margin.
+func (o *Options) safetyMargin() float64 {
+	if o == nil || o.SafetyMargin == nil {
+		return DefaultSafetyMargin
+	}
+
+	return *o.SafetyMargin
+}
+
+// clock returns the configured clock.
+func (o *Options) clock() Clock {
+	if o == nil || o.Clock == nil {
+		return systemClock{}
+	}
+
+	return o.Clock
+}
+
+// Release is the outcome of a scheduled unlock.
+type Release struct {
+	// Puzzle is the puzzle that was solved.
+	Puzzle *puzzle.Puzzle
+	// ReleaseAt is the time the plaintext is released at.
+	ReleaseAt time.Time
+	// Plaintext is the plaintext that was hidden inside of the puzzle (or nil
+	// if Err is set).
+	Plaintext *big.Int
+	// StartedAt is the time the solving run was started at (or the zero time
+	// if the unlock was canceled before).
+	StartedAt time.Time
+	// SolvedAt is the time the puzzle was solved at.
+	SolvedAt time.Time
+	// PublishedAt is the time the release was published at.
+	PublishedAt time.Time
+	// Err is the reason why the puzzle couldn't be solved (e.g. because the
+	// unlock was canceled).
+	Err error
+}
+
+// Unlock is an instance of a scheduled unlock of a puzzle.
+type Unlock struct {
+	// C receives the release once it's published.
+	C <-chan *Release
+
+	cancel context.CancelFunc
+}
+
+// Cancel cancels the unlock. The release is published immediately with the
+// cancellation as its error unless it was already published.
+func (u *Unlock) Cancel() {
+	u.cancel()
+}
+
+// Scheduler schedules the unlocks of puzzles that were generated with the same
+// protocol parameters.
+type Scheduler struct {
+	params   *params.Params
+	estimate time.Duration
+	lead     time.Duration
+	clock    Clock
+}
+
+// NewScheduler creates a new scheduler which estimates the time it takes to
+// solve a puzzle via the calibration profile. The options can be nil.
+// Returns an error if the protocol parameters are missing or invalid, if the
+// profile doesn't contain a measurement for the size of the modulus or if the
+// safety margin is negative.
+func NewScheduler(p *params.Params, profile *params.Profile, opts *Options) (*Scheduler, error) {
+	if p == nil {
+		return nil, ErrMissingParams
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	margin := opts.safetyMargin()
+	if margin < 0 || math.IsNaN(margin) {
+		return nil, ErrInvalidSafetyMargin
+	}
+
+	estimate, err := params.EstimateDuration(p, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	lead := time.Duration(math.MaxInt64)
+	if l := float64(estimate) * (1 + margin); l < math.MaxInt64 {
+		lead = time.Duration(l)
+	}
+
+	s := &Scheduler{
+		params:   p,
+		estimate: estimate,
+		lead:     lead,
+		clock:    opts.clock(),
+	}
+
+	return s, nil
+}
+
+// Estimate returns the estimated time it takes to solve a puzzle.
+func (s *Scheduler) Estimate() time.Duration {
+	return s.estimate
+}
+
+// StartAt returns the time the solving run of a puzzle that's released at the
+// time is started at which is the estimated solving time (plus the safety
+// margin) ahead of the release time.
+func (s *Scheduler) StartAt(releaseAt time.Time) time.Time {
+	return releaseAt.Add(-s.lead)
+}
+
+// Schedule starts solving the puzzle at StartAt(releaseAt) (or immediately if
+// that time has already passed) and publishes the release no earlier than the
+// release time. The release is sent to the returned unlock's channel and
+// passed to onRelease (which can be nil). Canceling the context cancels the
+// unlock.
+// Returns an error if the puzzle is missing or isn't well-formed with respect
+// to the protocol parameters.
+func (s *Scheduler) Schedule(ctx context.Context, z *puzzle.Puzzle, releaseAt time.Time, onRelease func(*Release)) (*Unlock, error) {
+	if z == nil {
+		return nil, ErrMissingPuzzle
+	}
+
+	if err := z.Validate(s.params); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c := make(chan *Release, 1)
+
+	go func() {
+		defer cancel()
+
+		release := s.run(ctx, z, releaseAt)
+		if onRelease != nil {
+			onRelease(release)
+		}
+		c <- release
+	}()
+
+	u := &Unlock{
+		C:      c,
+		cancel: cancel,
+	}
+
+	return u, nil
+}
+
+// run waits for the start of the solving run, solves the puzzle and holds the
+// plaintext back until the release time.
+func (s *Scheduler) run(ctx context.Context, z *puzzle.Puzzle, releaseAt time.Time) *Release {
+	release := &Release{
+		Puzzle:    z,
+		ReleaseAt: releaseAt,
+	}
+
+	publish := func(err error) *Release {
+		release.Err = err
+		release.PublishedAt = s.clock.Now()
+		return release
+	}
+
+	if err := s.waitUntil(ctx, s.StartAt(releaseAt)); err != nil {
+		return publish(err)
+	}
+
+	release.StartedAt = s.clock.Now()
+
+	plaintext, err := puzzle.SolvePuzzleContext(ctx, s.params, z, nil)
+	if err != nil {
+		return publish(err)
+	}
+
+	release.SolvedAt = s.clock.Now()
+
+	if err := s.waitUntil(ctx, releaseAt); err != nil {
+		return publish(err)
+	}
+
+	release.Plaintext = plaintext
+
+	return publish(nil)
+}
+
+// waitUntil waits until the clock reaches the time.
+// Returns an error if the context is canceled before.
+func (s *Scheduler) waitUntil(ctx context.Context, t time.Time) error {
+	for {
+		d := t.Sub(s.clock.Now())
+		if d <= 0 {
+			return nil
+		}
+
+		select {
+		case <-s.clock.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
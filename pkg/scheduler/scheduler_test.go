@@ -0,0 +1,366 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/scheduler"
+)
+
+// fakeClock is a clock whose time only moves when it's advanced.
+type fakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []waiter
+}
+
+// waiter is a pending call of After.
+type waiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// newFakeClock creates a new fake clock that starts at the time.
+func newFakeClock(now time.Time) *fakeClock {
+	c := &fakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After returns a channel that receives the time once the clock was advanced
+// by the duration.
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.waiters = append(c.waiters, waiter{at: c.now.Add(d), c: ch})
+	c.cond.Broadcast()
+
+	return ch
+}
+
+// Advance advances the clock by the duration and fires the due waiters.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	var pending []waiter
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = pending
+}
+
+// BlockUntil blocks until n calls of After are pending.
+func (c *fakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// newProfile creates a calibration profile according to which it takes 10
+// seconds to solve a puzzle that was generated with the protocol parameters.
+func newProfile(p *params.Params) *params.Profile {
+	m := &params.Measurement{
+		Bits:      p.N.BitLen(),
+		Squarings: p.T.Uint64(),
+		Elapsed:   10 * time.Second,
+	}
+
+	return &params.Profile{Measurements: []*params.Measurement{m}}
+}
+
+// start is the time the fake clocks start at.
+var start = time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Solve ahead of Release Time / Publish at Release Time", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+		clock := newFakeClock(start)
+		releaseAt := start.Add(time.Hour)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		s, err := scheduler.NewScheduler(params, newProfile(params), &scheduler.Options{Clock: clock})
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		// 10 seconds plus the default safety margin of 10%.
+		startAt := releaseAt.Add(-11 * time.Second)
+		if !s.StartAt(releaseAt).Equal(startAt) {
+			t.Errorf("want start at %v, got %v", startAt, s.StartAt(releaseAt))
+		}
+
+		callback := make(chan *scheduler.Release, 1)
+		u, err := s.Schedule(context.Background(), puzzle1, releaseAt, func(r *scheduler.Release) {
+			callback <- r
+		})
+		if err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+
+		// Wait for the start of the solving run.
+		clock.BlockUntil(1)
+		clock.Advance(startAt.Sub(start))
+
+		// Wait for the release time after the puzzle was solved.
+		clock.BlockUntil(1)
+
+		select {
+		case r := <-u.C:
+			t.Fatalf("want no release before %v, got %v", releaseAt, r)
+		default:
+		}
+
+		clock.Advance(11 * time.Second)
+		r := <-u.C
+
+		if r.Err != nil {
+			t.Fatalf("want no error, got %v", r.Err)
+		}
+
+		if r.Plaintext.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, r.Plaintext)
+		}
+
+		if !r.StartedAt.Equal(startAt) || !r.SolvedAt.Equal(startAt) {
+			t.Errorf("want start and solve at %v, got %v and %v", startAt, r.StartedAt, r.SolvedAt)
+		}
+
+		if r.PublishedAt.Before(releaseAt) {
+			t.Errorf("want publish no earlier than %v, got %v", releaseAt, r.PublishedAt)
+		}
+
+		if cr := <-callback; cr != r {
+			t.Errorf("want %v, got %v", r, cr)
+		}
+	})
+
+	t.Run("Start immediately if Start Time has passed", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+		clock := newFakeClock(start)
+		releaseAt := start.Add(5 * time.Second)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		s, _ := scheduler.NewScheduler(params, newProfile(params), &scheduler.Options{Clock: clock})
+		u, _ := s.Schedule(context.Background(), puzzle1, releaseAt, nil)
+
+		clock.BlockUntil(1)
+		clock.Advance(5 * time.Second)
+		r := <-u.C
+
+		if r.Plaintext.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, r.Plaintext)
+		}
+
+		if !r.StartedAt.Equal(start) || !r.PublishedAt.Equal(releaseAt) {
+			t.Errorf("want start at %v and publish at %v, got %v and %v", start, releaseAt, r.StartedAt, r.PublishedAt)
+		}
+	})
+
+	t.Run("Publish immediately if Release Time has passed", func(t *testing.T) {
+		t.Parallel()
+
+		message := big.NewInt(42)
+		clock := newFakeClock(start)
+		releaseAt := start.Add(-time.Minute)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, message)
+
+		s, _ := scheduler.NewScheduler(params, newProfile(params), &scheduler.Options{Clock: clock})
+		u, _ := s.Schedule(context.Background(), puzzle1, releaseAt, nil)
+		r := <-u.C
+
+		if r.Plaintext.Cmp(message) != 0 {
+			t.Errorf("want %v, got %v", message, r.Plaintext)
+		}
+
+		if !r.PublishedAt.Equal(start) {
+			t.Errorf("want publish at %v, got %v", start, r.PublishedAt)
+		}
+	})
+
+	t.Run("Safety Margin", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+
+		tests := []struct {
+			name   string
+			margin float64
+			want   time.Duration
+		}{
+			{"50%", 0.5, 15 * time.Second},
+			{"No Margin", 0, 10 * time.Second},
+		}
+
+		for _, tc := range tests {
+			s, _ := scheduler.NewScheduler(params, newProfile(params), &scheduler.Options{SafetyMargin: &tc.margin})
+
+			if s.Estimate() != 10*time.Second {
+				t.Errorf("%v: want estimate %v, got %v", tc.name, 10*time.Second, s.Estimate())
+			}
+
+			startAt := start.Add(-tc.want)
+			if !s.StartAt(start).Equal(startAt) {
+				t.Errorf("%v: want start at %v, got %v", tc.name, startAt, s.StartAt(start))
+			}
+		}
+	})
+
+	t.Run("Cancel before Start", func(t *testing.T) {
+		t.Parallel()
+
+		clock := newFakeClock(start)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		s, _ := scheduler.NewScheduler(params, newProfile(params), &scheduler.Options{Clock: clock})
+		u, _ := s.Schedule(context.Background(), puzzle1, start.Add(time.Hour), nil)
+
+		clock.BlockUntil(1)
+		u.Cancel()
+		r := <-u.C
+
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("want error %v, got %v", context.Canceled, r.Err)
+		}
+
+		if r.Plaintext != nil || !r.StartedAt.IsZero() {
+			t.Errorf("want no plaintext and start, got %v and %v", r.Plaintext, r.StartedAt)
+		}
+	})
+
+	t.Run("Don't publish Plaintext when canceled before Release Time", func(t *testing.T) {
+		t.Parallel()
+
+		clock := newFakeClock(start)
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		puzzle1, _ := puzzle.GeneratePuzzle(params, big.NewInt(42))
+
+		s, _ := scheduler.NewScheduler(params, newProfile(params), &scheduler.Options{Clock: clock})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		u, _ := s.Schedule(ctx, puzzle1, start.Add(time.Second), nil)
+
+		// Wait for the release time after the puzzle was solved.
+		clock.BlockUntil(1)
+		cancel()
+		r := <-u.C
+
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("want error %v, got %v", context.Canceled, r.Err)
+		}
+
+		if r.Plaintext != nil {
+			t.Errorf("want no plaintext, got %v", r.Plaintext)
+		}
+	})
+
+	t.Run("Error when params are missing", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := scheduler.NewScheduler(nil, &params.Profile{}, nil)
+		if !errors.Is(err, scheduler.ErrMissingParams) {
+			t.Errorf("want error %v, got %v", scheduler.ErrMissingParams, err)
+		}
+	})
+
+	t.Run("Error when params are invalid", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		profile := newProfile(params1)
+		params1.T = big.NewInt(0)
+
+		_, err := scheduler.NewScheduler(params1, profile, nil)
+		if !errors.Is(err, params.ErrInvalidT) {
+			t.Errorf("want error %v, got %v", params.ErrInvalidT, err)
+		}
+	})
+
+	t.Run("Error when profile doesn't contain a measurement", func(t *testing.T) {
+		t.Parallel()
+
+		params1, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+
+		for _, profile := range []*params.Profile{nil, {}} {
+			_, err := scheduler.NewScheduler(params1, profile, nil)
+			if !errors.Is(err, params.ErrMissingMeasurement) {
+				t.Errorf("want error %v, got %v", params.ErrMissingMeasurement, err)
+			}
+		}
+	})
+
+	t.Run("Error when safety margin is negative", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+
+		margin := -1.0
+		_, err := scheduler.NewScheduler(params, newProfile(params), &scheduler.Options{SafetyMargin: &margin})
+		if !errors.Is(err, scheduler.ErrInvalidSafetyMargin) {
+			t.Errorf("want error %v, got %v", scheduler.ErrInvalidSafetyMargin, err)
+		}
+	})
+
+	t.Run("Error when puzzle is missing or invalid", func(t *testing.T) {
+		t.Parallel()
+
+		params, _ := params.GenerateParams(128, 2, big.NewInt(1_000))
+		s, _ := scheduler.NewScheduler(params, newProfile(params), nil)
+
+		if _, err := s.Schedule(context.Background(), nil, start, nil); !errors.Is(err, scheduler.ErrMissingPuzzle) {
+			t.Errorf("want error %v, got %v", scheduler.ErrMissingPuzzle, err)
+		}
+
+		puzzle1 := puzzle.NewPuzzle(big.NewInt(0), big.NewInt(1))
+		if _, err := s.Schedule(context.Background(), puzzle1, start, nil); !errors.Is(err, puzzle.ErrInvalidU) {
+			t.Errorf("want error %v, got %v", puzzle.ErrInvalidU, err)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package server
+
+import "fmt"
+
+var (
+	// ErrMissingParams is returned if the protocol parameters are missing.
+	ErrMissingParams = fmt.Errorf("missing params")
+	// ErrInvalidRequest is returned if a request body isn't a valid JSON object of the expected form.
+	ErrInvalidRequest = fmt.Errorf("invalid request body")
+	// ErrRequestTooLarge is returned if a request body exceeds the maximum size.
+	ErrRequestTooLarge = fmt.Errorf("request body too large")
+	// ErrInvalidValue is returned if a value isn't a canonical hex string.
+	ErrInvalidValue = fmt.Errorf("invalid value")
+	// ErrValueOutOfRange is returned if a plaintext value isn't an element of {0, ..., n^(y - 1) - 1}.
+	ErrValueOutOfRange = fmt.Errorf("value out of range")
+	// ErrMissingPuzzle is returned if a puzzle is missing.
+	ErrMissingPuzzle = fmt.Errorf("missing puzzle")
+	// ErrTooManyPuzzles is returned if a request contains too many puzzles.
+	ErrTooManyPuzzles = fmt.Errorf("too many puzzles")
+	// ErrMissingProof is returned if a proof is missing.
+	ErrMissingProof = fmt.Errorf("missing proof")
+	// ErrInvalidProofBits is returned if the security parameter of a proof is out of range.
+	ErrInvalidProofBits = fmt.Errorf("invalid proof security parameter")
+	// ErrJobNotFound is returned if a job doesn't exist.
+	ErrJobNotFound = fmt.Errorf("job not found")
+	// ErrTooManyJobs is returned if the maximum number of jobs is reached.
+	ErrTooManyJobs = fmt.Errorf("too many jobs")
+	// ErrServerClosed is returned if the server was closed.
+	ErrServerClosed = fmt.Errorf("server closed")
+	// ErrSampleJobID is returned if the random job id can't be sampled.
+	ErrSampleJobID = fmt.Errorf("unable to sample random job id")
+)
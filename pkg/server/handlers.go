@@ -0,0 +1,374 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+
+	"github.com/primefactor-io/lhtlp/pkg/homomorphic"
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// Error codes of error responses.
+const (
+	codeInvalidRequest  = "invalid_request"
+	codeRequestTooLarge = "request_too_large"
+	codeNotFound        = "not_found"
+	codeTooManyJobs     = "too_many_jobs"
+	codeUnavailable     = "unavailable"
+	codeInternal        = "internal"
+)
+
+// errorJSON is the JSON representation of an error response.
+type errorJSON struct {
+	Error errorDetailsJSON `json:"error"`
+}
+
+// errorDetailsJSON is the JSON representation of an error.
+type errorDetailsJSON struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// createPuzzleRequest is the body of a puzzle creation request.
+type createPuzzleRequest struct {
+	Value string `json:"value"`
+}
+
+// addPuzzlesRequest is the body of a puzzle addition request.
+type addPuzzlesRequest struct {
+	Puzzles []*puzzle.Puzzle `json:"puzzles"`
+	Value   string           `json:"value,omitempty"`
+}
+
+// mulPuzzleRequest is the body of a puzzle multiplication request.
+type mulPuzzleRequest struct {
+	Puzzle *puzzle.Puzzle `json:"puzzle"`
+	Value  string         `json:"value"`
+}
+
+// verifyRangeProofRequest is the body of a Range proof verification request.
+type verifyRangeProofRequest struct {
+	Bits    int                `json:"bits"`
+	Q       string             `json:"q"`
+	Puzzles []*puzzle.Puzzle   `json:"puzzles"`
+	Proof   *proofs.RangeProof `json:"proof"`
+}
+
+// createJobRequest is the body of a job creation request.
+type createJobRequest struct {
+	Puzzle *puzzle.Puzzle `json:"puzzle"`
+}
+
+// puzzleResponse is the body of a response that contains a puzzle.
+type puzzleResponse struct {
+	Puzzle *puzzle.Puzzle `json:"puzzle"`
+}
+
+// verifyResponse is the body of a verification response.
+type verifyResponse struct {
+	Valid bool `json:"valid"`
+}
+
+// handleParams publishes the protocol parameters.
+func (s *Server) handleParams(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.params)
+}
+
+// handleCreatePuzzle creates a puzzle that hides the requested value.
+func (s *Server) handleCreatePuzzle(w http.ResponseWriter, r *http.Request) {
+	var req createPuzzleRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	value, err := s.parsePlaintext(req.Value)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+		return
+	}
+
+	z, err := puzzle.GeneratePuzzle(s.params, value)
+	if err != nil {
+		writeOperationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, &puzzleResponse{Puzzle: z})
+}
+
+// handleAddPuzzles adds the plaintext values of the requested puzzles (and the
+// optional value).
+func (s *Server) handleAddPuzzles(w http.ResponseWriter, r *http.Request) {
+	var req addPuzzlesRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if err := s.checkPuzzles(req.Puzzles...); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+		return
+	}
+
+	z, err := homomorphic.AddPlaintextValues(s.params, req.Puzzles...)
+	if err != nil {
+		writeOperationError(w, err)
+		return
+	}
+
+	if req.Value != "" {
+		value, err := s.parsePlaintext(req.Value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+			return
+		}
+
+		if z, err = homomorphic.AddPlaintextValue(s.params, z, value); err != nil {
+			writeOperationError(w, err)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, &puzzleResponse{Puzzle: z})
+}
+
+// handleMulPuzzle multiplies the plaintext value of the requested puzzle by
+// the value.
+func (s *Server) handleMulPuzzle(w http.ResponseWriter, r *http.Request) {
+	var req mulPuzzleRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if err := s.checkPuzzles(req.Puzzle); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+		return
+	}
+
+	value, err := s.parsePlaintext(req.Value)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+		return
+	}
+
+	z, err := homomorphic.MultiplyPlaintextValue(s.params, req.Puzzle, value)
+	if err != nil {
+		writeOperationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &puzzleResponse{Puzzle: z})
+}
+
+// handleVerifyRangeProof verifies the requested Range proof.
+func (s *Server) handleVerifyRangeProof(w http.ResponseWriter, r *http.Request) {
+	var req verifyRangeProofRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Bits <= 0 || req.Bits > MaxProofBits {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, ErrInvalidProofBits)
+		return
+	}
+
+	if req.Proof == nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, ErrMissingProof)
+		return
+	}
+
+	if err := s.checkPuzzles(req.Puzzles...); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+		return
+	}
+
+	q, err := parseHex(req.Q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+		return
+	}
+
+	valid, err := proofs.VerifyRangePoof(req.Proof, req.Bits, s.params, req.Puzzles, q)
+	if err != nil {
+		writeOperationError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &verifyResponse{Valid: valid})
+}
+
+// handleCreateJob starts a job that solves the requested puzzle.
+func (s *Server) handleCreateJob(w http.ResponseWriter, r *http.Request) {
+	var req createJobRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if err := s.checkPuzzles(req.Puzzle); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+		return
+	}
+
+	if err := req.Puzzle.Validate(s.params); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+		return
+	}
+
+	j, err := s.startJob(req.Puzzle)
+	switch {
+	case errors.Is(err, ErrTooManyJobs):
+		writeError(w, http.StatusServiceUnavailable, codeTooManyJobs, err)
+		return
+	case errors.Is(err, ErrServerClosed):
+		writeError(w, http.StatusServiceUnavailable, codeUnavailable, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, err)
+		return
+	}
+
+	w.Header().Set("Location", "/v1/jobs/"+j.id)
+	writeJSON(w, http.StatusAccepted, j.snapshot())
+}
+
+// handleGetJob returns the state of the job.
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	j, ok := s.lookupJob(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, ErrJobNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, j.snapshot())
+}
+
+// handleDeleteJob cancels and removes the job.
+func (s *Server) handleDeleteJob(w http.ResponseWriter, r *http.Request) {
+	if !s.removeJob(r.PathValue("id")) {
+		writeError(w, http.StatusNotFound, codeNotFound, ErrJobNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkPuzzles checks that there's at least one and at most the maximum
+// number of puzzles and that none of them is missing.
+// Returns an error if a check fails.
+func (s *Server) checkPuzzles(puzzles ...*puzzle.Puzzle) error {
+	if len(puzzles) == 0 {
+		return ErrMissingPuzzle
+	}
+
+	if len(puzzles) > s.opts.maxPuzzles() {
+		return ErrTooManyPuzzles
+	}
+
+	for _, z := range puzzles {
+		if z == nil {
+			return ErrMissingPuzzle
+		}
+	}
+
+	return nil
+}
+
+// readJSON decodes the request body into v while rejecting unknown fields and
+// trailing data. An error response is written if the body can't be decoded.
+// Returns false if the body can't be decoded.
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, codeRequestTooLarge, ErrRequestTooLarge)
+			return false
+		}
+
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, ErrInvalidRequest)
+		return false
+	}
+
+	if err := utils.DecodeJSON(data, v); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, ErrInvalidRequest)
+		return false
+	}
+
+	return true
+}
+
+// writeJSON writes v as the JSON response body with the status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+// writeError writes the error as the JSON response body with the status code.
+func writeError(w http.ResponseWriter, status int, code string, err error) {
+	data, _ := json.Marshal(&errorJSON{
+		Error: errorDetailsJSON{
+			Code:    code,
+			Message: err.Error(),
+		},
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+// writeOperationError writes the error of a library operation. Errors are
+// caused by the request (e.g. invalid puzzles or values) unless randomness
+// couldn't be sampled.
+func writeOperationError(w http.ResponseWriter, err error) {
+	if errors.Is(err, puzzle.ErrSampleNonceR) {
+		writeError(w, http.StatusInternalServerError, codeInternal, err)
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, codeInvalidRequest, err)
+}
+
+// parseHex parses a big integer that's encoded as a canonical hex string.
+// Returns an error if the string is missing or isn't a canonical hex string.
+func parseHex(s string) (*big.Int, error) {
+	x, err := utils.HexToBigInt(s)
+	if err != nil {
+		return nil, ErrInvalidValue
+	}
+
+	return x, nil
+}
+
+// parsePlaintext parses a plaintext value that's encoded as a canonical hex
+// string.
+// Returns an error if the string isn't a canonical hex string or if the value
+// isn't an element of {0, ..., n^(y - 1) - 1}.
+func (s *Server) parsePlaintext(str string) (*big.Int, error) {
+	x, err := parseHex(str)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.Sign() < 0 || x.Cmp(s.params.NExpYMinusOne) >= 0 {
+		return nil, ErrValueOutOfRange
+	}
+
+	return x, nil
+}
+
+// hexOf returns the canonical hex string of x.
+func hexOf(x *big.Int) string {
+	return utils.BigIntToHex(x)
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+)
+
+// JobState is the state of a solve job.
+type JobState string
+
+const (
+	// JobQueued is the state of a job that waits for a free worker.
+	JobQueued JobState = "queued"
+	// JobRunning is the state of a job whose puzzle is being solved.
+	JobRunning JobState = "running"
+	// JobDone is the state of a job whose puzzle was solved.
+	JobDone JobState = "done"
+	// JobFailed is the state of a job whose puzzle couldn't be solved.
+	JobFailed JobState = "failed"
+)
+
+// job is an instance of an asynchronous solve job.
+type job struct {
+	id     string
+	puzzle *puzzle.Puzzle
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	state     JobState
+	squarings *big.Int
+	total     *big.Int
+	plaintext *big.Int
+	err       error
+	finished  time.Time
+}
+
+// jobJSON is the JSON representation of a job.
+type jobJSON struct {
+	ID        string   `json:"id"`
+	State     JobState `json:"state"`
+	Squarings string   `json:"squarings"`
+	Total     string   `json:"total"`
+	Plaintext string   `json:"plaintext,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// snapshot returns the JSON representation of the job's current state.
+func (j *job) snapshot() *jobJSON {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	v := &jobJSON{
+		ID:        j.id,
+		State:     j.state,
+		Squarings: hexOf(j.squarings),
+		Total:     hexOf(j.total),
+	}
+
+	if j.plaintext != nil {
+		v.Plaintext = hexOf(j.plaintext)
+	}
+
+	if j.err != nil {
+		v.Error = j.err.Error()
+	}
+
+	return v
+}
+
+// startJob registers a job for the puzzle and solves it in the background
+// once a worker is free. Finished jobs whose retention period has passed are
+// removed beforehand.
+// Returns an error if the server was closed or if too many jobs are queued or
+// running.
+func (s *Server) startJob(z *puzzle.Puzzle) (*job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+
+	j := &job{
+		id:        id,
+		puzzle:    z,
+		cancel:    cancel,
+		state:     JobQueued,
+		squarings: new(big.Int),
+		total:     new(big.Int).Set(s.params.T),
+	}
+
+	s.mu.Lock()
+	if s.closed() {
+		s.mu.Unlock()
+		cancel()
+		return nil, ErrServerClosed
+	}
+
+	s.expireJobs()
+
+	if s.active >= s.opts.maxJobs() {
+		s.mu.Unlock()
+		cancel()
+		return nil, ErrTooManyJobs
+	}
+
+	s.jobs[id] = j
+	s.active++
+	s.wg.Add(1)
+	s.mu.Unlock()
+
+	go s.runJob(ctx, j)
+
+	return j, nil
+}
+
+// runJob waits for a free worker and solves the job's puzzle.
+func (s *Server) runJob(ctx context.Context, j *job) {
+	defer s.wg.Done()
+	defer j.cancel()
+	defer func() {
+		s.mu.Lock()
+		s.active--
+		s.mu.Unlock()
+	}()
+
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-ctx.Done():
+		j.finish(nil, ctx.Err())
+		return
+	}
+
+	j.mu.Lock()
+	j.state = JobRunning
+	j.mu.Unlock()
+
+	opts := &puzzle.SolveOptions{
+		OnProgress: func(p puzzle.Progress) {
+			j.mu.Lock()
+			j.squarings = new(big.Int).Set(p.Squarings)
+			j.mu.Unlock()
+		},
+	}
+
+	plaintext, err := puzzle.SolvePuzzleContext(ctx, s.params, j.puzzle, opts)
+	j.finish(plaintext, err)
+}
+
+// finish records the result of the job.
+func (j *job) finish(plaintext *big.Int, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.finished = time.Now()
+
+	if err != nil {
+		j.state = JobFailed
+		j.err = err
+		return
+	}
+
+	j.state = JobDone
+	j.squarings = new(big.Int).Set(j.total)
+	j.plaintext = plaintext
+}
+
+// expireJobs removes the finished jobs whose retention period has passed.
+// Note: The caller needs to ensure that s.mu is held.
+func (s *Server) expireJobs() {
+	cutoff := time.Now().Add(-s.opts.jobRetention())
+
+	for id, j := range s.jobs {
+		j.mu.Lock()
+		expired := !j.finished.IsZero() && j.finished.Before(cutoff)
+		j.mu.Unlock()
+
+		if expired {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// lookupJob returns the job with the id.
+func (s *Server) lookupJob(id string) (*job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+
+	return j, ok
+}
+
+// removeJob cancels and removes the job with the id.
+// Returns false if the job doesn't exist.
+func (s *Server) removeJob(id string) bool {
+	s.mu.Lock()
+	j, ok := s.jobs[id]
+	delete(s.jobs, id)
+	s.mu.Unlock()
+
+	if ok {
+		j.cancel()
+	}
+
+	return ok
+}
+
+// newJobID returns a random job id.
+// Returns an error if the id can't be sampled.
+func newJobID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", ErrSampleJobID
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,186 @@
+// Package server exposes the scheme as an HTTP/JSON service which publishes
+// the protocol parameters, creates and combines puzzles, verifies Range proofs
+// and solves puzzles in asynchronous jobs that run in-process.
+package server
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+)
+
+// DefaultMaxBodySize is the default maximum size (in bytes) of request bodies.
+const DefaultMaxBodySize = 1024 * 1024
+
+// DefaultMaxJobs is the default maximum number of queued or running jobs.
+const DefaultMaxJobs = 1024
+
+// DefaultJobRetention is the default duration for which finished jobs are
+// kept.
+const DefaultJobRetention = 10 * time.Minute
+
+// DefaultMaxPuzzles is the default maximum number of puzzles per request.
+const DefaultMaxPuzzles = 1024
+
+// MaxProofBits is the maximum security parameter of Range proofs that are
+// verified (which bounds the work of a verification).
+const MaxProofBits = 256
+
+// Options configures a server.
+type Options struct {
+	// MaxBodySize is the maximum size (in bytes) of request bodies. Defaults
+	// to DefaultMaxBodySize if not set.
+	MaxBodySize int64
+	// MaxJobs is the maximum number of queued or running jobs (finished jobs
+	// don't count). Defaults to DefaultMaxJobs if not set.
+	MaxJobs int
+	// JobRetention is the duration for which finished jobs are kept before
+	// they're removed. Defaults to DefaultJobRetention if not set.
+	JobRetention time.Duration
+	// MaxPuzzles is the maximum number of puzzles per request. Defaults to
+	// DefaultMaxPuzzles if not set.
+	MaxPuzzles int
+	// Workers is the number of jobs that are solved concurrently. Defaults to
+	// the number of CPUs if not set.
+	Workers int
+}
+
+// maxBodySize returns the configured maximum size of request bodies.
+func (o *Options) maxBodySize() int64 {
+	if o == nil || o.MaxBodySize <= 0 {
+		return DefaultMaxBodySize
+	}
+
+	return o.MaxBodySize
+}
+
+// maxJobs returns the configured maximum number of jobs.
+func (o *Options) maxJobs() int {
+	if o == nil || o.MaxJobs <= 0 {
+		return DefaultMaxJobs
+	}
+
+	return o.MaxJobs
+}
+
+// jobRetention returns the configured duration for which finished jobs are
+// kept.
+func (o *Options) jobRetention() time.Duration {
+	if o == nil || o.JobRetention <= 0 {
+		return DefaultJobRetention
+	}
+
+	return o.JobRetention
+}
+
+// maxPuzzles returns the configured maximum number of puzzles per request.
+func (o *Options) maxPuzzles() int {
+	if o == nil || o.MaxPuzzles <= 0 {
+		return DefaultMaxPuzzles
+	}
+
+	return o.MaxPuzzles
+}
+
+// workers returns the configured number of concurrent jobs.
+func (o *Options) workers() int {
+	if o == nil || o.Workers <= 0 {
+		return runtime.NumCPU()
+	}
+
+	return o.Workers
+}
+
+// Server is an HTTP handler that serves the API for the protocol parameters.
+//
+// The API consists of the following endpoints (all bodies are JSON objects,
+// puzzles, proofs and protocol parameters use their JSON form and big
+// integers are canonical hex strings):
+//
+//	GET    /v1/params               the protocol parameters
+//	POST   /v1/puzzles              {"value"} -> {"puzzle"}
+//	POST   /v1/puzzles/add          {"puzzles", "value" (optional)} -> {"puzzle"}
+//	POST   /v1/puzzles/mul          {"puzzle", "value"} -> {"puzzle"}
+//	POST   /v1/proofs/range/verify  {"bits", "q", "puzzles", "proof"} -> {"valid"}
+//	POST   /v1/jobs                 {"puzzle"} -> job
+//	GET    /v1/jobs/{id}            job
+//	DELETE /v1/jobs/{id}            cancels and removes the job
+//
+// Errors are reported as {"error": {"code", "message"}} (apart from unknown
+// endpoints and methods which are handled by http.ServeMux).
+type Server struct {
+	params *params.Params
+	opts   *Options
+	mux    *http.ServeMux
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	jobs   map[string]*job
+	active int
+}
+
+// NewServer creates a server for the protocol parameters. The options can be
+// nil.
+// Returns an error if the protocol parameters are invalid.
+func NewServer(params *params.Params, opts *Options) (*Server, error) {
+	if params == nil {
+		return nil, ErrMissingParams
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Server{
+		params: params,
+		opts:   opts,
+		mux:    http.NewServeMux(),
+		ctx:    ctx,
+		cancel: cancel,
+		sem:    make(chan struct{}, opts.workers()),
+		jobs:   make(map[string]*job),
+	}
+
+	s.mux.HandleFunc("GET /v1/params", s.handleParams)
+	s.mux.HandleFunc("POST /v1/puzzles", s.handleCreatePuzzle)
+	s.mux.HandleFunc("POST /v1/puzzles/add", s.handleAddPuzzles)
+	s.mux.HandleFunc("POST /v1/puzzles/mul", s.handleMulPuzzle)
+	s.mux.HandleFunc("POST /v1/proofs/range/verify", s.handleVerifyRangeProof)
+	s.mux.HandleFunc("POST /v1/jobs", s.handleCreateJob)
+	s.mux.HandleFunc("GET /v1/jobs/{id}", s.handleGetJob)
+	s.mux.HandleFunc("DELETE /v1/jobs/{id}", s.handleDeleteJob)
+
+	return s, nil
+}
+
+// ServeHTTP serves the API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.opts.maxBodySize())
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// Close cancels all running jobs and waits until they have stopped. Jobs
+// can't be created after the server was closed.
+func (s *Server) Close() {
+	s.mu.Lock()
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// closed reports whether the server was closed.
+func (s *Server) closed() bool {
+	return s.ctx.Err() != nil
+}
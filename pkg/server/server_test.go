@@ -0,0 +1,423 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/primefactor-io/lhtlp/pkg/params"
+	"github.com/primefactor-io/lhtlp/pkg/proofs"
+	"github.com/primefactor-io/lhtlp/pkg/puzzle"
+	"github.com/primefactor-io/lhtlp/pkg/server"
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+// job is the JSON representation of a job.
+type job struct {
+	ID        string `json:"id"`
+	State     string `json:"state"`
+	Squarings string `json:"squarings"`
+	Total     string `json:"total"`
+	Plaintext string `json:"plaintext"`
+	Error     string `json:"error"`
+}
+
+// apiError is the JSON representation of an error response.
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// newTestServer starts a test server for the protocol parameters.
+func newTestServer(t *testing.T, p *params.Params, opts *server.Options) *httptest.Server {
+	t.Helper()
+
+	srv, err := server.NewServer(p, opts)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		ts.Close()
+		srv.Close()
+	})
+
+	return ts
+}
+
+// call sends the request with the JSON encoded body (if not nil) and decodes
+// the JSON response body into res (if not nil). It returns the status code.
+func call(t *testing.T, ts *httptest.Server, method, path string, body, res any) int {
+	t.Helper()
+
+	var data []byte
+	if s, ok := body.(string); ok {
+		data = []byte(s)
+	} else if body != nil {
+		data, _ = json.Marshal(body)
+	}
+
+	req, _ := http.NewRequest(method, ts.URL+path, bytes.NewReader(data))
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	if res != nil {
+		if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	}
+
+	return resp.StatusCode
+}
+
+// hex returns the canonical hex encoding of x.
+func hex(x int64) string {
+	return utils.BigIntToHex(big.NewInt(x))
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	params1, _ := params.GenerateParams(256, 2, big.NewInt(1_000))
+	ts := newTestServer(t, params1, nil)
+
+	// createPuzzle creates a puzzle via the server.
+	createPuzzle := func(t *testing.T, value int64) *puzzle.Puzzle {
+		var res struct {
+			Puzzle *puzzle.Puzzle `json:"puzzle"`
+		}
+
+		status := call(t, ts, http.MethodPost, "/v1/puzzles", map[string]string{"value": hex(value)}, &res)
+		if status != http.StatusCreated {
+			t.Fatalf("want status %v, got %v", http.StatusCreated, status)
+		}
+
+		return res.Puzzle
+	}
+
+	t.Run("Params", func(t *testing.T) {
+		t.Parallel()
+
+		var p params.Params
+		status := call(t, ts, http.MethodGet, "/v1/params", nil, &p)
+
+		if status != http.StatusOK {
+			t.Fatalf("want status %v, got %v", http.StatusOK, status)
+		}
+
+		if p.N.Cmp(params1.N) != 0 || p.T.Cmp(params1.T) != 0 {
+			t.Errorf("want %v, got %v", params1, p)
+		}
+	})
+
+	t.Run("Create / Add / Mul", func(t *testing.T) {
+		t.Parallel()
+
+		z1 := createPuzzle(t, 5)
+		z2 := createPuzzle(t, 7)
+
+		var sum, product struct {
+			Puzzle *puzzle.Puzzle `json:"puzzle"`
+		}
+
+		status := call(t, ts, http.MethodPost, "/v1/puzzles/add", map[string]any{"puzzles": []*puzzle.Puzzle{z1, z2}, "value": hex(3)}, &sum)
+		if status != http.StatusOK {
+			t.Fatalf("want status %v, got %v", http.StatusOK, status)
+		}
+
+		status = call(t, ts, http.MethodPost, "/v1/puzzles/mul", map[string]any{"puzzle": sum.Puzzle, "value": hex(2)}, &product)
+		if status != http.StatusOK {
+			t.Fatalf("want status %v, got %v", http.StatusOK, status)
+		}
+
+		result := puzzle.SolvePuzzle(params1, product.Puzzle)
+
+		if result.Cmp(big.NewInt(30)) != 0 {
+			t.Errorf("want %v, got %v", 30, result)
+		}
+	})
+
+	t.Run("Verify Range Proof", func(t *testing.T) {
+		t.Parallel()
+
+		bits := 128
+		q := big.NewInt(100)
+		x := big.NewInt(42)
+		z, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params1, x)
+		wit := []*proofs.PuzzleValues{proofs.NewPuzzleValues(x, r)}
+		proof, _ := proofs.GenerateRangeProof(bits, params1, []*puzzle.Puzzle{z}, q, wit)
+
+		other, _ := puzzle.GeneratePuzzle(params1, x)
+
+		tests := []struct {
+			name   string
+			puzzle *puzzle.Puzzle
+			valid  bool
+		}{
+			{"Valid", z, true},
+			{"Different Puzzle", other, false},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				var res struct {
+					Valid bool `json:"valid"`
+				}
+
+				req := map[string]any{"bits": bits, "q": utils.BigIntToHex(q), "puzzles": []*puzzle.Puzzle{tc.puzzle}, "proof": proof}
+				status := call(t, ts, http.MethodPost, "/v1/proofs/range/verify", req, &res)
+
+				if status != http.StatusOK {
+					t.Fatalf("want status %v, got %v", http.StatusOK, status)
+				}
+
+				if res.Valid != tc.valid {
+					t.Errorf("want %v, got %v", tc.valid, res.Valid)
+				}
+			})
+		}
+	})
+
+	t.Run("Solve Job", func(t *testing.T) {
+		t.Parallel()
+
+		z := createPuzzle(t, 42)
+
+		var created job
+		status := call(t, ts, http.MethodPost, "/v1/jobs", map[string]any{"puzzle": z}, &created)
+		if status != http.StatusAccepted {
+			t.Fatalf("want status %v, got %v", http.StatusAccepted, status)
+		}
+
+		var j job
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			call(t, ts, http.MethodGet, "/v1/jobs/"+created.ID, nil, &j)
+			if j.State == string(server.JobDone) {
+				break
+			}
+		}
+
+		if j.State != string(server.JobDone) || j.Plaintext != hex(42) || j.Squarings != j.Total {
+			t.Errorf("want a done job with plaintext %v, got %v", hex(42), j)
+		}
+
+		if status := call(t, ts, http.MethodDelete, "/v1/jobs/"+created.ID, nil, nil); status != http.StatusNoContent {
+			t.Errorf("want status %v, got %v", http.StatusNoContent, status)
+		}
+
+		if status := call(t, ts, http.MethodGet, "/v1/jobs/"+created.ID, nil, nil); status != http.StatusNotFound {
+			t.Errorf("want status %v, got %v", http.StatusNotFound, status)
+		}
+	})
+
+	t.Run("Error when request is invalid", func(t *testing.T) {
+		t.Parallel()
+
+		z := createPuzzle(t, 1)
+		invalid := puzzle.NewPuzzle(big.NewInt(0), big.NewInt(0))
+
+		// The proof only covers 8 of the 128 bits.
+		x := big.NewInt(1)
+		zx, r, _ := puzzle.GeneratePuzzleAndReturnNonce(params1, x)
+		truncated, _ := proofs.GenerateRangeProof(8, params1, []*puzzle.Puzzle{zx}, big.NewInt(100), []*proofs.PuzzleValues{proofs.NewPuzzleValues(x, r)})
+
+		tests := []struct {
+			name string
+			path string
+			body any
+		}{
+			{"Malformed JSON", "/v1/puzzles", `{"value":`},
+			{"Unknown Field", "/v1/puzzles", `{"value":"1","foo":1}`},
+			{"Trailing Data", "/v1/puzzles", `{"value":"1"}{}`},
+			{"Non-Canonical Value", "/v1/puzzles", map[string]string{"value": "0x1"}},
+			{"Value Out Of Range", "/v1/puzzles", map[string]string{"value": utils.BigIntToHex(params1.NExpYMinusOne)}},
+			{"Missing Puzzles", "/v1/puzzles/add", map[string]any{"puzzles": []*puzzle.Puzzle{}}},
+			{"Null Puzzle", "/v1/puzzles/add", `{"puzzles":[null]}`},
+			{"Invalid Puzzle", "/v1/puzzles/add", map[string]any{"puzzles": []*puzzle.Puzzle{z, invalid}}},
+			{"Missing Value", "/v1/puzzles/mul", map[string]any{"puzzle": z}},
+			{"Invalid Proof Bits", "/v1/proofs/range/verify", map[string]any{"bits": 100_000, "q": hex(1), "puzzles": []*puzzle.Puzzle{z}, "proof": proofs.NewRangeProof(nil, nil)}},
+			{"Missing Proof", "/v1/proofs/range/verify", map[string]any{"bits": 128, "q": hex(1), "puzzles": []*puzzle.Puzzle{z}}},
+			{"Empty Proof", "/v1/proofs/range/verify", map[string]any{"bits": 128, "q": hex(100), "puzzles": []*puzzle.Puzzle{z}, "proof": proofs.NewRangeProof(nil, nil)}},
+			{"Truncated Proof", "/v1/proofs/range/verify", map[string]any{"bits": 128, "q": hex(100), "puzzles": []*puzzle.Puzzle{zx}, "proof": truncated}},
+			{"Invalid Job Puzzle", "/v1/jobs", map[string]any{"puzzle": invalid}},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				var res apiError
+				status := call(t, ts, http.MethodPost, tc.path, tc.body, &res)
+
+				if status != http.StatusBadRequest || res.Error.Code != "invalid_request" {
+					t.Errorf("want status %v, got %v (%v)", http.StatusBadRequest, status, res.Error)
+				}
+			})
+		}
+	})
+
+	t.Run("Error when request is too large", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestServer(t, params1, &server.Options{MaxBodySize: 64})
+		body := `{"value":"` + strings.Repeat("1", 100) + `"}`
+
+		if status := call(t, ts, http.MethodPost, "/v1/puzzles", body, nil); status != http.StatusRequestEntityTooLarge {
+			t.Errorf("want status %v, got %v", http.StatusRequestEntityTooLarge, status)
+		}
+	})
+
+	t.Run("Error when job doesn't exist", func(t *testing.T) {
+		t.Parallel()
+
+		for _, method := range []string{http.MethodGet, http.MethodDelete} {
+			if status := call(t, ts, method, "/v1/jobs/foo", nil, nil); status != http.StatusNotFound {
+				t.Errorf("want status %v, got %v", http.StatusNotFound, status)
+			}
+		}
+	})
+}
+
+func TestServerJobs(t *testing.T) {
+	t.Parallel()
+
+	// The puzzles of these params take far longer to solve than the test runs.
+	params1, _ := params.GenerateParams(256, 2, new(big.Int).Lsh(big.NewInt(1), 40))
+	z, _ := puzzle.GeneratePuzzle(params1, big.NewInt(42))
+
+	t.Run("Queue / Cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestServer(t, params1, &server.Options{Workers: 1, MaxJobs: 2})
+
+		var job1, job2 job
+		call(t, ts, http.MethodPost, "/v1/jobs", map[string]any{"puzzle": z}, &job1)
+		call(t, ts, http.MethodPost, "/v1/jobs", map[string]any{"puzzle": z}, &job2)
+
+		var res apiError
+		status := call(t, ts, http.MethodPost, "/v1/jobs", map[string]any{"puzzle": z}, &res)
+
+		if status != http.StatusServiceUnavailable || res.Error.Code != "too_many_jobs" {
+			t.Errorf("want status %v, got %v (%v)", http.StatusServiceUnavailable, status, res.Error)
+		}
+
+		// Only one job is running as there's only one worker.
+		var j1, j2 job
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			call(t, ts, http.MethodGet, "/v1/jobs/"+job1.ID, nil, &j1)
+			if j1.State == string(server.JobRunning) {
+				break
+			}
+		}
+		call(t, ts, http.MethodGet, "/v1/jobs/"+job2.ID, nil, &j2)
+
+		if j1.State != string(server.JobRunning) || j2.State != string(server.JobQueued) {
+			t.Errorf("want states %v and %v, got %v and %v", server.JobRunning, server.JobQueued, j1.State, j2.State)
+		}
+
+		// Canceling the running job frees the worker for the queued job.
+		call(t, ts, http.MethodDelete, "/v1/jobs/"+job1.ID, nil, nil)
+
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			call(t, ts, http.MethodGet, "/v1/jobs/"+job2.ID, nil, &j2)
+			if j2.State == string(server.JobRunning) {
+				break
+			}
+		}
+
+		if j2.State != string(server.JobRunning) {
+			t.Errorf("want state %v, got %v", server.JobRunning, j2.State)
+		}
+	})
+
+	t.Run("Finished Jobs don't count and expire", func(t *testing.T) {
+		t.Parallel()
+
+		params2, _ := params.GenerateParams(256, 2, big.NewInt(1_000))
+		z2, _ := puzzle.GeneratePuzzle(params2, big.NewInt(42))
+		ts := newTestServer(t, params2, &server.Options{MaxJobs: 1, JobRetention: time.Nanosecond})
+
+		// waitDone waits until the job with the id is done.
+		waitDone := func(id string) {
+			var j job
+			for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+				call(t, ts, http.MethodGet, "/v1/jobs/"+id, nil, &j)
+				if j.State == string(server.JobDone) {
+					return
+				}
+			}
+			t.Fatalf("want state %v, got %v", server.JobDone, j.State)
+		}
+
+		var ids []string
+		for range 3 {
+			var created job
+			status := call(t, ts, http.MethodPost, "/v1/jobs", map[string]any{"puzzle": z2}, &created)
+			if status != http.StatusAccepted {
+				t.Fatalf("want status %v, got %v", http.StatusAccepted, status)
+			}
+
+			waitDone(created.ID)
+			ids = append(ids, created.ID)
+		}
+
+		// Creating a job removes the finished jobs whose retention has passed.
+		if status := call(t, ts, http.MethodGet, "/v1/jobs/"+ids[0], nil, nil); status != http.StatusNotFound {
+			t.Errorf("want status %v, got %v", http.StatusNotFound, status)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		t.Parallel()
+
+		srv, _ := server.NewServer(params1, nil)
+		ts := httptest.NewServer(srv)
+		defer ts.Close()
+
+		var created job
+		call(t, ts, http.MethodPost, "/v1/jobs", map[string]any{"puzzle": z}, &created)
+
+		// Close cancels the running job.
+		srv.Close()
+
+		var j job
+		call(t, ts, http.MethodGet, "/v1/jobs/"+created.ID, nil, &j)
+
+		if j.State != string(server.JobFailed) || j.Error == "" {
+			t.Errorf("want state %v with an error, got %v", server.JobFailed, j)
+		}
+
+		var res apiError
+		status := call(t, ts, http.MethodPost, "/v1/jobs", map[string]any{"puzzle": z}, &res)
+
+		if status != http.StatusServiceUnavailable {
+			t.Errorf("want status %v, got %v", http.StatusServiceUnavailable, status)
+		}
+	})
+
+	t.Run("Error when params are invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err1 := server.NewServer(nil, nil)
+		_, err2 := server.NewServer(&params.Params{}, nil)
+
+		for _, err := range []error{err1, err2} {
+			if err == nil {
+				t.Error("want error, got nil")
+			}
+		}
+	})
+}
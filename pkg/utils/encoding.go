@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// EncodingVersion is the current version of the binary encoding.
+const EncodingVersion byte = 1
+
+// Types of binary encoded objects.
+const (
+	TypeParams        byte = 0x01
+	TypePuzzle        byte = 0x02
+	TypeRangeProof    byte = 0x03
+	TypeOpeningProof  byte = 0x04
+	TypeSolutionProof byte = 0x05
+	TypeEnvelope      byte = 0x06
+	TypeStreamHeader  byte = 0x07
+	TypeCheckpoint    byte = 0x08
+)
+
+// Signs of encoded big integers.
+const (
+	signPositive byte = 0x00
+	signNegative byte = 0x01
+)
+
+// AppendHeader appends the header which consists of the encoding version and
+// the type of the encoded object to b.
+func AppendHeader(b []byte, typ byte) []byte {
+	return append(b, EncodingVersion, typ)
+}
+
+// AppendUint64 appends the big-endian encoding of x to b.
+func AppendUint64(b []byte, x uint64) []byte {
+	return binary.BigEndian.AppendUint64(b, x)
+}
+
+// AppendBytes appends the data prefixed with its length (encoded as a 4 byte
+// big-endian integer) to b.
+func AppendBytes(b []byte, data []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+// AppendBigInt appends the canonical encoding of x to b.
+// The encoding consists of a sign byte followed by the length-prefixed
+// big-endian magnitude of x without leading zeros.
+func AppendBigInt(b []byte, x *big.Int) []byte {
+	sign := signPositive
+	if x.Sign() < 0 {
+		sign = signNegative
+	}
+
+	b = append(b, sign)
+
+	return AppendBytes(b, x.Bytes())
+}
+
+// Decoder is an instance of a strict decoder for data that was encoded via the
+// Append* functions.
+type Decoder struct {
+	data []byte
+}
+
+// NewDecoder creates a new instance of a decoder.
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{
+		data: data,
+	}
+}
+
+// ReadByte reads a single byte.
+// Returns an error if there's no data left.
+func (d *Decoder) ReadByte() (byte, error) {
+	if len(d.data) < 1 {
+		return 0, ErrUnexpectedEnd
+	}
+
+	b := d.data[0]
+	d.data = d.data[1:]
+
+	return b, nil
+}
+
+// ReadUint64 reads a big-endian encoded uint64.
+// Returns an error if there's not enough data left.
+func (d *Decoder) ReadUint64() (uint64, error) {
+	if len(d.data) < 8 {
+		return 0, ErrUnexpectedEnd
+	}
+
+	x := binary.BigEndian.Uint64(d.data)
+	d.data = d.data[8:]
+
+	return x, nil
+}
+
+// ReadBytes reads length-prefixed data.
+// Returns an error if there's not enough data left.
+func (d *Decoder) ReadBytes() ([]byte, error) {
+	if len(d.data) < 4 {
+		return nil, ErrUnexpectedEnd
+	}
+
+	length := binary.BigEndian.Uint32(d.data)
+	d.data = d.data[4:]
+
+	if uint64(len(d.data)) < uint64(length) {
+		return nil, ErrUnexpectedEnd
+	}
+
+	data := make([]byte, length)
+	copy(data, d.data)
+	d.data = d.data[length:]
+
+	return data, nil
+}
+
+// ReadBigInt reads a big integer that was encoded via AppendBigInt.
+// Returns an error if there's not enough data left or if the encoding isn't
+// canonical.
+func (d *Decoder) ReadBigInt() (*big.Int, error) {
+	sign, err := d.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	magnitude, err := d.ReadBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Leading zeros are not allowed.
+	if len(magnitude) > 0 && magnitude[0] == 0 {
+		return nil, ErrNonCanonicalEncoding
+	}
+
+	x := new(big.Int).SetBytes(magnitude)
+
+	switch sign {
+	case signPositive:
+		return x, nil
+	case signNegative:
+		// Zero must be encoded as a positive number.
+		if x.Sign() == 0 {
+			return nil, ErrNonCanonicalEncoding
+		}
+		return x.Neg(x), nil
+	default:
+		return nil, ErrNonCanonicalEncoding
+	}
+}
+
+// ReadHeader reads the header and checks that it matches the current encoding
+// version and the expected type.
+// Returns an error if there's not enough data left, if the version isn't
+// supported or if the type is unexpected.
+func (d *Decoder) ReadHeader(typ byte) error {
+	version, err := d.ReadByte()
+	if err != nil {
+		return err
+	}
+	if version != EncodingVersion {
+		return ErrUnsupportedVersion
+	}
+
+	actual, err := d.ReadByte()
+	if err != nil {
+		return err
+	}
+	if actual != typ {
+		return ErrUnexpectedType
+	}
+
+	return nil
+}
+
+// Len returns the number of bytes that are left to be read.
+func (d *Decoder) Len() int {
+	return len(d.data)
+}
+
+// Finish checks that all data was read.
+// Returns an error if there are trailing bytes.
+func (d *Decoder) Finish() error {
+	if len(d.data) != 0 {
+		return ErrTrailingBytes
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package utils_test
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+func TestEncoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Encode / Decode", func(t *testing.T) {
+		t.Parallel()
+
+		x1 := big.NewInt(0)
+		x2 := big.NewInt(1_000_000)
+		x3 := big.NewInt(-42)
+		data := []byte{1, 2, 3}
+
+		var b []byte
+		b = utils.AppendUint64(b, 7)
+		b = utils.AppendBytes(b, data)
+		b = utils.AppendBigInt(b, x1)
+		b = utils.AppendBigInt(b, x2)
+		b = utils.AppendBigInt(b, x3)
+
+		d := utils.NewDecoder(b)
+
+		got1, _ := d.ReadUint64()
+		if got1 != 7 {
+			t.Errorf("want %v, got %v", 7, got1)
+		}
+
+		got2, _ := d.ReadBytes()
+		if !slices.Equal(got2, data) {
+			t.Errorf("want %v, got %v", data, got2)
+		}
+
+		for _, want := range []*big.Int{x1, x2, x3} {
+			got, _ := d.ReadBigInt()
+			if got.Cmp(want) != 0 {
+				t.Errorf("want %v, got %v", want, got)
+			}
+		}
+
+		if err := d.Finish(); err != nil {
+			t.Errorf("want no error, got %v", err)
+		}
+	})
+
+	t.Run("Error when data ends unexpectedly", func(t *testing.T) {
+		t.Parallel()
+
+		b := utils.AppendBigInt(nil, big.NewInt(1_000_000))
+
+		_, err := utils.NewDecoder(b[:len(b)-1]).ReadBigInt()
+
+		if !errors.Is(err, utils.ErrUnexpectedEnd) {
+			t.Errorf("want error %v, got %v", utils.ErrUnexpectedEnd, err)
+		}
+	})
+
+	t.Run("Error when encoding has leading zeros", func(t *testing.T) {
+		t.Parallel()
+
+		b := utils.AppendBytes([]byte{0x00}, []byte{0x00, 0x01})
+
+		_, err := utils.NewDecoder(b).ReadBigInt()
+
+		if !errors.Is(err, utils.ErrNonCanonicalEncoding) {
+			t.Errorf("want error %v, got %v", utils.ErrNonCanonicalEncoding, err)
+		}
+	})
+
+	t.Run("Error when zero is encoded as negative number", func(t *testing.T) {
+		t.Parallel()
+
+		b := utils.AppendBytes([]byte{0x01}, nil)
+
+		_, err := utils.NewDecoder(b).ReadBigInt()
+
+		if !errors.Is(err, utils.ErrNonCanonicalEncoding) {
+			t.Errorf("want error %v, got %v", utils.ErrNonCanonicalEncoding, err)
+		}
+	})
+
+	t.Run("Error when data contains trailing bytes", func(t *testing.T) {
+		t.Parallel()
+
+		b := utils.AppendBigInt(nil, big.NewInt(42))
+		b = append(b, 0x00)
+
+		d := utils.NewDecoder(b)
+		_, _ = d.ReadBigInt()
+		err := d.Finish()
+
+		if !errors.Is(err, utils.ErrTrailingBytes) {
+			t.Errorf("want error %v, got %v", utils.ErrTrailingBytes, err)
+		}
+	})
+}
@@ -2,5 +2,21 @@ package utils
 
 import "fmt"
 
-// ErrInitializeAES is returned if AES can't be initialized.
-var ErrInitializeAES = fmt.Errorf("unable to initialize AES")
+var (
+	// ErrInitializeAES is returned if AES can't be initialized.
+	ErrInitializeAES = fmt.Errorf("unable to initialize AES")
+	// ErrUnexpectedEnd is returned if the encoded data ends unexpectedly.
+	ErrUnexpectedEnd = fmt.Errorf("unexpected end of encoded data")
+	// ErrNonCanonicalEncoding is returned if the encoded data isn't canonical.
+	ErrNonCanonicalEncoding = fmt.Errorf("non-canonical encoding")
+	// ErrUnsupportedVersion is returned if the encoding version is not supported.
+	ErrUnsupportedVersion = fmt.Errorf("unsupported encoding version")
+	// ErrUnexpectedType is returned if the encoded object has an unexpected type.
+	ErrUnexpectedType = fmt.Errorf("unexpected type of encoded object")
+	// ErrInvalidArmor is returned if the armored text doesn't contain a PEM block.
+	ErrInvalidArmor = fmt.Errorf("invalid armor")
+	// ErrDuplicateKey is returned if a JSON object contains a key more than once.
+	ErrDuplicateKey = fmt.Errorf("duplicate key in JSON object")
+	// ErrTrailingBytes is returned if the encoded data contains trailing bytes.
+	ErrTrailingBytes = fmt.Errorf("trailing bytes after encoded data")
+)
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// strausThreshold is the number of bases from which on Pippenger's bucket
+// method is used instead of Straus' interleaved window method.
+const strausThreshold = 16
+
+// strausWindow is the window size (in bits) of Straus' method.
+const strausWindow = 4
+
+// MultiExponentiate computes the product b_1^e_1 * ... * b_k^e_k mod m by
+// sharing the squarings between all exponentiations. Straus' interleaved
+// window method is used for a few bases and Pippenger's bucket method for many
+// bases.
+// Note: The caller needs to ensure that there are as many bases as exponents,
+// that the exponents are non-negative and that m > 1.
+func MultiExponentiate(bases, exps []*big.Int, m *big.Int) *big.Int {
+	maxBits := 0
+	for _, e := range exps {
+		maxBits = max(maxBits, e.BitLen())
+	}
+
+	if len(bases) < strausThreshold {
+		return straus(bases, exps, m, maxBits)
+	}
+
+	return pippenger(bases, exps, m, maxBits)
+}
+
+// straus computes the product b_1^e_1 * ... * b_k^e_k mod m via Straus'
+// interleaved window method which precomputes b_i^1, ..., b_i^(2^w - 1) for
+// every base and then processes the exponents window by window.
+func straus(bases, exps []*big.Int, m *big.Int, maxBits int) *big.Int {
+	w := strausWindow
+
+	// Precompute the powers of every base.
+	tables := make([][]*big.Int, len(bases))
+	for i, b := range bases {
+		table := make([]*big.Int, 1<<w)
+		table[1] = new(big.Int).Mod(b, m)
+		for j := 2; j < len(table); j++ {
+			in1 := new(big.Int).Mul(table[j-1], table[1]) // b^(j - 1) * b
+			table[j] = in1.Mod(in1, m)                    // b^j mod m
+		}
+		tables[i] = table
+	}
+
+	result := big.NewInt(1)
+	for window := (maxBits + w - 1) / w; window > 0; window-- {
+		for range w {
+			result.Mul(result, result).Mod(result, m) // result^2 mod m
+		}
+
+		offset := (window - 1) * w
+		for i, e := range exps {
+			if d := digit(e, offset, w); d != 0 {
+				result.Mul(result, tables[i][d]).Mod(result, m) // result * b_i^d mod m
+			}
+		}
+	}
+
+	return result.Mod(result, m)
+}
+
+// pippenger computes the product b_1^e_1 * ... * b_k^e_k mod m via Pippenger's
+// bucket method which sorts the bases into buckets based on the exponents'
+// digits in every window so that every base is multiplied only once per
+// window.
+func pippenger(bases, exps []*big.Int, m *big.Int, maxBits int) *big.Int {
+	// The window size grows logarithmically with the number of bases.
+	c := max(2, bits.Len(uint(len(bases)))-2)
+
+	buckets := make([]*big.Int, 1<<c)
+
+	result := big.NewInt(1)
+	for window := (maxBits + c - 1) / c; window > 0; window-- {
+		for range c {
+			result.Mul(result, result).Mod(result, m) // result^2 mod m
+		}
+
+		// Multiply every base into the bucket of its digit.
+		clear(buckets)
+		offset := (window - 1) * c
+		for i, e := range exps {
+			d := digit(e, offset, c)
+			if d == 0 {
+				continue
+			}
+
+			if buckets[d] == nil {
+				buckets[d] = new(big.Int).Mod(bases[i], m)
+				continue
+			}
+			buckets[d].Mul(buckets[d], bases[i]).Mod(buckets[d], m)
+		}
+
+		// Compute bucket_1^1 * ... * bucket_(2^c - 1)^(2^c - 1) via running
+		// products so that no exponentiation is needed.
+		var running *big.Int
+		var acc *big.Int
+		for d := len(buckets) - 1; d > 0; d-- {
+			if buckets[d] != nil {
+				if running == nil {
+					running = new(big.Int).Set(buckets[d])
+				} else {
+					running.Mul(running, buckets[d]).Mod(running, m)
+				}
+			}
+
+			if running == nil {
+				continue
+			}
+
+			if acc == nil {
+				acc = new(big.Int).Set(running)
+			} else {
+				acc.Mul(acc, running).Mod(acc, m)
+			}
+		}
+
+		if acc != nil {
+			result.Mul(result, acc).Mod(result, m)
+		}
+	}
+
+	return result.Mod(result, m)
+}
+
+// digit returns the w bits of e starting at the bit with the offset.
+func digit(e *big.Int, offset, w int) int {
+	d := 0
+	for j := w - 1; j >= 0; j-- {
+		d = d<<1 | int(e.Bit(offset+j))
+	}
+
+	return d
+}
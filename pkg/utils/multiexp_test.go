@@ -0,0 +1,58 @@
+package utils_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+func TestMultiExponentiate(t *testing.T) {
+	t.Parallel()
+
+	m, _ := rand.Prime(rand.Reader, 256)
+
+	// Straus' method is used for less than 16 bases and Pippenger's method for
+	// 16 and more bases.
+	for _, k := range []int{1, 2, 15, 16, 100} {
+		t.Run(fmt.Sprintf("%d Random Bases and Exponents", k), func(t *testing.T) {
+			t.Parallel()
+
+			bases := make([]*big.Int, k)
+			exps := make([]*big.Int, k)
+			expected := big.NewInt(1)
+
+			for i := range k {
+				bases[i], _ = rand.Int(rand.Reader, m)
+				exps[i], _ = rand.Int(rand.Reader, big.NewInt(1<<20))
+				if i%3 == 0 {
+					exps[i] = big.NewInt(0)
+				}
+
+				in1 := new(big.Int).Exp(bases[i], exps[i], m) // b_i^e_i mod m
+				expected.Mul(expected, in1).Mod(expected, m)
+			}
+
+			result := utils.MultiExponentiate(bases, exps, m)
+
+			if result.Cmp(expected) != 0 {
+				t.Errorf("want %v, got %v", expected, result)
+			}
+		})
+	}
+
+	t.Run("Zero Exponents", func(t *testing.T) {
+		t.Parallel()
+
+		bases := []*big.Int{big.NewInt(2), big.NewInt(3)}
+		exps := []*big.Int{big.NewInt(0), big.NewInt(0)}
+
+		result := utils.MultiExponentiate(bases, exps, m)
+
+		if result.Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("want %v, got %v", 1, result)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// BigIntToHex returns the canonical hex encoding of x which consists of an
+// optional "-" sign followed by the lowercase hex digits of the magnitude of x
+// without leading zeros (zero is encoded as "0").
+func BigIntToHex(x *big.Int) string {
+	return x.Text(16)
+}
+
+// HexToBigInt parses a big integer that was encoded via BigIntToHex.
+// Returns an error if the string isn't a canonical hex encoding.
+func HexToBigInt(s string) (*big.Int, error) {
+	digits := strings.TrimPrefix(s, "-")
+	negative := len(digits) != len(s)
+
+	if len(digits) == 0 {
+		return nil, ErrNonCanonicalEncoding
+	}
+
+	for _, c := range digits {
+		isDigit := c >= '0' && c <= '9'
+		isLetter := c >= 'a' && c <= 'f'
+		if !isDigit && !isLetter {
+			return nil, ErrNonCanonicalEncoding
+		}
+	}
+
+	// Leading zeros and negative zero are not allowed.
+	if digits[0] == '0' && (len(digits) > 1 || negative) {
+		return nil, ErrNonCanonicalEncoding
+	}
+
+	x, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		return nil, ErrNonCanonicalEncoding
+	}
+
+	return x, nil
+}
+
+// DecodeJSON decodes the JSON data into v while rejecting unknown fields,
+// duplicate keys and trailing data.
+// Returns an error if the data can't be decoded.
+func DecodeJSON(data []byte, v any) error {
+	if err := checkDuplicateKeys(json.NewDecoder(bytes.NewReader(data))); err != nil {
+		return err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.DisallowUnknownFields()
+
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := d.Token(); err != io.EOF {
+		return ErrTrailingBytes
+	}
+
+	return nil
+}
+
+// checkDuplicateKeys reads the next JSON value from the decoder and checks
+// that none of its objects (including the nested ones) contains a key twice.
+// Returns an error if the value can't be read or if it contains a duplicate
+// key.
+func checkDuplicateKeys(d *json.Decoder) error {
+	token, err := d.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		keys := make(map[string]bool)
+		for d.More() {
+			token, err := d.Token()
+			if err != nil {
+				return err
+			}
+
+			key := token.(string)
+			if keys[key] {
+				return ErrDuplicateKey
+			}
+			keys[key] = true
+
+			if err := checkDuplicateKeys(d); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for d.More() {
+			if err := checkDuplicateKeys(d); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Read the closing delimiter.
+	_, err = d.Token()
+
+	return err
+}
+
+// Armor wraps the binary encoded data in a PEM block of the type.
+func Armor(typ string, data []byte) []byte {
+	block := &pem.Block{
+		Type:  typ,
+		Bytes: data,
+	}
+
+	return pem.EncodeToMemory(block)
+}
+
+// Dearmor unwraps the binary encoded data from a PEM block of the type.
+// Returns an error if the text isn't a single PEM block of the type without
+// headers.
+func Dearmor(typ string, text []byte) ([]byte, error) {
+	block, rest := pem.Decode(text)
+	if block == nil {
+		return nil, ErrInvalidArmor
+	}
+
+	if block.Type != typ || len(block.Headers) != 0 {
+		return nil, ErrUnexpectedType
+	}
+
+	if len(bytes.TrimSpace(rest)) != 0 {
+		return nil, ErrTrailingBytes
+	}
+
+	return block.Bytes, nil
+}
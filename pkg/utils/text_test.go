@@ -0,0 +1,69 @@
+package utils_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/primefactor-io/lhtlp/pkg/utils"
+)
+
+func TestText(t *testing.T) {
+	t.Parallel()
+
+	t.Run("BigIntToHex / HexToBigInt", func(t *testing.T) {
+		t.Parallel()
+
+		values := map[string]*big.Int{
+			"0":   big.NewInt(0),
+			"2a":  big.NewInt(42),
+			"-2a": big.NewInt(-42),
+		}
+
+		for want, x := range values {
+			got := utils.BigIntToHex(x)
+			if got != want {
+				t.Errorf("want %v, got %v", want, got)
+			}
+
+			y, err := utils.HexToBigInt(got)
+			if err != nil || y.Cmp(x) != 0 {
+				t.Errorf("want %v, got %v (%v)", x, y, err)
+			}
+		}
+	})
+
+	t.Run("Error when hex is not canonical", func(t *testing.T) {
+		t.Parallel()
+
+		for _, s := range []string{"", "-", "-0", "00", "02a", "2A", "0x2a", "+2a", " 2a"} {
+			_, err := utils.HexToBigInt(s)
+
+			if !errors.Is(err, utils.ErrNonCanonicalEncoding) {
+				t.Errorf("%q want error %v, got %v", s, utils.ErrNonCanonicalEncoding, err)
+			}
+		}
+	})
+
+	t.Run("DecodeJSON", func(t *testing.T) {
+		t.Parallel()
+
+		var v map[string]any
+		if err := utils.DecodeJSON([]byte(`{"a":{"b":[1,{"c":2}]},"b":[{"a":1},{"a":2}]}`), &v); err != nil {
+			t.Errorf("want no error, got %v", err)
+		}
+	})
+
+	t.Run("Error when JSON contains duplicate keys", func(t *testing.T) {
+		t.Parallel()
+
+		for _, data := range []string{`{"a":1,"a":2}`, `{"a":{"b":1,"b":2}}`, `[{"a":1,"a":1}]`} {
+			var v any
+			err := utils.DecodeJSON([]byte(data), &v)
+
+			if !errors.Is(err, utils.ErrDuplicateKey) {
+				t.Errorf("%q want error %v, got %v", data, utils.ErrDuplicateKey, err)
+			}
+		}
+	})
+}
@@ -90,3 +90,14 @@ func Exponentiate(n *big.Int, x int) (*big.Int, *big.Int, *big.Int) {
 
 	return n1, n2, n3
 }
+
+// IsUnit checks if x is an element of {1, ..., m - 1} and coprime with m.
+func IsUnit(x, m *big.Int) bool {
+	if x == nil || x.Sign() <= 0 || x.Cmp(m) >= 0 {
+		return false
+	}
+
+	gcd := new(big.Int).GCD(nil, nil, x, m) // gcd(x, m)
+
+	return gcd.Cmp(big.NewInt(1)) == 0
+}
@@ -127,4 +127,30 @@ func TestUtils(t *testing.T) {
 			t.Errorf("want 2^33 = 8589934592, got %v", res3)
 		}
 	})
+
+	t.Run("IsUnit", func(t *testing.T) {
+		t.Parallel()
+
+		m := big.NewInt(15)
+
+		tests := []struct {
+			x    *big.Int
+			want bool
+		}{
+			{nil, false},
+			{big.NewInt(-1), false},
+			{big.NewInt(0), false},
+			{big.NewInt(1), true},
+			{big.NewInt(2), true},
+			{big.NewInt(3), false},
+			{big.NewInt(14), true},
+			{big.NewInt(15), false},
+		}
+
+		for _, tt := range tests {
+			if got := utils.IsUnit(tt.x, m); got != tt.want {
+				t.Errorf("%v: want %v, got %v", tt.x, tt.want, got)
+			}
+		}
+	})
 }